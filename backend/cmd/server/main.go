@@ -1,18 +1,24 @@
 // Package main is the entry point for the Songify backend server.
-// It initializes logging, configuration, database, and starts the HTTP server.
+// It initializes logging, configuration, database, and starts the HTTP server,
+// shutting it down gracefully on SIGINT or SIGTERM.
 package main
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/getsentry/sentry-go"
 	"github.com/songify/backend/internal/broker"
 	"github.com/songify/backend/internal/config"
 	"github.com/songify/backend/internal/database"
-	"github.com/songify/backend/internal/db"
 	"github.com/songify/backend/internal/logging"
 	"github.com/songify/backend/internal/router"
 	sentryscrub "github.com/songify/backend/internal/sentry"
@@ -51,7 +57,11 @@ func main() {
 	}
 
 	// Initialize database
-	sqlDB, err := database.New(cfg.DatabasePath)
+	dsn := cfg.DatabasePath
+	if cfg.DatabaseDriver == database.DriverPostgres {
+		dsn = cfg.DatabaseURL
+	}
+	sqlDB, err := database.Open(cfg.DatabaseDriver, dsn)
 	if err != nil {
 		slog.Error("failed to connect to database", slog.String("error", err.Error()))
 		os.Exit(1)
@@ -65,21 +75,88 @@ func main() {
 	}
 
 	// Initialize queries
-	queries := db.New(sqlDB)
+	queries := database.NewQueries(sqlDB)
 
 	// Create event broker for SSE
-	eventBroker := broker.New()
+	eventBroker, err := newBroker(cfg, sqlDB)
+	if err != nil {
+		slog.Error("failed to start event broker", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	defer eventBroker.Close()
+
+	// Cancelled on SIGINT or SIGTERM, which stops background workers and
+	// ends SSE streams
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
 	// Create router
-	r := router.New(cfg, queries, eventBroker)
+	rt, err := router.New(ctx, cfg, sqlDB, queries, eventBroker)
+	if err != nil {
+		slog.Error("failed to create router", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
 
 	// Start server
 	addr := ":" + cfg.Port
 	slog.Info("starting server", slog.String("addr", addr))
 	slog.Info("frontend should connect to", slog.String("url", "http://localhost"+addr))
 
-	if err := http.ListenAndServe(addr, r); err != nil {
+	srv := &http.Server{Addr: addr, Handler: rt}
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
 		slog.Error("server failed", slog.String("error", err.Error()))
 		os.Exit(1)
+	case <-ctx.Done():
+	}
+	// A second signal kills the process without waiting
+	stop()
+
+	// Let in-flight requests finish, then stop what they depend on. The
+	// deferred closes run afterwards: the event broker, then the database.
+	slog.Info("shutting down", slog.Duration("timeout", cfg.ShutdownTimeout))
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		slog.Error("failed to finish in-flight requests", slog.String("error", err.Error()))
+		srv.Close()
+	}
+	if err := rt.Close(); err != nil {
+		slog.Error("failed to close router", slog.String("error", err.Error()))
+	}
+	slog.Info("server stopped")
+}
+
+// newBroker creates the event broker selected by EVENT_BROKER. The Redis and
+// Postgres brokers deliver events to SSE clients on every instance.
+func newBroker(cfg *config.Config, sqlDB *sql.DB) (broker.Broker, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	switch cfg.EventBroker {
+	case "memory":
+		return broker.New(), nil
+	case "redis":
+		b, err := broker.NewRedis(ctx, cfg.RedisURL)
+		if err != nil {
+			return nil, err
+		}
+		return b, nil
+	case "postgres":
+		if cfg.DatabaseDriver != database.DriverPostgres {
+			return nil, errors.New("EVENT_BROKER=postgres requires DATABASE_DRIVER=postgres")
+		}
+		b, err := broker.NewPostgres(ctx, sqlDB, cfg.DatabaseURL)
+		if err != nil {
+			return nil, err
+		}
+		return b, nil
+	default:
+		return nil, fmt.Errorf("unknown event broker %q", cfg.EventBroker)
 	}
 }
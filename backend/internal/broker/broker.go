@@ -1,5 +1,7 @@
-// Package broker provides an in-memory pub/sub mechanism scoped by session ID.
-// It is used to notify SSE connections when song requests change.
+// Package broker provides a pub/sub mechanism scoped by session ID. It is used
+// to notify SSE connections when song requests change. The in-memory broker
+// serves a single instance; the Redis and Postgres brokers fan events out to
+// every instance sharing the same server.
 package broker
 
 import "sync"
@@ -7,21 +9,34 @@ import "sync"
 // Broker is a session-scoped pub/sub hub. Subscribers receive a signal (empty struct)
 // whenever Publish is called for their session. Channels are buffered to 1 so
 // multiple rapid publishes coalesce into a single notification.
-type Broker struct {
+type Broker interface {
+	// Subscribe returns a buffered(1) channel that receives a signal each
+	// time Publish is called for the given session ID.
+	Subscribe(sessionID string) chan struct{}
+	// Unsubscribe removes a channel from the session's subscriber set.
+	Unsubscribe(sessionID string, ch chan struct{})
+	// Publish signals every subscriber for the given session.
+	Publish(sessionID string)
+	// Close stops delivering events from other instances.
+	Close() error
+}
+
+// Memory is a Broker that delivers events within the current process.
+type Memory struct {
 	mu   sync.Mutex
 	subs map[string]map[chan struct{}]struct{}
 }
 
-// New creates a ready-to-use Broker.
-func New() *Broker {
-	return &Broker{
+// New creates a ready-to-use in-memory Broker.
+func New() *Memory {
+	return &Memory{
 		subs: make(map[string]map[chan struct{}]struct{}),
 	}
 }
 
 // Subscribe returns a buffered(1) channel that receives a signal each time
 // Publish is called for the given session ID.
-func (b *Broker) Subscribe(sessionID string) chan struct{} {
+func (b *Memory) Subscribe(sessionID string) chan struct{} {
 	ch := make(chan struct{}, 1)
 	b.mu.Lock()
 	defer b.mu.Unlock()
@@ -34,7 +49,7 @@ func (b *Broker) Subscribe(sessionID string) chan struct{} {
 
 // Unsubscribe removes a channel from the session's subscriber set.
 // If the session has no remaining subscribers, the entry is cleaned up.
-func (b *Broker) Unsubscribe(sessionID string, ch chan struct{}) {
+func (b *Memory) Unsubscribe(sessionID string, ch chan struct{}) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 	if subs, ok := b.subs[sessionID]; ok {
@@ -47,7 +62,7 @@ func (b *Broker) Unsubscribe(sessionID string, ch chan struct{}) {
 
 // Publish sends a non-blocking signal to every subscriber for the given session.
 // Because channels are buffered to 1, a pending unread signal is not duplicated.
-func (b *Broker) Publish(sessionID string) {
+func (b *Memory) Publish(sessionID string) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 	for ch := range b.subs[sessionID] {
@@ -57,3 +72,22 @@ func (b *Broker) Publish(sessionID string) {
 		}
 	}
 }
+
+// Close does nothing; the in-memory broker has no connections to release.
+func (b *Memory) Close() error {
+	return nil
+}
+
+// publishAll signals every subscriber of every session.
+func (b *Memory) publishAll() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, subs := range b.subs {
+		for ch := range subs {
+			select {
+			case ch <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package broker
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// publishTimeout bounds how long Publish waits on the shared server.
+const publishTimeout = 2 * time.Second
+
+// fanout holds what the Redis and Postgres brokers share. Events reach local
+// subscribers straight away and are relayed to other instances as
+// "<instance ID> <session ID>" messages; an instance ignores its own.
+type fanout struct {
+	*Memory
+	instanceID string
+}
+
+func newFanout() fanout {
+	return fanout{Memory: New(), instanceID: uuid.NewString()}
+}
+
+// message encodes an event for other instances.
+func (f fanout) message(sessionID string) string {
+	return f.instanceID + " " + sessionID
+}
+
+// deliver passes an event from another instance to local subscribers.
+func (f fanout) deliver(msg string) {
+	instanceID, sessionID, ok := strings.Cut(msg, " ")
+	if !ok || instanceID == f.instanceID {
+		return
+	}
+	f.Memory.Publish(sessionID)
+}
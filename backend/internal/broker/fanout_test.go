@@ -0,0 +1,90 @@
+package broker
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/songify/backend/internal/database"
+)
+
+// testFanout checks that events published on one instance reach subscribers
+// on both.
+func testFanout(t *testing.T, a, b Broker) {
+	t.Helper()
+	chA := a.Subscribe("sess1")
+	defer a.Unsubscribe("sess1", chA)
+	chB := b.Subscribe("sess1")
+	defer b.Unsubscribe("sess1", chB)
+	other := b.Subscribe("sess2")
+	defer b.Unsubscribe("sess2", other)
+
+	a.Publish("sess1")
+
+	for name, ch := range map[string]chan struct{}{"publishing instance": chA, "other instance": chB} {
+		select {
+		case <-ch:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("%s subscriber did not receive the event", name)
+		}
+	}
+	select {
+	case <-other:
+		t.Fatal("sess2 subscriber should not receive signal from sess1 publish")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestRedisFanout(t *testing.T) {
+	server := miniredis.RunT(t)
+	ctx := context.Background()
+
+	a, err := NewRedis(ctx, "redis://"+server.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close()
+	b, err := NewRedis(ctx, "redis://"+server.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+
+	testFanout(t, a, b)
+}
+
+func TestRedisUnavailable(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if _, err := NewRedis(ctx, "redis://127.0.0.1:1"); err == nil {
+		t.Fatal("NewRedis succeeded without a server, want an error")
+	}
+}
+
+func TestPostgresFanout(t *testing.T) {
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	ctx := context.Background()
+	sqlDB, err := database.NewPostgres(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sqlDB.Close()
+
+	a, err := NewPostgres(ctx, sqlDB, url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close()
+	b, err := NewPostgres(ctx, sqlDB, url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+
+	testFanout(t, a, b)
+}
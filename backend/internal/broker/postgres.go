@@ -0,0 +1,115 @@
+package broker
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	// postgresChannel is the LISTEN/NOTIFY channel events are relayed on.
+	postgresChannel = "songify_events"
+	// listenRetryDelay is how long to wait before reconnecting a lost
+	// LISTEN connection.
+	listenRetryDelay = 5 * time.Second
+)
+
+// Postgres is a Broker that relays events between instances with Postgres
+// LISTEN/NOTIFY.
+type Postgres struct {
+	fanout
+	db     *sql.DB
+	url    string
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewPostgres starts relaying events from other instances. Events are sent
+// through db and received on a dedicated connection to url, which must name
+// the same database.
+func NewPostgres(ctx context.Context, db *sql.DB, url string) (*Postgres, error) {
+	conn, err := listen(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	b := &Postgres{
+		fanout: newFanout(),
+		db:     db,
+		url:    url,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	go b.receive(runCtx, conn)
+	return b, nil
+}
+
+// listen opens a connection subscribed to the events channel.
+func listen(ctx context.Context, url string) (*pgx.Conn, error) {
+	conn, err := pgx.Connect(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to postgres: %w", err)
+	}
+	if _, err := conn.Exec(ctx, "LISTEN "+postgresChannel); err != nil {
+		conn.Close(ctx)
+		return nil, fmt.Errorf("failed to listen for events: %w", err)
+	}
+	return conn, nil
+}
+
+// receive delivers events from other instances until ctx is cancelled,
+// reconnecting when the connection drops. Events sent while disconnected are
+// lost, so every local subscriber is signalled after reconnecting.
+func (b *Postgres) receive(ctx context.Context, conn *pgx.Conn) {
+	defer close(b.done)
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err == nil {
+			b.deliver(n.Payload)
+			continue
+		}
+		conn.Close(context.Background())
+		if ctx.Err() != nil {
+			return
+		}
+		slog.Error("broker: lost postgres listen connection", slog.String("error", err.Error()))
+
+		for conn = nil; conn == nil; {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(listenRetryDelay):
+			}
+			if conn, err = listen(ctx, b.url); err != nil {
+				slog.Error("broker: failed to reconnect to postgres", slog.String("error", err.Error()))
+			}
+		}
+		b.publishAll()
+	}
+}
+
+// Publish signals local subscribers and relays the event to other instances.
+func (b *Postgres) Publish(sessionID string) {
+	b.Memory.Publish(sessionID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+	defer cancel()
+	if _, err := b.db.ExecContext(ctx, "SELECT pg_notify($1, $2)", postgresChannel, b.message(sessionID)); err != nil {
+		slog.Error("broker: failed to notify postgres",
+			slog.String("session_id", sessionID),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// Close stops relaying events and closes the LISTEN connection.
+func (b *Postgres) Close() error {
+	b.cancel()
+	<-b.done
+	return nil
+}
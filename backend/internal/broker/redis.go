@@ -0,0 +1,77 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisChannel is the Redis pub/sub channel events are relayed on.
+const redisChannel = "songify:events"
+
+// Redis is a Broker that relays events between instances over Redis pub/sub.
+type Redis struct {
+	fanout
+	client *redis.Client
+	pubsub *redis.PubSub
+	done   chan struct{}
+}
+
+// NewRedis connects to the Redis server at url (e.g. redis://localhost:6379/0)
+// and starts relaying events from other instances.
+func NewRedis(ctx context.Context, url string) (*Redis, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse redis URL: %w", err)
+	}
+	client := redis.NewClient(opts)
+
+	pubsub := client.Subscribe(ctx, redisChannel)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		client.Close()
+		return nil, fmt.Errorf("failed to subscribe to redis: %w", err)
+	}
+
+	b := &Redis{
+		fanout: newFanout(),
+		client: client,
+		pubsub: pubsub,
+		done:   make(chan struct{}),
+	}
+	go b.receive()
+	return b, nil
+}
+
+// receive delivers events from other instances until the subscription is
+// closed. The client reconnects and resubscribes on its own.
+func (b *Redis) receive() {
+	defer close(b.done)
+	for msg := range b.pubsub.Channel() {
+		b.deliver(msg.Payload)
+	}
+}
+
+// Publish signals local subscribers and relays the event to other instances.
+func (b *Redis) Publish(sessionID string) {
+	b.Memory.Publish(sessionID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+	defer cancel()
+	if err := b.client.Publish(ctx, redisChannel, b.message(sessionID)).Err(); err != nil {
+		slog.Error("broker: failed to publish to redis",
+			slog.String("session_id", sessionID),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// Close stops relaying events and disconnects from Redis.
+func (b *Redis) Close() error {
+	err := b.pubsub.Close()
+	<-b.done
+	return errors.Join(err, b.client.Close())
+}
@@ -12,16 +12,35 @@ import (
 // Config holds all application settings loaded from environment variables.
 type Config struct {
 	Port                  string
+	DatabaseDriver        string
 	DatabasePath          string
+	DatabaseURL           string
+	EventBroker           string
+	RedisURL              string
 	JWTSecret             string
 	AdminPortalPassword   string
 	SpotifyClientID       string
 	SpotifyClientSecret   string
 	YouTubeAPIKey         string
+	YouTubeDailyQuota     int
+	YouTubeQuotaSoftPercent int
+	SubsonicURL           string
+	SubsonicUsername      string
+	SubsonicPassword      string
+	SubsonicQueueMode     string
+	SearchCacheSize       int
+	SearchCacheTTL        time.Duration
 	AdminTokenDuration    time.Duration
 	FriendTokenDuration   time.Duration
+	UndoWindow            time.Duration
+	SchedulerInterval     time.Duration
+	OutboxInterval        time.Duration
+	ShutdownTimeout       time.Duration
 	RateLimitPerMinute        int
 	AuthRateLimitPerMinute    int
+	SessionSearchRateLimitPerMinute int
+	SessionSearchRateLimitKey string
+	RateLimitStore            string
 	CORSAllowedOrigins        []string
 	TrustedProxies        []string
 	SentryDSN             string
@@ -33,16 +52,35 @@ type Config struct {
 func Load() *Config {
 	return &Config{
 		Port:                  getEnv("PORT", "8080"),
+		DatabaseDriver:        getEnv("DATABASE_DRIVER", "sqlite"),
 		DatabasePath:          getEnv("DATABASE_PATH", "./songify.db"),
+		DatabaseURL:           getEnv("DATABASE_URL", ""),
+		EventBroker:           getEnv("EVENT_BROKER", "memory"),
+		RedisURL:              getEnv("REDIS_URL", "redis://localhost:6379/0"),
 		JWTSecret:             getEnv("JWT_SECRET", "change-me-in-production"),  // #nosec G101 -- intentional dev default
 		AdminPortalPassword:   getEnv("ADMIN_PORTAL_PASSWORD", "admin123"),     // #nosec G101 -- intentional dev default
 		SpotifyClientID:       getEnv("SPOTIFY_CLIENT_ID", ""),
 		SpotifyClientSecret:   getEnv("SPOTIFY_CLIENT_SECRET", ""),
 		YouTubeAPIKey:         getEnv("YOUTUBE_API_KEY", ""),
+		YouTubeDailyQuota:     getIntEnv("YOUTUBE_DAILY_QUOTA", 10000),
+		YouTubeQuotaSoftPercent: getIntEnv("YOUTUBE_QUOTA_SOFT_PERCENT", 90),
+		SubsonicURL:           getEnv("SUBSONIC_URL", ""),
+		SubsonicUsername:      getEnv("SUBSONIC_USERNAME", ""),
+		SubsonicPassword:      getEnv("SUBSONIC_PASSWORD", ""),
+		SubsonicQueueMode:     getEnv("SUBSONIC_QUEUE_MODE", "jukebox"),
+		SearchCacheSize:       getIntEnv("SEARCH_CACHE_SIZE", 1000),
+		SearchCacheTTL:        getDurationEnv("SEARCH_CACHE_TTL", 15*time.Minute),
 		AdminTokenDuration:    getDurationEnv("ADMIN_TOKEN_DURATION", 7*24*time.Hour),
 		FriendTokenDuration:   getDurationEnv("FRIEND_TOKEN_DURATION", 12*time.Hour),
+		UndoWindow:            getDurationEnv("UNDO_WINDOW", 5*time.Minute),
+		SchedulerInterval:     getDurationEnv("SCHEDULER_INTERVAL", time.Second),
+		OutboxInterval:        getDurationEnv("OUTBOX_INTERVAL", time.Second),
+		ShutdownTimeout:       getDurationEnv("SHUTDOWN_TIMEOUT", 15*time.Second),
 		RateLimitPerMinute:        getIntEnv("RATE_LIMIT_PER_MINUTE", 10),
 		AuthRateLimitPerMinute:    getIntEnv("AUTH_RATE_LIMIT_PER_MINUTE", 5),
+		SessionSearchRateLimitPerMinute: getIntEnv("SESSION_SEARCH_RATE_LIMIT_PER_MINUTE", 20),
+		SessionSearchRateLimitKey: getEnv("SESSION_SEARCH_RATE_LIMIT_KEY", "identity"),
+		RateLimitStore:            getEnv("RATE_LIMIT_STORE", "memory"),
 		CORSAllowedOrigins:    []string{"http://localhost:5173", "http://localhost:3000"},
 		TrustedProxies:        getStringSliceEnv("TRUSTED_PROXIES"),
 		SentryDSN:             getEnv("SENTRY_DSN", ""),
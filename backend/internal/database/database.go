@@ -1,6 +1,6 @@
-// Package database provides SQLite database initialization and migration support.
-// It uses embedded SQL migration files and the golang-migrate library to manage
-// schema versions.
+// Package database provides database initialization and migration support for
+// SQLite and PostgreSQL. It uses embedded SQL migration files, kept per
+// dialect, and the golang-migrate library to manage schema versions.
 package database
 
 import (
@@ -8,20 +8,59 @@ import (
 	"embed"
 	"fmt"
 	"log"
+	"strings"
 
 	"github.com/golang-migrate/migrate/v4"
+	migratedb "github.com/golang-migrate/migrate/v4/database"
+	migratepgx "github.com/golang-migrate/migrate/v4/database/pgx/v5"
 	"github.com/golang-migrate/migrate/v4/database/sqlite"
 	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/songify/backend/internal/db"
+	"github.com/songify/backend/internal/db/pgdb"
 	_ "modernc.org/sqlite"
 )
 
-//go:embed migrations/*.sql
+// Database drivers accepted by Open.
+const (
+	DriverSQLite   = "sqlite"
+	DriverPostgres = "postgres"
+)
+
+//go:embed migrations/sqlite/*.sql migrations/postgres/*.sql
 var migrationsFS embed.FS
 
+// Open connects to the database for the given driver. dsn is a file path for
+// SQLite and a connection URL for PostgreSQL.
+func Open(driver, dsn string) (*sql.DB, error) {
+	switch driver {
+	case DriverSQLite:
+		return New(dsn)
+	case DriverPostgres:
+		return NewPostgres(dsn)
+	default:
+		return nil, fmt.Errorf("unknown database driver %q", driver)
+	}
+}
+
+// NewQueries returns the generated queries for the database's dialect.
+func NewQueries(sqlDB *sql.DB) db.Store {
+	if isPostgres(sqlDB) {
+		return postgresQueries{pgdb.New(sqlDB)}
+	}
+	return db.New(sqlDB)
+}
+
 // New creates and configures a new SQLite database connection.
 // It enables foreign key constraints and WAL mode for better concurrency.
 func New(dbPath string) (*sql.DB, error) {
-	db, err := sql.Open("sqlite", dbPath)
+	// Wait for another connection's write lock instead of failing with
+	// SQLITE_BUSY, as concurrent outbox workers otherwise would. Set in the
+	// DSN so it applies to every pooled connection.
+	sep := "?"
+	if strings.Contains(dbPath, "?") {
+		sep = "&"
+	}
+	db, err := sql.Open("sqlite", dbPath+sep+"_pragma=busy_timeout(5000)")
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
@@ -39,21 +78,32 @@ func New(dbPath string) (*sql.DB, error) {
 	return db, nil
 }
 
-// RunMigrations applies any pending database migrations from the embedded
-// migrations directory. It's safe to call multiple times; already-applied
-// migrations are skipped.
+// RunMigrations applies any pending database migrations for the database's
+// dialect from the embedded migrations directory. It's safe to call multiple
+// times; already-applied migrations are skipped.
 func RunMigrations(db *sql.DB) error {
-	driver, err := sqlite.WithInstance(db, &sqlite.Config{})
+	var (
+		driver  migratedb.Driver
+		dialect string
+		err     error
+	)
+	if isPostgres(db) {
+		dialect = DriverPostgres
+		driver, err = migratepgx.WithInstance(db, &migratepgx.Config{})
+	} else {
+		dialect = DriverSQLite
+		driver, err = sqlite.WithInstance(db, &sqlite.Config{})
+	}
 	if err != nil {
 		return fmt.Errorf("failed to create migration driver: %w", err)
 	}
 
-	source, err := iofs.New(migrationsFS, "migrations")
+	source, err := iofs.New(migrationsFS, "migrations/"+dialect)
 	if err != nil {
 		return fmt.Errorf("failed to create migration source: %w", err)
 	}
 
-	m, err := migrate.NewWithInstance("iofs", source, "sqlite", driver)
+	m, err := migrate.NewWithInstance("iofs", source, dialect, driver)
 	if err != nil {
 		return fmt.Errorf("failed to create migrator: %w", err)
 	}
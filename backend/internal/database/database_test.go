@@ -0,0 +1,44 @@
+package database
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/songify/backend/internal/db"
+)
+
+func TestOpen(t *testing.T) {
+	if _, err := Open("mysql", "songify"); err == nil {
+		t.Error("Open with an unknown driver succeeded, want an error")
+	}
+
+	sqlDB, err := Open(DriverSQLite, filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sqlDB.Close()
+	if isPostgres(sqlDB) {
+		t.Error("SQLite database detected as Postgres")
+	}
+	if _, ok := NewQueries(sqlDB).(db.Querier); !ok {
+		t.Error("SQLite database did not get the SQLite queries")
+	}
+	if err := RunMigrations(sqlDB); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := pgx.ParseConfig("postgres://songify@localhost:5432/songify")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pgDB := stdlib.OpenDB(*config)
+	defer pgDB.Close()
+	if !isPostgres(pgDB) {
+		t.Error("Postgres database not detected")
+	}
+	if _, ok := NewQueries(pgDB).(postgresQueries); !ok {
+		t.Error("Postgres database did not get the Postgres queries")
+	}
+}
@@ -0,0 +1,39 @@
+package dbtest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/songify/backend/internal/db"
+)
+
+// Session creates a YouTube session administered by "admin". Its friend
+// access key is "happy-tiger-" followed by the ID.
+func Session(t testing.TB, q db.Querier, id string) db.Session {
+	t.Helper()
+	session, err := q.CreateSession(context.Background(), db.CreateSessionParams{
+		ID: id, DisplayName: "Party", AdminName: "admin", AdminPasswordHash: "x",
+		FriendAccessKey: "happy-tiger-" + id, MusicService: "youtube",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return session
+}
+
+// SongRequests creates a pending request in a session for each track ID,
+// which doubles as the track's name and URI.
+func SongRequests(t testing.TB, q db.Querier, sessionID string, trackIDs ...string) []db.SongRequest {
+	t.Helper()
+	requests := make([]db.SongRequest, 0, len(trackIDs))
+	for _, trackID := range trackIDs {
+		sr, err := q.CreateSongRequest(context.Background(), db.CreateSongRequestParams{
+			SessionID: sessionID, ExternalTrackID: trackID, TrackName: trackID, ArtistNames: "x", ExternalUri: trackID,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		requests = append(requests, sr)
+	}
+	return requests
+}
@@ -0,0 +1,12 @@
+ALTER TABLE sessions DROP COLUMN lounge_target;
+
+ALTER TABLE sessions ADD COLUMN lounge_screen_id TEXT;
+ALTER TABLE sessions ADD COLUMN lounge_token TEXT;
+ALTER TABLE sessions ADD COLUMN lounge_screen_name TEXT;
+
+UPDATE sessions SET
+    lounge_screen_id = (SELECT screen_id FROM lounge_screens WHERE lounge_screens.session_id = sessions.id AND is_primary),
+    lounge_token = (SELECT lounge_token FROM lounge_screens WHERE lounge_screens.session_id = sessions.id AND is_primary),
+    lounge_screen_name = (SELECT screen_name FROM lounge_screens WHERE lounge_screens.session_id = sessions.id AND is_primary);
+
+DROP TABLE lounge_screens;
@@ -0,0 +1,23 @@
+CREATE TABLE lounge_screens (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    session_id TEXT NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+    screen_id TEXT NOT NULL,
+    lounge_token TEXT NOT NULL,
+    screen_name TEXT,
+    is_primary BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (session_id, screen_id)
+);
+
+CREATE INDEX idx_lounge_screens_session_id ON lounge_screens(session_id);
+
+INSERT INTO lounge_screens (session_id, screen_id, lounge_token, screen_name, is_primary)
+SELECT id, lounge_screen_id, lounge_token, lounge_screen_name, TRUE
+FROM sessions
+WHERE lounge_screen_id IS NOT NULL AND lounge_token IS NOT NULL;
+
+ALTER TABLE sessions DROP COLUMN lounge_screen_id;
+ALTER TABLE sessions DROP COLUMN lounge_token;
+ALTER TABLE sessions DROP COLUMN lounge_screen_name;
+
+ALTER TABLE sessions ADD COLUMN lounge_target TEXT NOT NULL DEFAULT 'primary';
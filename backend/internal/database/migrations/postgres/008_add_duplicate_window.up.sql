@@ -0,0 +1 @@
+ALTER TABLE sessions ADD COLUMN duplicate_window_minutes BIGINT;
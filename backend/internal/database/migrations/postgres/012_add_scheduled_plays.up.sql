@@ -0,0 +1,17 @@
+-- Approved requests an admin has scheduled to play at a wall-clock time or
+-- after the current track. Rows stay after firing so the admin can see what
+-- happened.
+CREATE TABLE scheduled_plays (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    session_id TEXT NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+    request_id BIGINT NOT NULL REFERENCES song_requests(id) ON DELETE CASCADE,
+    mode TEXT NOT NULL CHECK (mode IN ('at', 'after_current')),
+    play_at TIMESTAMPTZ,
+    status TEXT NOT NULL DEFAULT 'scheduled' CHECK (status IN ('scheduled', 'fired', 'failed', 'cancelled')),
+    error TEXT,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    fired_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_scheduled_plays_status ON scheduled_plays(status);
+CREATE INDEX idx_scheduled_plays_session_id ON scheduled_plays(session_id);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_lounge_commands_status;
+DROP TABLE IF EXISTS lounge_commands;
+DROP TABLE IF EXISTS lounge_leases;
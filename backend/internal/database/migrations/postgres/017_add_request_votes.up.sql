@@ -0,0 +1,9 @@
+-- Guest votes on pending requests. A request's score is the sum of its
+-- votes and can be used as an auto-moderation condition.
+CREATE TABLE request_votes (
+    request_id BIGINT NOT NULL REFERENCES song_requests(id) ON DELETE CASCADE,
+    voter TEXT NOT NULL,
+    value BIGINT NOT NULL CHECK (value IN (-1, 1)),
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (request_id, voter)
+);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_prohibited_patterns_session_id;
+DROP INDEX IF EXISTS idx_song_requests_status;
+DROP INDEX IF EXISTS idx_song_requests_session_id;
+DROP INDEX IF EXISTS idx_sessions_friend_access_key;
+DROP TABLE IF EXISTS song_requests;
+DROP TABLE IF EXISTS prohibited_patterns;
+DROP TABLE IF EXISTS sessions;
@@ -0,0 +1 @@
+ALTER TABLE sessions DROP COLUMN spotify_playlist_name;
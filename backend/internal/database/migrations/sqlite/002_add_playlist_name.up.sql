@@ -0,0 +1 @@
+ALTER TABLE sessions ADD COLUMN spotify_playlist_name TEXT;
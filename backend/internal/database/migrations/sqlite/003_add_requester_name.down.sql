@@ -0,0 +1 @@
+ALTER TABLE song_requests DROP COLUMN requester_name;
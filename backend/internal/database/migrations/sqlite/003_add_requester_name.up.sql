@@ -0,0 +1 @@
+ALTER TABLE song_requests ADD COLUMN requester_name TEXT;
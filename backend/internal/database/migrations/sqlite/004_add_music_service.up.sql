@@ -0,0 +1 @@
+ALTER TABLE sessions ADD COLUMN music_service TEXT NOT NULL DEFAULT 'spotify';
@@ -0,0 +1,2 @@
+ALTER TABLE song_requests RENAME COLUMN external_track_id TO spotify_track_id;
+ALTER TABLE song_requests RENAME COLUMN external_uri TO spotify_uri;
@@ -0,0 +1,2 @@
+ALTER TABLE song_requests RENAME COLUMN spotify_track_id TO external_track_id;
+ALTER TABLE song_requests RENAME COLUMN spotify_uri TO external_uri;
@@ -0,0 +1,3 @@
+ALTER TABLE sessions DROP COLUMN lounge_screen_id;
+ALTER TABLE sessions DROP COLUMN lounge_token;
+ALTER TABLE sessions DROP COLUMN lounge_screen_name;
@@ -0,0 +1,3 @@
+ALTER TABLE sessions ADD COLUMN lounge_screen_id TEXT;
+ALTER TABLE sessions ADD COLUMN lounge_token TEXT;
+ALTER TABLE sessions ADD COLUMN lounge_screen_name TEXT;
@@ -0,0 +1,2 @@
+ALTER TABLE song_requests DROP COLUMN note_hidden;
+ALTER TABLE song_requests DROP COLUMN note;
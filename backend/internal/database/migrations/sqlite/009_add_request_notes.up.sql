@@ -0,0 +1,2 @@
+ALTER TABLE song_requests ADD COLUMN note TEXT;
+ALTER TABLE song_requests ADD COLUMN note_hidden BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,3 @@
+ALTER TABLE song_requests DROP COLUMN processed_by;
+
+DROP TABLE auto_moderation_rules;
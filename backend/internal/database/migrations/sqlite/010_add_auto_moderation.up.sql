@@ -0,0 +1,15 @@
+CREATE TABLE auto_moderation_rules (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    session_id TEXT NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    action TEXT NOT NULL CHECK (action IN ('approve', 'reject')),
+    reason TEXT,
+    conditions TEXT NOT NULL DEFAULT '{}',
+    position INTEGER NOT NULL DEFAULT 0,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_auto_moderation_rules_session_id ON auto_moderation_rules(session_id);
+
+ALTER TABLE song_requests ADD COLUMN processed_by TEXT;
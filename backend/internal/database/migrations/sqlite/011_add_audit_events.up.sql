@@ -0,0 +1,16 @@
+-- Append-only record of moderation and settings changes. Not tied to the
+-- sessions table by a foreign key so the trail outlives the rows it describes.
+CREATE TABLE audit_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    session_id TEXT NOT NULL,
+    actor TEXT NOT NULL,
+    role TEXT NOT NULL,
+    action TEXT NOT NULL,
+    target_type TEXT NOT NULL,
+    target_id TEXT NOT NULL,
+    before_value TEXT,
+    after_value TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_audit_events_session_id ON audit_events(session_id, id);
@@ -0,0 +1,3 @@
+ALTER TABLE sessions DROP COLUMN fallback_position;
+ALTER TABLE sessions DROP COLUMN fallback_playlist_id;
+ALTER TABLE sessions DROP COLUMN fallback_service;
@@ -0,0 +1,5 @@
+-- Playlist the auto-DJ plays from when a session's TV runs out of songs.
+-- fallback_position is the index of the next playlist entry to try.
+ALTER TABLE sessions ADD COLUMN fallback_service TEXT;
+ALTER TABLE sessions ADD COLUMN fallback_playlist_id TEXT;
+ALTER TABLE sessions ADD COLUMN fallback_position INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1 @@
+ALTER TABLE sessions DROP COLUMN track_constraints;
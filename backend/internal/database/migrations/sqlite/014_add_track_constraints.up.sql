@@ -0,0 +1,2 @@
+-- Genre, release year and popularity rules for requests, stored as JSON.
+ALTER TABLE sessions ADD COLUMN track_constraints TEXT;
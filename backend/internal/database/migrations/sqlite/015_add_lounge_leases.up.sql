@@ -0,0 +1,22 @@
+-- Which server instance owns each session's Lounge connections. The owner
+-- renews its lease while connected; an expired lease may be taken over.
+CREATE TABLE lounge_leases (
+    session_id TEXT PRIMARY KEY REFERENCES sessions(id) ON DELETE CASCADE,
+    owner TEXT NOT NULL,
+    expires_at INTEGER NOT NULL -- Unix milliseconds
+);
+
+-- Commands sent by instances that do not own the session's Lounge
+-- connections, delivered to the TV by the owner.
+CREATE TABLE lounge_commands (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    session_id TEXT NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+    command TEXT NOT NULL CHECK (command IN ('addVideo', 'setVideo', 'removeVideo')),
+    video_id TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sent', 'failed')),
+    error TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    processed_at TIMESTAMP
+);
+
+CREATE INDEX idx_lounge_commands_status ON lounge_commands(status, session_id);
@@ -0,0 +1,5 @@
+ALTER TABLE song_requests DROP COLUMN delivery_error;
+ALTER TABLE song_requests DROP COLUMN delivery_status;
+DROP INDEX IF EXISTS idx_playback_outbox_request_id;
+DROP INDEX IF EXISTS idx_playback_outbox_status;
+DROP TABLE IF EXISTS playback_outbox;
@@ -0,0 +1,23 @@
+-- Playback side effects of moderation (queueing a song on the TV, adding it
+-- to a playlist, ...), written in the same transaction as the request change
+-- and delivered in the background with retries.
+CREATE TABLE playback_outbox (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    session_id TEXT NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+    request_id INTEGER REFERENCES song_requests(id) ON DELETE CASCADE,
+    effect TEXT NOT NULL CHECK (effect IN ('enqueue', 'play_now', 'remove')),
+    track_id TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'delivered', 'failed', 'cancelled')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP,
+    error TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    delivered_at TIMESTAMP
+);
+
+CREATE INDEX idx_playback_outbox_status ON playback_outbox(status, id);
+CREATE INDEX idx_playback_outbox_request_id ON playback_outbox(request_id);
+
+-- Whether the request's song has reached the playback target.
+ALTER TABLE song_requests ADD COLUMN delivery_status TEXT CHECK (delivery_status IN ('pending', 'delivered', 'failed'));
+ALTER TABLE song_requests ADD COLUMN delivery_error TEXT;
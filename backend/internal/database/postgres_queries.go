@@ -0,0 +1,404 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/songify/backend/internal/db"
+	"github.com/songify/backend/internal/db/pgdb"
+)
+
+// postgresQueries adapts the PostgreSQL queries generated in pgdb to
+// db.Store. Both query sets are generated from matching schemas, so rows and
+// parameters convert directly between the two packages' types.
+type postgresQueries struct {
+	q *pgdb.Queries
+}
+
+var _ db.Store = postgresQueries{}
+
+func (p postgresQueries) InTx(tx *sql.Tx) db.Store {
+	return postgresQueries{p.q.WithTx(tx)}
+}
+
+// convertRows converts a query's result rows with conv, passing err through.
+func convertRows[From, To any](rows []From, err error, conv func(From) To) ([]To, error) {
+	if err != nil || rows == nil {
+		return nil, err
+	}
+	converted := make([]To, len(rows))
+	for i, row := range rows {
+		converted[i] = conv(row)
+	}
+	return converted, nil
+}
+
+// Querier methods, in the order of db.Querier.
+
+func (p postgresQueries) ApproveSongRequest(ctx context.Context, arg db.ApproveSongRequestParams) (int64, error) {
+	return p.q.ApproveSongRequest(ctx, pgdb.ApproveSongRequestParams(arg))
+}
+
+func (p postgresQueries) CancelLoungeCommand(ctx context.Context, arg db.CancelLoungeCommandParams) (int64, error) {
+	return p.q.CancelLoungeCommand(ctx, pgdb.CancelLoungeCommandParams(arg))
+}
+
+func (p postgresQueries) CancelOutboxEntries(ctx context.Context, requestID sql.NullInt64) (int64, error) {
+	return p.q.CancelOutboxEntries(ctx, requestID)
+}
+
+func (p postgresQueries) CancelScheduledPlay(ctx context.Context, arg db.CancelScheduledPlayParams) (sql.Result, error) {
+	return p.q.CancelScheduledPlay(ctx, pgdb.CancelScheduledPlayParams(arg))
+}
+
+func (p postgresQueries) ClaimLoungeCommand(ctx context.Context, id int64) (int64, error) {
+	return p.q.ClaimLoungeCommand(ctx, id)
+}
+
+func (p postgresQueries) ClaimLoungeLease(ctx context.Context, arg db.ClaimLoungeLeaseParams) error {
+	return p.q.ClaimLoungeLease(ctx, pgdb.ClaimLoungeLeaseParams(arg))
+}
+
+func (p postgresQueries) ClaimOutboxEntry(ctx context.Context, arg db.ClaimOutboxEntryParams) (int64, error) {
+	return p.q.ClaimOutboxEntry(ctx, pgdb.ClaimOutboxEntryParams(arg))
+}
+
+func (p postgresQueries) CountPendingSongRequests(ctx context.Context, sessionID string) (int64, error) {
+	return p.q.CountPendingSongRequests(ctx, sessionID)
+}
+
+func (p postgresQueries) CountScheduledPlaysForRequest(ctx context.Context, requestID int64) (int64, error) {
+	return p.q.CountScheduledPlaysForRequest(ctx, requestID)
+}
+
+func (p postgresQueries) CreateAuditEvent(ctx context.Context, arg db.CreateAuditEventParams) error {
+	return p.q.CreateAuditEvent(ctx, pgdb.CreateAuditEventParams(arg))
+}
+
+func (p postgresQueries) CreateAutoModerationRule(ctx context.Context, arg db.CreateAutoModerationRuleParams) (db.AutoModerationRule, error) {
+	row, err := p.q.CreateAutoModerationRule(ctx, pgdb.CreateAutoModerationRuleParams(arg))
+	return db.AutoModerationRule(row), err
+}
+
+func (p postgresQueries) CreateLoungeCommand(ctx context.Context, arg db.CreateLoungeCommandParams) (db.LoungeCommand, error) {
+	row, err := p.q.CreateLoungeCommand(ctx, pgdb.CreateLoungeCommandParams(arg))
+	return db.LoungeCommand(row), err
+}
+
+func (p postgresQueries) CreateOutboxEntry(ctx context.Context, arg db.CreateOutboxEntryParams) (db.PlaybackOutbox, error) {
+	row, err := p.q.CreateOutboxEntry(ctx, pgdb.CreateOutboxEntryParams(arg))
+	return db.PlaybackOutbox(row), err
+}
+
+func (p postgresQueries) CreateProhibitedPattern(ctx context.Context, arg db.CreateProhibitedPatternParams) (db.ProhibitedPattern, error) {
+	row, err := p.q.CreateProhibitedPattern(ctx, pgdb.CreateProhibitedPatternParams(arg))
+	return db.ProhibitedPattern(row), err
+}
+
+func (p postgresQueries) CreateScheduledPlay(ctx context.Context, arg db.CreateScheduledPlayParams) (db.ScheduledPlay, error) {
+	row, err := p.q.CreateScheduledPlay(ctx, pgdb.CreateScheduledPlayParams(arg))
+	return db.ScheduledPlay(row), err
+}
+
+func (p postgresQueries) CreateSession(ctx context.Context, arg db.CreateSessionParams) (db.Session, error) {
+	row, err := p.q.CreateSession(ctx, pgdb.CreateSessionParams(arg))
+	return db.Session(row), err
+}
+
+func (p postgresQueries) CreateSongRequest(ctx context.Context, arg db.CreateSongRequestParams) (db.SongRequest, error) {
+	row, err := p.q.CreateSongRequest(ctx, pgdb.CreateSongRequestParams(arg))
+	return db.SongRequest(row), err
+}
+
+func (p postgresQueries) DeleteAllSongRequestsBySessionID(ctx context.Context, sessionID string) error {
+	return p.q.DeleteAllSongRequestsBySessionID(ctx, sessionID)
+}
+
+func (p postgresQueries) DeleteAutoModerationRule(ctx context.Context, arg db.DeleteAutoModerationRuleParams) (sql.Result, error) {
+	return p.q.DeleteAutoModerationRule(ctx, pgdb.DeleteAutoModerationRuleParams(arg))
+}
+
+func (p postgresQueries) DeleteLoungeLease(ctx context.Context, sessionID string) error {
+	return p.q.DeleteLoungeLease(ctx, sessionID)
+}
+
+func (p postgresQueries) DeleteLoungeScreen(ctx context.Context, arg db.DeleteLoungeScreenParams) (sql.Result, error) {
+	return p.q.DeleteLoungeScreen(ctx, pgdb.DeleteLoungeScreenParams(arg))
+}
+
+func (p postgresQueries) DeleteLoungeScreensBySessionID(ctx context.Context, sessionID string) error {
+	return p.q.DeleteLoungeScreensBySessionID(ctx, sessionID)
+}
+
+func (p postgresQueries) DeleteProhibitedPattern(ctx context.Context, id int64) error {
+	return p.q.DeleteProhibitedPattern(ctx, id)
+}
+
+func (p postgresQueries) DeleteProhibitedPatternBySession(ctx context.Context, arg db.DeleteProhibitedPatternBySessionParams) (sql.Result, error) {
+	return p.q.DeleteProhibitedPatternBySession(ctx, pgdb.DeleteProhibitedPatternBySessionParams(arg))
+}
+
+func (p postgresQueries) DeleteProhibitedPatternsBySessionID(ctx context.Context, sessionID string) error {
+	return p.q.DeleteProhibitedPatternsBySessionID(ctx, sessionID)
+}
+
+func (p postgresQueries) DeleteRequestVote(ctx context.Context, arg db.DeleteRequestVoteParams) error {
+	return p.q.DeleteRequestVote(ctx, pgdb.DeleteRequestVoteParams(arg))
+}
+
+func (p postgresQueries) DeleteSession(ctx context.Context, id string) error {
+	return p.q.DeleteSession(ctx, id)
+}
+
+func (p postgresQueries) DeleteSongRequest(ctx context.Context, id int64) error {
+	return p.q.DeleteSongRequest(ctx, id)
+}
+
+func (p postgresQueries) FriendKeyExists(ctx context.Context, friendAccessKey string) (int64, error) {
+	exists, err := p.q.FriendKeyExists(ctx, friendAccessKey)
+	if exists {
+		return 1, err
+	}
+	return 0, err
+}
+
+func (p postgresQueries) GetActiveSongRequests(ctx context.Context, sessionID string) ([]db.SongRequest, error) {
+	rows, err := p.q.GetActiveSongRequests(ctx, sessionID)
+	return convertRows(rows, err, func(row pgdb.SongRequest) db.SongRequest { return db.SongRequest(row) })
+}
+
+func (p postgresQueries) GetAutoModerationRuleByID(ctx context.Context, arg db.GetAutoModerationRuleByIDParams) (db.AutoModerationRule, error) {
+	row, err := p.q.GetAutoModerationRuleByID(ctx, pgdb.GetAutoModerationRuleByIDParams(arg))
+	return db.AutoModerationRule(row), err
+}
+
+func (p postgresQueries) GetAutoModerationRulesBySessionID(ctx context.Context, sessionID string) ([]db.AutoModerationRule, error) {
+	rows, err := p.q.GetAutoModerationRulesBySessionID(ctx, sessionID)
+	return convertRows(rows, err, func(row pgdb.AutoModerationRule) db.AutoModerationRule { return db.AutoModerationRule(row) })
+}
+
+func (p postgresQueries) GetLoungeCommand(ctx context.Context, id int64) (db.LoungeCommand, error) {
+	row, err := p.q.GetLoungeCommand(ctx, id)
+	return db.LoungeCommand(row), err
+}
+
+func (p postgresQueries) GetLoungeLease(ctx context.Context, sessionID string) (db.LoungeLease, error) {
+	row, err := p.q.GetLoungeLease(ctx, sessionID)
+	return db.LoungeLease(row), err
+}
+
+func (p postgresQueries) GetLoungeScreen(ctx context.Context, arg db.GetLoungeScreenParams) (db.LoungeScreen, error) {
+	row, err := p.q.GetLoungeScreen(ctx, pgdb.GetLoungeScreenParams(arg))
+	return db.LoungeScreen(row), err
+}
+
+func (p postgresQueries) GetLoungeScreensBySessionID(ctx context.Context, sessionID string) ([]db.LoungeScreen, error) {
+	rows, err := p.q.GetLoungeScreensBySessionID(ctx, sessionID)
+	return convertRows(rows, err, func(row pgdb.LoungeScreen) db.LoungeScreen { return db.LoungeScreen(row) })
+}
+
+func (p postgresQueries) GetOutboxEntriesByRequestID(ctx context.Context, requestID sql.NullInt64) ([]db.PlaybackOutbox, error) {
+	rows, err := p.q.GetOutboxEntriesByRequestID(ctx, requestID)
+	return convertRows(rows, err, func(row pgdb.PlaybackOutbox) db.PlaybackOutbox { return db.PlaybackOutbox(row) })
+}
+
+func (p postgresQueries) GetPendingLoungeCommands(ctx context.Context, owner string) ([]db.LoungeCommand, error) {
+	rows, err := p.q.GetPendingLoungeCommands(ctx, owner)
+	return convertRows(rows, err, func(row pgdb.LoungeCommand) db.LoungeCommand { return db.LoungeCommand(row) })
+}
+
+func (p postgresQueries) GetPendingOutboxEntries(ctx context.Context) ([]db.PlaybackOutbox, error) {
+	rows, err := p.q.GetPendingOutboxEntries(ctx)
+	return convertRows(rows, err, func(row pgdb.PlaybackOutbox) db.PlaybackOutbox { return db.PlaybackOutbox(row) })
+}
+
+func (p postgresQueries) GetPendingSongRequests(ctx context.Context, sessionID string) ([]db.SongRequest, error) {
+	rows, err := p.q.GetPendingSongRequests(ctx, sessionID)
+	return convertRows(rows, err, func(row pgdb.SongRequest) db.SongRequest { return db.SongRequest(row) })
+}
+
+func (p postgresQueries) GetProhibitedPatternByID(ctx context.Context, arg db.GetProhibitedPatternByIDParams) (db.ProhibitedPattern, error) {
+	row, err := p.q.GetProhibitedPatternByID(ctx, pgdb.GetProhibitedPatternByIDParams(arg))
+	return db.ProhibitedPattern(row), err
+}
+
+func (p postgresQueries) GetProhibitedPatternsBySessionID(ctx context.Context, sessionID string) ([]db.ProhibitedPattern, error) {
+	rows, err := p.q.GetProhibitedPatternsBySessionID(ctx, sessionID)
+	return convertRows(rows, err, func(row pgdb.ProhibitedPattern) db.ProhibitedPattern { return db.ProhibitedPattern(row) })
+}
+
+func (p postgresQueries) GetRequestVoteScore(ctx context.Context, requestID int64) (int64, error) {
+	return p.q.GetRequestVoteScore(ctx, requestID)
+}
+
+func (p postgresQueries) GetRequestedTrackStatuses(ctx context.Context, sessionID string) ([]db.GetRequestedTrackStatusesRow, error) {
+	rows, err := p.q.GetRequestedTrackStatuses(ctx, sessionID)
+	return convertRows(rows, err, func(row pgdb.GetRequestedTrackStatusesRow) db.GetRequestedTrackStatusesRow {
+		return db.GetRequestedTrackStatusesRow(row)
+	})
+}
+
+func (p postgresQueries) GetScheduledPlayByID(ctx context.Context, arg db.GetScheduledPlayByIDParams) (db.ScheduledPlay, error) {
+	row, err := p.q.GetScheduledPlayByID(ctx, pgdb.GetScheduledPlayByIDParams(arg))
+	return db.ScheduledPlay(row), err
+}
+
+func (p postgresQueries) GetScheduledPlaysBySessionID(ctx context.Context, sessionID string) ([]db.ScheduledPlay, error) {
+	rows, err := p.q.GetScheduledPlaysBySessionID(ctx, sessionID)
+	return convertRows(rows, err, func(row pgdb.ScheduledPlay) db.ScheduledPlay { return db.ScheduledPlay(row) })
+}
+
+func (p postgresQueries) GetScheduledPlaysByStatus(ctx context.Context, status string) ([]db.ScheduledPlay, error) {
+	rows, err := p.q.GetScheduledPlaysByStatus(ctx, status)
+	return convertRows(rows, err, func(row pgdb.ScheduledPlay) db.ScheduledPlay { return db.ScheduledPlay(row) })
+}
+
+func (p postgresQueries) GetSessionByAdminCredentials(ctx context.Context, arg db.GetSessionByAdminCredentialsParams) (db.Session, error) {
+	row, err := p.q.GetSessionByAdminCredentials(ctx, pgdb.GetSessionByAdminCredentialsParams(arg))
+	return db.Session(row), err
+}
+
+func (p postgresQueries) GetSessionByFriendKey(ctx context.Context, friendAccessKey string) (db.Session, error) {
+	row, err := p.q.GetSessionByFriendKey(ctx, friendAccessKey)
+	return db.Session(row), err
+}
+
+func (p postgresQueries) GetSessionByID(ctx context.Context, id string) (db.Session, error) {
+	row, err := p.q.GetSessionByID(ctx, id)
+	return db.Session(row), err
+}
+
+func (p postgresQueries) GetSongRequestByID(ctx context.Context, id int64) (db.SongRequest, error) {
+	row, err := p.q.GetSongRequestByID(ctx, id)
+	return db.SongRequest(row), err
+}
+
+func (p postgresQueries) GetSongRequestsByRequester(ctx context.Context, arg db.GetSongRequestsByRequesterParams) ([]db.SongRequest, error) {
+	rows, err := p.q.GetSongRequestsByRequester(ctx, pgdb.GetSongRequestsByRequesterParams(arg))
+	return convertRows(rows, err, func(row pgdb.SongRequest) db.SongRequest { return db.SongRequest(row) })
+}
+
+func (p postgresQueries) GetSongRequestsBySessionID(ctx context.Context, sessionID string) ([]db.SongRequest, error) {
+	rows, err := p.q.GetSongRequestsBySessionID(ctx, sessionID)
+	return convertRows(rows, err, func(row pgdb.SongRequest) db.SongRequest { return db.SongRequest(row) })
+}
+
+func (p postgresQueries) ListAllSessions(ctx context.Context) ([]db.Session, error) {
+	rows, err := p.q.ListAllSessions(ctx)
+	return convertRows(rows, err, func(row pgdb.Session) db.Session { return db.Session(row) })
+}
+
+func (p postgresQueries) ListAuditEvents(ctx context.Context, arg db.ListAuditEventsParams) ([]db.AuditEvent, error) {
+	rows, err := p.q.ListAuditEvents(ctx, pgdb.ListAuditEventsParams{
+		SessionID: arg.SessionID,
+		ID:        arg.ID,
+		Limit:     int32(arg.Limit),
+	})
+	return convertRows(rows, err, func(row pgdb.AuditEvent) db.AuditEvent { return db.AuditEvent(row) })
+}
+
+func (p postgresQueries) ListSessionsWithFallback(ctx context.Context) ([]db.Session, error) {
+	rows, err := p.q.ListSessionsWithFallback(ctx)
+	return convertRows(rows, err, func(row pgdb.Session) db.Session { return db.Session(row) })
+}
+
+func (p postgresQueries) MarkLoungeCommandFailed(ctx context.Context, arg db.MarkLoungeCommandFailedParams) error {
+	return p.q.MarkLoungeCommandFailed(ctx, pgdb.MarkLoungeCommandFailedParams(arg))
+}
+
+func (p postgresQueries) MarkLoungeCommandSent(ctx context.Context, id int64) error {
+	return p.q.MarkLoungeCommandSent(ctx, id)
+}
+
+func (p postgresQueries) MarkOutboxEntryDelivered(ctx context.Context, id int64) (int64, error) {
+	return p.q.MarkOutboxEntryDelivered(ctx, id)
+}
+
+func (p postgresQueries) MarkOutboxEntryFailed(ctx context.Context, arg db.MarkOutboxEntryFailedParams) error {
+	return p.q.MarkOutboxEntryFailed(ctx, pgdb.MarkOutboxEntryFailedParams(arg))
+}
+
+func (p postgresQueries) MarkScheduledPlayFailed(ctx context.Context, arg db.MarkScheduledPlayFailedParams) (sql.Result, error) {
+	return p.q.MarkScheduledPlayFailed(ctx, pgdb.MarkScheduledPlayFailedParams(arg))
+}
+
+func (p postgresQueries) MarkScheduledPlayFired(ctx context.Context, id int64) (sql.Result, error) {
+	return p.q.MarkScheduledPlayFired(ctx, id)
+}
+
+func (p postgresQueries) RejectSongRequest(ctx context.Context, arg db.RejectSongRequestParams) (int64, error) {
+	return p.q.RejectSongRequest(ctx, pgdb.RejectSongRequestParams(arg))
+}
+
+func (p postgresQueries) ReleaseLoungeLease(ctx context.Context, arg db.ReleaseLoungeLeaseParams) error {
+	return p.q.ReleaseLoungeLease(ctx, pgdb.ReleaseLoungeLeaseParams(arg))
+}
+
+func (p postgresQueries) RenewLoungeLease(ctx context.Context, arg db.RenewLoungeLeaseParams) (int64, error) {
+	return p.q.RenewLoungeLease(ctx, pgdb.RenewLoungeLeaseParams(arg))
+}
+
+func (p postgresQueries) RetryOutboxEntry(ctx context.Context, arg db.RetryOutboxEntryParams) error {
+	return p.q.RetryOutboxEntry(ctx, pgdb.RetryOutboxEntryParams(arg))
+}
+
+func (p postgresQueries) RevertSongRequest(ctx context.Context, arg db.RevertSongRequestParams) (sql.Result, error) {
+	return p.q.RevertSongRequest(ctx, pgdb.RevertSongRequestParams(arg))
+}
+
+func (p postgresQueries) SetPrimaryLoungeScreen(ctx context.Context, arg db.SetPrimaryLoungeScreenParams) error {
+	return p.q.SetPrimaryLoungeScreen(ctx, pgdb.SetPrimaryLoungeScreenParams(arg))
+}
+
+func (p postgresQueries) SetSongRequestDelivery(ctx context.Context, arg db.SetSongRequestDeliveryParams) error {
+	return p.q.SetSongRequestDelivery(ctx, pgdb.SetSongRequestDeliveryParams(arg))
+}
+
+func (p postgresQueries) SetSongRequestNoteHidden(ctx context.Context, arg db.SetSongRequestNoteHiddenParams) error {
+	return p.q.SetSongRequestNoteHidden(ctx, pgdb.SetSongRequestNoteHiddenParams(arg))
+}
+
+func (p postgresQueries) UpdateAutoModerationRule(ctx context.Context, arg db.UpdateAutoModerationRuleParams) (sql.Result, error) {
+	return p.q.UpdateAutoModerationRule(ctx, pgdb.UpdateAutoModerationRuleParams(arg))
+}
+
+func (p postgresQueries) UpdateSessionDuplicateWindow(ctx context.Context, arg db.UpdateSessionDuplicateWindowParams) error {
+	return p.q.UpdateSessionDuplicateWindow(ctx, pgdb.UpdateSessionDuplicateWindowParams(arg))
+}
+
+func (p postgresQueries) UpdateSessionFallback(ctx context.Context, arg db.UpdateSessionFallbackParams) error {
+	return p.q.UpdateSessionFallback(ctx, pgdb.UpdateSessionFallbackParams(arg))
+}
+
+func (p postgresQueries) UpdateSessionFallbackPosition(ctx context.Context, arg db.UpdateSessionFallbackPositionParams) error {
+	return p.q.UpdateSessionFallbackPosition(ctx, pgdb.UpdateSessionFallbackPositionParams(arg))
+}
+
+func (p postgresQueries) UpdateSessionLoungeTarget(ctx context.Context, arg db.UpdateSessionLoungeTargetParams) error {
+	return p.q.UpdateSessionLoungeTarget(ctx, pgdb.UpdateSessionLoungeTargetParams(arg))
+}
+
+func (p postgresQueries) UpdateSessionPlaylist(ctx context.Context, arg db.UpdateSessionPlaylistParams) error {
+	return p.q.UpdateSessionPlaylist(ctx, pgdb.UpdateSessionPlaylistParams(arg))
+}
+
+func (p postgresQueries) UpdateSessionSettings(ctx context.Context, arg db.UpdateSessionSettingsParams) error {
+	return p.q.UpdateSessionSettings(ctx, pgdb.UpdateSessionSettingsParams(arg))
+}
+
+func (p postgresQueries) UpdateSessionTrackConstraints(ctx context.Context, arg db.UpdateSessionTrackConstraintsParams) error {
+	return p.q.UpdateSessionTrackConstraints(ctx, pgdb.UpdateSessionTrackConstraintsParams(arg))
+}
+
+func (p postgresQueries) UpsertLoungeScreen(ctx context.Context, arg db.UpsertLoungeScreenParams) (db.LoungeScreen, error) {
+	row, err := p.q.UpsertLoungeScreen(ctx, pgdb.UpsertLoungeScreenParams(arg))
+	return db.LoungeScreen(row), err
+}
+
+func (p postgresQueries) UpsertRequestVote(ctx context.Context, arg db.UpsertRequestVoteParams) error {
+	return p.q.UpsertRequestVote(ctx, pgdb.UpsertRequestVoteParams(arg))
+}
+
+func (p postgresQueries) WithdrawSongRequest(ctx context.Context, arg db.WithdrawSongRequestParams) (sql.Result, error) {
+	return p.q.WithdrawSongRequest(ctx, pgdb.WithdrawSongRequestParams(arg))
+}
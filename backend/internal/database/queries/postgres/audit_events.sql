@@ -0,0 +1,6 @@
+-- name: CreateAuditEvent :exec
+INSERT INTO audit_events (session_id, actor, role, action, target_type, target_id, before_value, after_value)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+
+-- name: ListAuditEvents :many
+SELECT * FROM audit_events WHERE session_id = $1 AND id < $2 ORDER BY id DESC LIMIT $3;
@@ -0,0 +1,17 @@
+-- name: CreateAutoModerationRule :one
+INSERT INTO auto_moderation_rules (session_id, name, action, reason, conditions, position, enabled)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: GetAutoModerationRulesBySessionID :many
+SELECT * FROM auto_moderation_rules WHERE session_id = $1 ORDER BY position, id;
+
+-- name: GetAutoModerationRuleByID :one
+SELECT * FROM auto_moderation_rules WHERE id = $1 AND session_id = $2;
+
+-- name: UpdateAutoModerationRule :execresult
+UPDATE auto_moderation_rules SET name = $1, action = $2, reason = $3, conditions = $4, position = $5, enabled = $6
+WHERE id = $7 AND session_id = $8;
+
+-- name: DeleteAutoModerationRule :execresult
+DELETE FROM auto_moderation_rules WHERE id = $1 AND session_id = $2;
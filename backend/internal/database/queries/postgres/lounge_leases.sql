@@ -0,0 +1,46 @@
+-- name: ClaimLoungeLease :exec
+INSERT INTO lounge_leases (session_id, owner, expires_at)
+VALUES ($1, $2, $3)
+ON CONFLICT (session_id) DO UPDATE SET owner = excluded.owner, expires_at = excluded.expires_at;
+
+-- name: RenewLoungeLease :execrows
+UPDATE lounge_leases SET expires_at = $1 WHERE session_id = $2 AND owner = $3;
+
+-- name: GetLoungeLease :one
+SELECT * FROM lounge_leases WHERE session_id = $1;
+
+-- name: ReleaseLoungeLease :exec
+DELETE FROM lounge_leases WHERE session_id = $1 AND owner = $2;
+
+-- name: DeleteLoungeLease :exec
+DELETE FROM lounge_leases WHERE session_id = $1;
+
+-- name: CreateLoungeCommand :one
+INSERT INTO lounge_commands (session_id, command, video_id)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: GetPendingLoungeCommands :many
+SELECT * FROM lounge_commands
+WHERE status = 'pending' AND processed_at IS NULL
+  AND session_id IN (SELECT session_id FROM lounge_leases WHERE owner = $1)
+ORDER BY id;
+
+-- name: MarkLoungeCommandSent :exec
+UPDATE lounge_commands SET status = 'sent', processed_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND status = 'pending';
+
+-- name: MarkLoungeCommandFailed :exec
+UPDATE lounge_commands SET status = 'failed', error = $1, processed_at = CURRENT_TIMESTAMP
+WHERE id = $2 AND status = 'pending';
+
+-- name: GetLoungeCommand :one
+SELECT * FROM lounge_commands WHERE id = $1;
+
+-- name: ClaimLoungeCommand :execrows
+UPDATE lounge_commands SET processed_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND status = 'pending' AND processed_at IS NULL;
+
+-- name: CancelLoungeCommand :execrows
+UPDATE lounge_commands SET status = 'failed', error = $1, processed_at = CURRENT_TIMESTAMP
+WHERE id = $2 AND status = 'pending' AND processed_at IS NULL;
@@ -0,0 +1,30 @@
+-- name: CreateOutboxEntry :one
+INSERT INTO playback_outbox (session_id, request_id, effect, track_id)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetPendingOutboxEntries :many
+SELECT * FROM playback_outbox WHERE status = 'pending' ORDER BY id;
+
+-- name: GetOutboxEntriesByRequestID :many
+SELECT * FROM playback_outbox WHERE request_id = $1 ORDER BY id;
+
+-- name: ClaimOutboxEntry :execrows
+UPDATE playback_outbox SET attempts = attempts + 1, next_attempt_at = $1
+WHERE id = $2 AND status = 'pending' AND attempts = $3;
+
+-- name: MarkOutboxEntryDelivered :execrows
+UPDATE playback_outbox SET status = 'delivered', error = NULL, delivered_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND status = 'pending';
+
+-- name: RetryOutboxEntry :exec
+UPDATE playback_outbox SET error = $1, next_attempt_at = $2
+WHERE id = $3 AND status = 'pending';
+
+-- name: MarkOutboxEntryFailed :exec
+UPDATE playback_outbox SET status = 'failed', error = $1
+WHERE id = $2 AND status = 'pending';
+
+-- name: CancelOutboxEntries :execrows
+UPDATE playback_outbox SET status = 'cancelled'
+WHERE request_id = $1 AND status = 'pending';
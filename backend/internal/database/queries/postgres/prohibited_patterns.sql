@@ -0,0 +1,19 @@
+-- name: CreateProhibitedPattern :one
+INSERT INTO prohibited_patterns (session_id, pattern_type, pattern)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: GetProhibitedPatternsBySessionID :many
+SELECT * FROM prohibited_patterns WHERE session_id = $1;
+
+-- name: DeleteProhibitedPattern :exec
+DELETE FROM prohibited_patterns WHERE id = $1;
+
+-- name: DeleteProhibitedPatternBySession :execresult
+DELETE FROM prohibited_patterns WHERE id = $1 AND session_id = $2;
+
+-- name: DeleteProhibitedPatternsBySessionID :exec
+DELETE FROM prohibited_patterns WHERE session_id = $1;
+
+-- name: GetProhibitedPatternByID :one
+SELECT * FROM prohibited_patterns WHERE id = $1 AND session_id = $2;
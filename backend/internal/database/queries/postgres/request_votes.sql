@@ -0,0 +1,10 @@
+-- name: UpsertRequestVote :exec
+INSERT INTO request_votes (request_id, voter, value)
+VALUES ($1, $2, $3)
+ON CONFLICT (request_id, voter) DO UPDATE SET value = excluded.value;
+
+-- name: DeleteRequestVote :exec
+DELETE FROM request_votes WHERE request_id = $1 AND voter = $2;
+
+-- name: GetRequestVoteScore :one
+SELECT CAST(COALESCE(SUM(value), 0) AS BIGINT) AS score FROM request_votes WHERE request_id = $1;
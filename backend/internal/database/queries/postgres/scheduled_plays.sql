@@ -0,0 +1,28 @@
+-- name: CreateScheduledPlay :one
+INSERT INTO scheduled_plays (session_id, request_id, mode, play_at)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetScheduledPlayByID :one
+SELECT * FROM scheduled_plays WHERE id = $1 AND session_id = $2;
+
+-- name: GetScheduledPlaysBySessionID :many
+SELECT * FROM scheduled_plays WHERE session_id = $1 ORDER BY id DESC;
+
+-- name: GetScheduledPlaysByStatus :many
+SELECT * FROM scheduled_plays WHERE status = $1 ORDER BY id;
+
+-- name: CountScheduledPlaysForRequest :one
+SELECT COUNT(*) FROM scheduled_plays WHERE request_id = $1 AND status = 'scheduled';
+
+-- name: MarkScheduledPlayFired :execresult
+UPDATE scheduled_plays SET status = 'fired', fired_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND status = 'scheduled';
+
+-- name: MarkScheduledPlayFailed :execresult
+UPDATE scheduled_plays SET status = 'failed', error = $1, fired_at = CURRENT_TIMESTAMP
+WHERE id = $2 AND status = 'scheduled';
+
+-- name: CancelScheduledPlay :execresult
+UPDATE scheduled_plays SET status = 'cancelled'
+WHERE id = $1 AND session_id = $2 AND status = 'scheduled';
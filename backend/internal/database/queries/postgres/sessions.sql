@@ -0,0 +1,46 @@
+-- name: CreateSession :one
+INSERT INTO sessions (id, display_name, admin_name, admin_password_hash, friend_access_key, spotify_playlist_id, song_duration_limit_ms, music_service)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: GetSessionByID :one
+SELECT * FROM sessions WHERE id = $1;
+
+-- name: GetSessionByFriendKey :one
+SELECT * FROM sessions WHERE friend_access_key = $1;
+
+-- name: GetSessionByAdminCredentials :one
+SELECT * FROM sessions WHERE admin_name = $1 AND admin_password_hash = $2;
+
+-- name: UpdateSessionPlaylist :exec
+UPDATE sessions SET spotify_playlist_id = $1, spotify_playlist_name = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $3;
+
+-- name: UpdateSessionSettings :exec
+UPDATE sessions SET song_duration_limit_ms = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2;
+
+-- name: UpdateSessionDuplicateWindow :exec
+UPDATE sessions SET duplicate_window_minutes = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2;
+
+-- name: DeleteSession :exec
+DELETE FROM sessions WHERE id = $1;
+
+-- name: FriendKeyExists :one
+SELECT EXISTS(SELECT 1 FROM sessions WHERE friend_access_key = $1) AS exists_flag;
+
+-- name: ListAllSessions :many
+SELECT * FROM sessions;
+
+-- name: UpdateSessionLoungeTarget :exec
+UPDATE sessions SET lounge_target = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2;
+
+-- name: UpdateSessionFallback :exec
+UPDATE sessions SET fallback_service = $1, fallback_playlist_id = $2, fallback_position = 0, updated_at = CURRENT_TIMESTAMP WHERE id = $3;
+
+-- name: UpdateSessionFallbackPosition :exec
+UPDATE sessions SET fallback_position = $1 WHERE id = $2;
+
+-- name: ListSessionsWithFallback :many
+SELECT * FROM sessions WHERE fallback_playlist_id IS NOT NULL;
+
+-- name: UpdateSessionTrackConstraints :exec
+UPDATE sessions SET track_constraints = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2;
@@ -0,0 +1,51 @@
+-- name: CreateSongRequest :one
+INSERT INTO song_requests (session_id, external_track_id, track_name, artist_names, album_name, album_art_url, duration_ms, external_uri, requester_name, note)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING *;
+
+-- name: GetSongRequestByID :one
+SELECT * FROM song_requests WHERE id = $1;
+
+-- name: GetSongRequestsBySessionID :many
+SELECT * FROM song_requests WHERE session_id = $1 ORDER BY requested_at DESC;
+
+-- name: GetSongRequestsByRequester :many
+SELECT * FROM song_requests WHERE session_id = $1 AND requester_name = $2 ORDER BY requested_at DESC;
+
+-- name: GetPendingSongRequests :many
+SELECT * FROM song_requests WHERE session_id = $1 AND status = 'pending' ORDER BY requested_at ASC;
+
+-- name: CountPendingSongRequests :one
+SELECT COUNT(*) FROM song_requests WHERE session_id = $1 AND status = 'pending';
+
+-- name: ApproveSongRequest :execrows
+UPDATE song_requests SET status = 'approved', processed_at = CURRENT_TIMESTAMP, processed_by = $1 WHERE id = $2 AND status = 'pending';
+
+-- name: RejectSongRequest :execrows
+UPDATE song_requests SET status = 'rejected', processed_at = CURRENT_TIMESTAMP, rejection_reason = $1, processed_by = $2 WHERE id = $3 AND status = 'pending';
+
+-- name: GetActiveSongRequests :many
+SELECT * FROM song_requests WHERE session_id = $1 AND status != 'rejected' ORDER BY requested_at DESC;
+
+-- name: SetSongRequestNoteHidden :exec
+UPDATE song_requests SET note_hidden = $1 WHERE id = $2;
+
+-- name: DeleteSongRequest :exec
+DELETE FROM song_requests WHERE id = $1;
+
+-- name: WithdrawSongRequest :execresult
+DELETE FROM song_requests WHERE id = $1 AND session_id = $2 AND requester_name = $3 AND status = 'pending';
+
+-- name: DeleteAllSongRequestsBySessionID :exec
+DELETE FROM song_requests WHERE session_id = $1;
+
+-- name: GetRequestedTrackStatuses :many
+SELECT external_track_id, status FROM song_requests WHERE session_id = $1 AND status != 'rejected';
+
+-- name: RevertSongRequest :execresult
+UPDATE song_requests SET status = 'pending', processed_at = NULL, processed_by = NULL, rejection_reason = NULL,
+    delivery_status = NULL, delivery_error = NULL
+WHERE id = $1 AND status = $2;
+
+-- name: SetSongRequestDelivery :exec
+UPDATE song_requests SET delivery_status = $1, delivery_error = $2 WHERE id = $3;
@@ -0,0 +1,6 @@
+-- name: CreateAuditEvent :exec
+INSERT INTO audit_events (session_id, actor, role, action, target_type, target_id, before_value, after_value)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: ListAuditEvents :many
+SELECT * FROM audit_events WHERE session_id = ? AND id < ? ORDER BY id DESC LIMIT ?;
@@ -0,0 +1,17 @@
+-- name: CreateAutoModerationRule :one
+INSERT INTO auto_moderation_rules (session_id, name, action, reason, conditions, position, enabled)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: GetAutoModerationRulesBySessionID :many
+SELECT * FROM auto_moderation_rules WHERE session_id = ? ORDER BY position, id;
+
+-- name: GetAutoModerationRuleByID :one
+SELECT * FROM auto_moderation_rules WHERE id = ? AND session_id = ?;
+
+-- name: UpdateAutoModerationRule :execresult
+UPDATE auto_moderation_rules SET name = ?, action = ?, reason = ?, conditions = ?, position = ?, enabled = ?
+WHERE id = ? AND session_id = ?;
+
+-- name: DeleteAutoModerationRule :execresult
+DELETE FROM auto_moderation_rules WHERE id = ? AND session_id = ?;
@@ -0,0 +1,46 @@
+-- name: ClaimLoungeLease :exec
+INSERT INTO lounge_leases (session_id, owner, expires_at)
+VALUES (?, ?, ?)
+ON CONFLICT (session_id) DO UPDATE SET owner = excluded.owner, expires_at = excluded.expires_at;
+
+-- name: RenewLoungeLease :execrows
+UPDATE lounge_leases SET expires_at = ? WHERE session_id = ? AND owner = ?;
+
+-- name: GetLoungeLease :one
+SELECT * FROM lounge_leases WHERE session_id = ?;
+
+-- name: ReleaseLoungeLease :exec
+DELETE FROM lounge_leases WHERE session_id = ? AND owner = ?;
+
+-- name: DeleteLoungeLease :exec
+DELETE FROM lounge_leases WHERE session_id = ?;
+
+-- name: CreateLoungeCommand :one
+INSERT INTO lounge_commands (session_id, command, video_id)
+VALUES (?, ?, ?)
+RETURNING *;
+
+-- name: GetPendingLoungeCommands :many
+SELECT * FROM lounge_commands
+WHERE status = 'pending' AND processed_at IS NULL
+  AND session_id IN (SELECT session_id FROM lounge_leases WHERE owner = ?)
+ORDER BY id;
+
+-- name: MarkLoungeCommandSent :exec
+UPDATE lounge_commands SET status = 'sent', processed_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status = 'pending';
+
+-- name: MarkLoungeCommandFailed :exec
+UPDATE lounge_commands SET status = 'failed', error = ?, processed_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status = 'pending';
+
+-- name: GetLoungeCommand :one
+SELECT * FROM lounge_commands WHERE id = ?;
+
+-- name: ClaimLoungeCommand :execrows
+UPDATE lounge_commands SET processed_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status = 'pending' AND processed_at IS NULL;
+
+-- name: CancelLoungeCommand :execrows
+UPDATE lounge_commands SET status = 'failed', error = ?, processed_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status = 'pending' AND processed_at IS NULL;
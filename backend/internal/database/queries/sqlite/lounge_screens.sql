@@ -0,0 +1,20 @@
+-- name: UpsertLoungeScreen :one
+INSERT INTO lounge_screens (session_id, screen_id, lounge_token, screen_name)
+VALUES (?, ?, ?, ?)
+ON CONFLICT (session_id, screen_id) DO UPDATE SET lounge_token = excluded.lounge_token, screen_name = excluded.screen_name
+RETURNING *;
+
+-- name: GetLoungeScreensBySessionID :many
+SELECT * FROM lounge_screens WHERE session_id = ? ORDER BY id ASC;
+
+-- name: GetLoungeScreen :one
+SELECT * FROM lounge_screens WHERE session_id = ? AND screen_id = ?;
+
+-- name: SetPrimaryLoungeScreen :exec
+UPDATE lounge_screens SET is_primary = (screen_id = sqlc.arg(screen_id)) WHERE session_id = sqlc.arg(session_id);
+
+-- name: DeleteLoungeScreen :execresult
+DELETE FROM lounge_screens WHERE session_id = ? AND screen_id = ?;
+
+-- name: DeleteLoungeScreensBySessionID :exec
+DELETE FROM lounge_screens WHERE session_id = ?;
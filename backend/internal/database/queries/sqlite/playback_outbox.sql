@@ -0,0 +1,30 @@
+-- name: CreateOutboxEntry :one
+INSERT INTO playback_outbox (session_id, request_id, effect, track_id)
+VALUES (?, ?, ?, ?)
+RETURNING *;
+
+-- name: GetPendingOutboxEntries :many
+SELECT * FROM playback_outbox WHERE status = 'pending' ORDER BY id;
+
+-- name: GetOutboxEntriesByRequestID :many
+SELECT * FROM playback_outbox WHERE request_id = ? ORDER BY id;
+
+-- name: ClaimOutboxEntry :execrows
+UPDATE playback_outbox SET attempts = attempts + 1, next_attempt_at = ?
+WHERE id = ? AND status = 'pending' AND attempts = ?;
+
+-- name: MarkOutboxEntryDelivered :execrows
+UPDATE playback_outbox SET status = 'delivered', error = NULL, delivered_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status = 'pending';
+
+-- name: RetryOutboxEntry :exec
+UPDATE playback_outbox SET error = ?, next_attempt_at = ?
+WHERE id = ? AND status = 'pending';
+
+-- name: MarkOutboxEntryFailed :exec
+UPDATE playback_outbox SET status = 'failed', error = ?
+WHERE id = ? AND status = 'pending';
+
+-- name: CancelOutboxEntries :execrows
+UPDATE playback_outbox SET status = 'cancelled'
+WHERE request_id = ? AND status = 'pending';
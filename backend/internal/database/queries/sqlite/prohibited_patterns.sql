@@ -14,3 +14,6 @@ DELETE FROM prohibited_patterns WHERE id = ? AND session_id = ?;
 
 -- name: DeleteProhibitedPatternsBySessionID :exec
 DELETE FROM prohibited_patterns WHERE session_id = ?;
+
+-- name: GetProhibitedPatternByID :one
+SELECT * FROM prohibited_patterns WHERE id = ? AND session_id = ?;
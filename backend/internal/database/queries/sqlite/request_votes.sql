@@ -0,0 +1,10 @@
+-- name: UpsertRequestVote :exec
+INSERT INTO request_votes (request_id, voter, value)
+VALUES (?, ?, ?)
+ON CONFLICT (request_id, voter) DO UPDATE SET value = excluded.value;
+
+-- name: DeleteRequestVote :exec
+DELETE FROM request_votes WHERE request_id = ? AND voter = ?;
+
+-- name: GetRequestVoteScore :one
+SELECT CAST(COALESCE(SUM(value), 0) AS INTEGER) AS score FROM request_votes WHERE request_id = ?;
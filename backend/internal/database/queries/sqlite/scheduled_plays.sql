@@ -0,0 +1,28 @@
+-- name: CreateScheduledPlay :one
+INSERT INTO scheduled_plays (session_id, request_id, mode, play_at)
+VALUES (?, ?, ?, ?)
+RETURNING *;
+
+-- name: GetScheduledPlayByID :one
+SELECT * FROM scheduled_plays WHERE id = ? AND session_id = ?;
+
+-- name: GetScheduledPlaysBySessionID :many
+SELECT * FROM scheduled_plays WHERE session_id = ? ORDER BY id DESC;
+
+-- name: GetScheduledPlaysByStatus :many
+SELECT * FROM scheduled_plays WHERE status = ? ORDER BY id;
+
+-- name: CountScheduledPlaysForRequest :one
+SELECT COUNT(*) FROM scheduled_plays WHERE request_id = ? AND status = 'scheduled';
+
+-- name: MarkScheduledPlayFired :execresult
+UPDATE scheduled_plays SET status = 'fired', fired_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status = 'scheduled';
+
+-- name: MarkScheduledPlayFailed :execresult
+UPDATE scheduled_plays SET status = 'failed', error = ?, fired_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status = 'scheduled';
+
+-- name: CancelScheduledPlay :execresult
+UPDATE scheduled_plays SET status = 'cancelled'
+WHERE id = ? AND session_id = ? AND status = 'scheduled';
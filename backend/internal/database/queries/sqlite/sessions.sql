@@ -18,6 +18,9 @@ UPDATE sessions SET spotify_playlist_id = ?, spotify_playlist_name = ?, updated_
 -- name: UpdateSessionSettings :exec
 UPDATE sessions SET song_duration_limit_ms = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?;
 
+-- name: UpdateSessionDuplicateWindow :exec
+UPDATE sessions SET duplicate_window_minutes = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?;
+
 -- name: DeleteSession :exec
 DELETE FROM sessions WHERE id = ?;
 
@@ -27,11 +30,17 @@ SELECT EXISTS(SELECT 1 FROM sessions WHERE friend_access_key = ?) AS exists_flag
 -- name: ListAllSessions :many
 SELECT * FROM sessions;
 
--- name: SaveLoungeCredentials :exec
-UPDATE sessions SET lounge_screen_id = ?, lounge_token = ?, lounge_screen_name = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?;
+-- name: UpdateSessionLoungeTarget :exec
+UPDATE sessions SET lounge_target = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?;
+
+-- name: UpdateSessionFallback :exec
+UPDATE sessions SET fallback_service = ?, fallback_playlist_id = ?, fallback_position = 0, updated_at = CURRENT_TIMESTAMP WHERE id = ?;
+
+-- name: UpdateSessionFallbackPosition :exec
+UPDATE sessions SET fallback_position = ? WHERE id = ?;
 
--- name: ClearLoungeCredentials :exec
-UPDATE sessions SET lounge_screen_id = NULL, lounge_token = NULL, lounge_screen_name = NULL, updated_at = CURRENT_TIMESTAMP WHERE id = ?;
+-- name: ListSessionsWithFallback :many
+SELECT * FROM sessions WHERE fallback_playlist_id IS NOT NULL;
 
--- name: GetLoungeCredentials :one
-SELECT lounge_screen_id, lounge_token, lounge_screen_name FROM sessions WHERE id = ?;
+-- name: UpdateSessionTrackConstraints :exec
+UPDATE sessions SET track_constraints = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?;
@@ -0,0 +1,51 @@
+-- name: CreateSongRequest :one
+INSERT INTO song_requests (session_id, external_track_id, track_name, artist_names, album_name, album_art_url, duration_ms, external_uri, requester_name, note)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: GetSongRequestByID :one
+SELECT * FROM song_requests WHERE id = ?;
+
+-- name: GetSongRequestsBySessionID :many
+SELECT * FROM song_requests WHERE session_id = ? ORDER BY requested_at DESC;
+
+-- name: GetSongRequestsByRequester :many
+SELECT * FROM song_requests WHERE session_id = ? AND requester_name = ? ORDER BY requested_at DESC;
+
+-- name: GetPendingSongRequests :many
+SELECT * FROM song_requests WHERE session_id = ? AND status = 'pending' ORDER BY requested_at ASC;
+
+-- name: CountPendingSongRequests :one
+SELECT COUNT(*) FROM song_requests WHERE session_id = ? AND status = 'pending';
+
+-- name: ApproveSongRequest :execrows
+UPDATE song_requests SET status = 'approved', processed_at = CURRENT_TIMESTAMP, processed_by = ? WHERE id = ? AND status = 'pending';
+
+-- name: RejectSongRequest :execrows
+UPDATE song_requests SET status = 'rejected', processed_at = CURRENT_TIMESTAMP, rejection_reason = ?, processed_by = ? WHERE id = ? AND status = 'pending';
+
+-- name: GetActiveSongRequests :many
+SELECT * FROM song_requests WHERE session_id = ? AND status != 'rejected' ORDER BY requested_at DESC;
+
+-- name: SetSongRequestNoteHidden :exec
+UPDATE song_requests SET note_hidden = ? WHERE id = ?;
+
+-- name: DeleteSongRequest :exec
+DELETE FROM song_requests WHERE id = ?;
+
+-- name: WithdrawSongRequest :execresult
+DELETE FROM song_requests WHERE id = ? AND session_id = ? AND requester_name = ? AND status = 'pending';
+
+-- name: DeleteAllSongRequestsBySessionID :exec
+DELETE FROM song_requests WHERE session_id = ?;
+
+-- name: GetRequestedTrackStatuses :many
+SELECT external_track_id, status FROM song_requests WHERE session_id = ? AND status != 'rejected';
+
+-- name: RevertSongRequest :execresult
+UPDATE song_requests SET status = 'pending', processed_at = NULL, processed_by = NULL, rejection_reason = NULL,
+    delivery_status = NULL, delivery_error = NULL
+WHERE id = ? AND status = ?;
+
+-- name: SetSongRequestDelivery :exec
+UPDATE song_requests SET delivery_status = ?, delivery_error = ? WHERE id = ?;
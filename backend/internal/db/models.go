@@ -8,6 +8,72 @@ import (
 	"database/sql"
 )
 
+type AuditEvent struct {
+	ID          int64          `json:"id"`
+	SessionID   string         `json:"session_id"`
+	Actor       string         `json:"actor"`
+	Role        string         `json:"role"`
+	Action      string         `json:"action"`
+	TargetType  string         `json:"target_type"`
+	TargetID    string         `json:"target_id"`
+	BeforeValue sql.NullString `json:"before_value"`
+	AfterValue  sql.NullString `json:"after_value"`
+	CreatedAt   sql.NullTime   `json:"created_at"`
+}
+
+type AutoModerationRule struct {
+	ID         int64          `json:"id"`
+	SessionID  string         `json:"session_id"`
+	Name       string         `json:"name"`
+	Action     string         `json:"action"`
+	Reason     sql.NullString `json:"reason"`
+	Conditions string         `json:"conditions"`
+	Position   int64          `json:"position"`
+	Enabled    bool           `json:"enabled"`
+	CreatedAt  sql.NullTime   `json:"created_at"`
+}
+
+type LoungeCommand struct {
+	ID          int64          `json:"id"`
+	SessionID   string         `json:"session_id"`
+	Command     string         `json:"command"`
+	VideoID     string         `json:"video_id"`
+	Status      string         `json:"status"`
+	Error       sql.NullString `json:"error"`
+	CreatedAt   sql.NullTime   `json:"created_at"`
+	ProcessedAt sql.NullTime   `json:"processed_at"`
+}
+
+type LoungeLease struct {
+	SessionID string `json:"session_id"`
+	Owner     string `json:"owner"`
+	ExpiresAt int64  `json:"expires_at"`
+}
+
+type LoungeScreen struct {
+	ID          int64          `json:"id"`
+	SessionID   string         `json:"session_id"`
+	ScreenID    string         `json:"screen_id"`
+	LoungeToken string         `json:"lounge_token"`
+	ScreenName  sql.NullString `json:"screen_name"`
+	IsPrimary   bool           `json:"is_primary"`
+	CreatedAt   sql.NullTime   `json:"created_at"`
+}
+
+type PlaybackOutbox struct {
+	ID            int64          `json:"id"`
+	SessionID     string         `json:"session_id"`
+	RequestID     sql.NullInt64  `json:"request_id"`
+	Effect        string         `json:"effect"`
+	TrackID       string         `json:"track_id"`
+	Status        string         `json:"status"`
+	Attempts      int64          `json:"attempts"`
+	NextAttemptAt sql.NullTime   `json:"next_attempt_at"`
+	Error         sql.NullString `json:"error"`
+	CreatedAt     sql.NullTime   `json:"created_at"`
+	DeliveredAt   sql.NullTime   `json:"delivered_at"`
+}
+
 type ProhibitedPattern struct {
 	ID          int64  `json:"id"`
 	SessionID   string `json:"session_id"`
@@ -15,21 +81,43 @@ type ProhibitedPattern struct {
 	Pattern     string `json:"pattern"`
 }
 
+type RequestVote struct {
+	RequestID int64        `json:"request_id"`
+	Voter     string       `json:"voter"`
+	Value     int64        `json:"value"`
+	CreatedAt sql.NullTime `json:"created_at"`
+}
+
+type ScheduledPlay struct {
+	ID        int64          `json:"id"`
+	SessionID string         `json:"session_id"`
+	RequestID int64          `json:"request_id"`
+	Mode      string         `json:"mode"`
+	PlayAt    sql.NullTime   `json:"play_at"`
+	Status    string         `json:"status"`
+	Error     sql.NullString `json:"error"`
+	CreatedAt sql.NullTime   `json:"created_at"`
+	FiredAt   sql.NullTime   `json:"fired_at"`
+}
+
 type Session struct {
-	ID                  string         `json:"id"`
-	DisplayName         string         `json:"display_name"`
-	AdminName           string         `json:"admin_name"`
-	AdminPasswordHash   string         `json:"admin_password_hash"`
-	FriendAccessKey     string         `json:"friend_access_key"`
-	SpotifyPlaylistID   sql.NullString `json:"spotify_playlist_id"`
-	SongDurationLimitMs sql.NullInt64  `json:"song_duration_limit_ms"`
-	CreatedAt           sql.NullTime   `json:"created_at"`
-	UpdatedAt           sql.NullTime   `json:"updated_at"`
-	SpotifyPlaylistName sql.NullString `json:"spotify_playlist_name"`
-	MusicService        string         `json:"music_service"`
-	LoungeScreenID      sql.NullString `json:"lounge_screen_id"`
-	LoungeToken         sql.NullString `json:"lounge_token"`
-	LoungeScreenName    sql.NullString `json:"lounge_screen_name"`
+	ID                     string         `json:"id"`
+	DisplayName            string         `json:"display_name"`
+	AdminName              string         `json:"admin_name"`
+	AdminPasswordHash      string         `json:"admin_password_hash"`
+	FriendAccessKey        string         `json:"friend_access_key"`
+	SpotifyPlaylistID      sql.NullString `json:"spotify_playlist_id"`
+	SongDurationLimitMs    sql.NullInt64  `json:"song_duration_limit_ms"`
+	CreatedAt              sql.NullTime   `json:"created_at"`
+	UpdatedAt              sql.NullTime   `json:"updated_at"`
+	SpotifyPlaylistName    sql.NullString `json:"spotify_playlist_name"`
+	MusicService           string         `json:"music_service"`
+	LoungeTarget           string         `json:"lounge_target"`
+	DuplicateWindowMinutes sql.NullInt64  `json:"duplicate_window_minutes"`
+	FallbackService        sql.NullString `json:"fallback_service"`
+	FallbackPlaylistID     sql.NullString `json:"fallback_playlist_id"`
+	FallbackPosition       int64          `json:"fallback_position"`
+	TrackConstraints       sql.NullString `json:"track_constraints"`
 }
 
 type SongRequest struct {
@@ -47,4 +135,9 @@ type SongRequest struct {
 	ProcessedAt     sql.NullTime   `json:"processed_at"`
 	RejectionReason sql.NullString `json:"rejection_reason"`
 	RequesterName   sql.NullString `json:"requester_name"`
+	Note            sql.NullString `json:"note"`
+	NoteHidden      bool           `json:"note_hidden"`
+	ProcessedBy     sql.NullString `json:"processed_by"`
+	DeliveryStatus  sql.NullString `json:"delivery_status"`
+	DeliveryError   sql.NullString `json:"delivery_error"`
 }
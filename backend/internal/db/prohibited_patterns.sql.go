@@ -65,6 +65,27 @@ func (q *Queries) DeleteProhibitedPatternsBySessionID(ctx context.Context, sessi
 	return err
 }
 
+const getProhibitedPatternByID = `-- name: GetProhibitedPatternByID :one
+SELECT id, session_id, pattern_type, pattern FROM prohibited_patterns WHERE id = ? AND session_id = ?
+`
+
+type GetProhibitedPatternByIDParams struct {
+	ID        int64  `json:"id"`
+	SessionID string `json:"session_id"`
+}
+
+func (q *Queries) GetProhibitedPatternByID(ctx context.Context, arg GetProhibitedPatternByIDParams) (ProhibitedPattern, error) {
+	row := q.db.QueryRowContext(ctx, getProhibitedPatternByID, arg.ID, arg.SessionID)
+	var i ProhibitedPattern
+	err := row.Scan(
+		&i.ID,
+		&i.SessionID,
+		&i.PatternType,
+		&i.Pattern,
+	)
+	return i, err
+}
+
 const getProhibitedPatternsBySessionID = `-- name: GetProhibitedPatternsBySessionID :many
 SELECT id, session_id, pattern_type, pattern FROM prohibited_patterns WHERE session_id = ?
 `
@@ -10,32 +10,87 @@ import (
 )
 
 type Querier interface {
-	ApproveSongRequest(ctx context.Context, id int64) error
-	ClearLoungeCredentials(ctx context.Context, id string) error
+	ApproveSongRequest(ctx context.Context, arg ApproveSongRequestParams) (int64, error)
+	CancelLoungeCommand(ctx context.Context, arg CancelLoungeCommandParams) (int64, error)
+	CancelOutboxEntries(ctx context.Context, requestID sql.NullInt64) (int64, error)
+	CancelScheduledPlay(ctx context.Context, arg CancelScheduledPlayParams) (sql.Result, error)
+	ClaimLoungeCommand(ctx context.Context, id int64) (int64, error)
+	ClaimLoungeLease(ctx context.Context, arg ClaimLoungeLeaseParams) error
+	ClaimOutboxEntry(ctx context.Context, arg ClaimOutboxEntryParams) (int64, error)
+	CountPendingSongRequests(ctx context.Context, sessionID string) (int64, error)
+	CountScheduledPlaysForRequest(ctx context.Context, requestID int64) (int64, error)
+	CreateAuditEvent(ctx context.Context, arg CreateAuditEventParams) error
+	CreateAutoModerationRule(ctx context.Context, arg CreateAutoModerationRuleParams) (AutoModerationRule, error)
+	CreateLoungeCommand(ctx context.Context, arg CreateLoungeCommandParams) (LoungeCommand, error)
+	CreateOutboxEntry(ctx context.Context, arg CreateOutboxEntryParams) (PlaybackOutbox, error)
 	CreateProhibitedPattern(ctx context.Context, arg CreateProhibitedPatternParams) (ProhibitedPattern, error)
+	CreateScheduledPlay(ctx context.Context, arg CreateScheduledPlayParams) (ScheduledPlay, error)
 	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
 	CreateSongRequest(ctx context.Context, arg CreateSongRequestParams) (SongRequest, error)
 	DeleteAllSongRequestsBySessionID(ctx context.Context, sessionID string) error
+	DeleteAutoModerationRule(ctx context.Context, arg DeleteAutoModerationRuleParams) (sql.Result, error)
+	DeleteLoungeLease(ctx context.Context, sessionID string) error
+	DeleteLoungeScreen(ctx context.Context, arg DeleteLoungeScreenParams) (sql.Result, error)
+	DeleteLoungeScreensBySessionID(ctx context.Context, sessionID string) error
 	DeleteProhibitedPattern(ctx context.Context, id int64) error
 	DeleteProhibitedPatternBySession(ctx context.Context, arg DeleteProhibitedPatternBySessionParams) (sql.Result, error)
 	DeleteProhibitedPatternsBySessionID(ctx context.Context, sessionID string) error
+	DeleteRequestVote(ctx context.Context, arg DeleteRequestVoteParams) error
 	DeleteSession(ctx context.Context, id string) error
 	DeleteSongRequest(ctx context.Context, id int64) error
 	FriendKeyExists(ctx context.Context, friendAccessKey string) (int64, error)
-	GetLoungeCredentials(ctx context.Context, id string) (GetLoungeCredentialsRow, error)
+	GetActiveSongRequests(ctx context.Context, sessionID string) ([]SongRequest, error)
+	GetAutoModerationRuleByID(ctx context.Context, arg GetAutoModerationRuleByIDParams) (AutoModerationRule, error)
+	GetAutoModerationRulesBySessionID(ctx context.Context, sessionID string) ([]AutoModerationRule, error)
+	GetLoungeCommand(ctx context.Context, id int64) (LoungeCommand, error)
+	GetLoungeLease(ctx context.Context, sessionID string) (LoungeLease, error)
+	GetLoungeScreen(ctx context.Context, arg GetLoungeScreenParams) (LoungeScreen, error)
+	GetLoungeScreensBySessionID(ctx context.Context, sessionID string) ([]LoungeScreen, error)
+	GetOutboxEntriesByRequestID(ctx context.Context, requestID sql.NullInt64) ([]PlaybackOutbox, error)
+	GetPendingLoungeCommands(ctx context.Context, owner string) ([]LoungeCommand, error)
+	GetPendingOutboxEntries(ctx context.Context) ([]PlaybackOutbox, error)
 	GetPendingSongRequests(ctx context.Context, sessionID string) ([]SongRequest, error)
+	GetProhibitedPatternByID(ctx context.Context, arg GetProhibitedPatternByIDParams) (ProhibitedPattern, error)
 	GetProhibitedPatternsBySessionID(ctx context.Context, sessionID string) ([]ProhibitedPattern, error)
+	GetRequestVoteScore(ctx context.Context, requestID int64) (int64, error)
+	GetRequestedTrackStatuses(ctx context.Context, sessionID string) ([]GetRequestedTrackStatusesRow, error)
+	GetScheduledPlayByID(ctx context.Context, arg GetScheduledPlayByIDParams) (ScheduledPlay, error)
+	GetScheduledPlaysBySessionID(ctx context.Context, sessionID string) ([]ScheduledPlay, error)
+	GetScheduledPlaysByStatus(ctx context.Context, status string) ([]ScheduledPlay, error)
 	GetSessionByAdminCredentials(ctx context.Context, arg GetSessionByAdminCredentialsParams) (Session, error)
 	GetSessionByFriendKey(ctx context.Context, friendAccessKey string) (Session, error)
 	GetSessionByID(ctx context.Context, id string) (Session, error)
 	GetSongRequestByID(ctx context.Context, id int64) (SongRequest, error)
+	GetSongRequestsByRequester(ctx context.Context, arg GetSongRequestsByRequesterParams) ([]SongRequest, error)
 	GetSongRequestsBySessionID(ctx context.Context, sessionID string) ([]SongRequest, error)
-	IsDuplicateRequest(ctx context.Context, arg IsDuplicateRequestParams) (int64, error)
 	ListAllSessions(ctx context.Context) ([]Session, error)
-	RejectSongRequest(ctx context.Context, arg RejectSongRequestParams) error
-	SaveLoungeCredentials(ctx context.Context, arg SaveLoungeCredentialsParams) error
+	ListAuditEvents(ctx context.Context, arg ListAuditEventsParams) ([]AuditEvent, error)
+	ListSessionsWithFallback(ctx context.Context) ([]Session, error)
+	MarkLoungeCommandFailed(ctx context.Context, arg MarkLoungeCommandFailedParams) error
+	MarkLoungeCommandSent(ctx context.Context, id int64) error
+	MarkOutboxEntryDelivered(ctx context.Context, id int64) (int64, error)
+	MarkOutboxEntryFailed(ctx context.Context, arg MarkOutboxEntryFailedParams) error
+	MarkScheduledPlayFailed(ctx context.Context, arg MarkScheduledPlayFailedParams) (sql.Result, error)
+	MarkScheduledPlayFired(ctx context.Context, id int64) (sql.Result, error)
+	RejectSongRequest(ctx context.Context, arg RejectSongRequestParams) (int64, error)
+	ReleaseLoungeLease(ctx context.Context, arg ReleaseLoungeLeaseParams) error
+	RenewLoungeLease(ctx context.Context, arg RenewLoungeLeaseParams) (int64, error)
+	RetryOutboxEntry(ctx context.Context, arg RetryOutboxEntryParams) error
+	RevertSongRequest(ctx context.Context, arg RevertSongRequestParams) (sql.Result, error)
+	SetPrimaryLoungeScreen(ctx context.Context, arg SetPrimaryLoungeScreenParams) error
+	SetSongRequestDelivery(ctx context.Context, arg SetSongRequestDeliveryParams) error
+	SetSongRequestNoteHidden(ctx context.Context, arg SetSongRequestNoteHiddenParams) error
+	UpdateAutoModerationRule(ctx context.Context, arg UpdateAutoModerationRuleParams) (sql.Result, error)
+	UpdateSessionDuplicateWindow(ctx context.Context, arg UpdateSessionDuplicateWindowParams) error
+	UpdateSessionFallback(ctx context.Context, arg UpdateSessionFallbackParams) error
+	UpdateSessionFallbackPosition(ctx context.Context, arg UpdateSessionFallbackPositionParams) error
+	UpdateSessionLoungeTarget(ctx context.Context, arg UpdateSessionLoungeTargetParams) error
 	UpdateSessionPlaylist(ctx context.Context, arg UpdateSessionPlaylistParams) error
 	UpdateSessionSettings(ctx context.Context, arg UpdateSessionSettingsParams) error
+	UpdateSessionTrackConstraints(ctx context.Context, arg UpdateSessionTrackConstraintsParams) error
+	UpsertLoungeScreen(ctx context.Context, arg UpsertLoungeScreenParams) (LoungeScreen, error)
+	UpsertRequestVote(ctx context.Context, arg UpsertRequestVoteParams) error
+	WithdrawSongRequest(ctx context.Context, arg WithdrawSongRequestParams) (sql.Result, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -10,19 +10,10 @@ import (
 	"database/sql"
 )
 
-const clearLoungeCredentials = `-- name: ClearLoungeCredentials :exec
-UPDATE sessions SET lounge_screen_id = NULL, lounge_token = NULL, lounge_screen_name = NULL, updated_at = CURRENT_TIMESTAMP WHERE id = ?
-`
-
-func (q *Queries) ClearLoungeCredentials(ctx context.Context, id string) error {
-	_, err := q.db.ExecContext(ctx, clearLoungeCredentials, id)
-	return err
-}
-
 const createSession = `-- name: CreateSession :one
 INSERT INTO sessions (id, display_name, admin_name, admin_password_hash, friend_access_key, spotify_playlist_id, song_duration_limit_ms, music_service)
 VALUES (?, ?, ?, ?, ?, ?, ?, ?)
-RETURNING id, display_name, admin_name, admin_password_hash, friend_access_key, spotify_playlist_id, song_duration_limit_ms, created_at, updated_at, spotify_playlist_name, music_service, lounge_screen_id, lounge_token, lounge_screen_name
+RETURNING id, display_name, admin_name, admin_password_hash, friend_access_key, spotify_playlist_id, song_duration_limit_ms, created_at, updated_at, spotify_playlist_name, music_service, lounge_target, duplicate_window_minutes, fallback_service, fallback_playlist_id, fallback_position, track_constraints
 `
 
 type CreateSessionParams struct {
@@ -60,9 +51,12 @@ func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (S
 		&i.UpdatedAt,
 		&i.SpotifyPlaylistName,
 		&i.MusicService,
-		&i.LoungeScreenID,
-		&i.LoungeToken,
-		&i.LoungeScreenName,
+		&i.LoungeTarget,
+		&i.DuplicateWindowMinutes,
+		&i.FallbackService,
+		&i.FallbackPlaylistID,
+		&i.FallbackPosition,
+		&i.TrackConstraints,
 	)
 	return i, err
 }
@@ -87,25 +81,8 @@ func (q *Queries) FriendKeyExists(ctx context.Context, friendAccessKey string) (
 	return exists_flag, err
 }
 
-const getLoungeCredentials = `-- name: GetLoungeCredentials :one
-SELECT lounge_screen_id, lounge_token, lounge_screen_name FROM sessions WHERE id = ?
-`
-
-type GetLoungeCredentialsRow struct {
-	LoungeScreenID   sql.NullString `json:"lounge_screen_id"`
-	LoungeToken      sql.NullString `json:"lounge_token"`
-	LoungeScreenName sql.NullString `json:"lounge_screen_name"`
-}
-
-func (q *Queries) GetLoungeCredentials(ctx context.Context, id string) (GetLoungeCredentialsRow, error) {
-	row := q.db.QueryRowContext(ctx, getLoungeCredentials, id)
-	var i GetLoungeCredentialsRow
-	err := row.Scan(&i.LoungeScreenID, &i.LoungeToken, &i.LoungeScreenName)
-	return i, err
-}
-
 const getSessionByAdminCredentials = `-- name: GetSessionByAdminCredentials :one
-SELECT id, display_name, admin_name, admin_password_hash, friend_access_key, spotify_playlist_id, song_duration_limit_ms, created_at, updated_at, spotify_playlist_name, music_service, lounge_screen_id, lounge_token, lounge_screen_name FROM sessions WHERE admin_name = ? AND admin_password_hash = ?
+SELECT id, display_name, admin_name, admin_password_hash, friend_access_key, spotify_playlist_id, song_duration_limit_ms, created_at, updated_at, spotify_playlist_name, music_service, lounge_target, duplicate_window_minutes, fallback_service, fallback_playlist_id, fallback_position, track_constraints FROM sessions WHERE admin_name = ? AND admin_password_hash = ?
 `
 
 type GetSessionByAdminCredentialsParams struct {
@@ -128,15 +105,18 @@ func (q *Queries) GetSessionByAdminCredentials(ctx context.Context, arg GetSessi
 		&i.UpdatedAt,
 		&i.SpotifyPlaylistName,
 		&i.MusicService,
-		&i.LoungeScreenID,
-		&i.LoungeToken,
-		&i.LoungeScreenName,
+		&i.LoungeTarget,
+		&i.DuplicateWindowMinutes,
+		&i.FallbackService,
+		&i.FallbackPlaylistID,
+		&i.FallbackPosition,
+		&i.TrackConstraints,
 	)
 	return i, err
 }
 
 const getSessionByFriendKey = `-- name: GetSessionByFriendKey :one
-SELECT id, display_name, admin_name, admin_password_hash, friend_access_key, spotify_playlist_id, song_duration_limit_ms, created_at, updated_at, spotify_playlist_name, music_service, lounge_screen_id, lounge_token, lounge_screen_name FROM sessions WHERE friend_access_key = ?
+SELECT id, display_name, admin_name, admin_password_hash, friend_access_key, spotify_playlist_id, song_duration_limit_ms, created_at, updated_at, spotify_playlist_name, music_service, lounge_target, duplicate_window_minutes, fallback_service, fallback_playlist_id, fallback_position, track_constraints FROM sessions WHERE friend_access_key = ?
 `
 
 func (q *Queries) GetSessionByFriendKey(ctx context.Context, friendAccessKey string) (Session, error) {
@@ -154,15 +134,18 @@ func (q *Queries) GetSessionByFriendKey(ctx context.Context, friendAccessKey str
 		&i.UpdatedAt,
 		&i.SpotifyPlaylistName,
 		&i.MusicService,
-		&i.LoungeScreenID,
-		&i.LoungeToken,
-		&i.LoungeScreenName,
+		&i.LoungeTarget,
+		&i.DuplicateWindowMinutes,
+		&i.FallbackService,
+		&i.FallbackPlaylistID,
+		&i.FallbackPosition,
+		&i.TrackConstraints,
 	)
 	return i, err
 }
 
 const getSessionByID = `-- name: GetSessionByID :one
-SELECT id, display_name, admin_name, admin_password_hash, friend_access_key, spotify_playlist_id, song_duration_limit_ms, created_at, updated_at, spotify_playlist_name, music_service, lounge_screen_id, lounge_token, lounge_screen_name FROM sessions WHERE id = ?
+SELECT id, display_name, admin_name, admin_password_hash, friend_access_key, spotify_playlist_id, song_duration_limit_ms, created_at, updated_at, spotify_playlist_name, music_service, lounge_target, duplicate_window_minutes, fallback_service, fallback_playlist_id, fallback_position, track_constraints FROM sessions WHERE id = ?
 `
 
 func (q *Queries) GetSessionByID(ctx context.Context, id string) (Session, error) {
@@ -180,15 +163,18 @@ func (q *Queries) GetSessionByID(ctx context.Context, id string) (Session, error
 		&i.UpdatedAt,
 		&i.SpotifyPlaylistName,
 		&i.MusicService,
-		&i.LoungeScreenID,
-		&i.LoungeToken,
-		&i.LoungeScreenName,
+		&i.LoungeTarget,
+		&i.DuplicateWindowMinutes,
+		&i.FallbackService,
+		&i.FallbackPlaylistID,
+		&i.FallbackPosition,
+		&i.TrackConstraints,
 	)
 	return i, err
 }
 
 const listAllSessions = `-- name: ListAllSessions :many
-SELECT id, display_name, admin_name, admin_password_hash, friend_access_key, spotify_playlist_id, song_duration_limit_ms, created_at, updated_at, spotify_playlist_name, music_service, lounge_screen_id, lounge_token, lounge_screen_name FROM sessions
+SELECT id, display_name, admin_name, admin_password_hash, friend_access_key, spotify_playlist_id, song_duration_limit_ms, created_at, updated_at, spotify_playlist_name, music_service, lounge_target, duplicate_window_minutes, fallback_service, fallback_playlist_id, fallback_position, track_constraints FROM sessions
 `
 
 func (q *Queries) ListAllSessions(ctx context.Context) ([]Session, error) {
@@ -212,9 +198,12 @@ func (q *Queries) ListAllSessions(ctx context.Context) ([]Session, error) {
 			&i.UpdatedAt,
 			&i.SpotifyPlaylistName,
 			&i.MusicService,
-			&i.LoungeScreenID,
-			&i.LoungeToken,
-			&i.LoungeScreenName,
+			&i.LoungeTarget,
+			&i.DuplicateWindowMinutes,
+			&i.FallbackService,
+			&i.FallbackPlaylistID,
+			&i.FallbackPosition,
+			&i.TrackConstraints,
 		); err != nil {
 			return nil, err
 		}
@@ -229,24 +218,105 @@ func (q *Queries) ListAllSessions(ctx context.Context) ([]Session, error) {
 	return items, nil
 }
 
-const saveLoungeCredentials = `-- name: SaveLoungeCredentials :exec
-UPDATE sessions SET lounge_screen_id = ?, lounge_token = ?, lounge_screen_name = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?
+const listSessionsWithFallback = `-- name: ListSessionsWithFallback :many
+SELECT id, display_name, admin_name, admin_password_hash, friend_access_key, spotify_playlist_id, song_duration_limit_ms, created_at, updated_at, spotify_playlist_name, music_service, lounge_target, duplicate_window_minutes, fallback_service, fallback_playlist_id, fallback_position, track_constraints FROM sessions WHERE fallback_playlist_id IS NOT NULL
 `
 
-type SaveLoungeCredentialsParams struct {
-	LoungeScreenID   sql.NullString `json:"lounge_screen_id"`
-	LoungeToken      sql.NullString `json:"lounge_token"`
-	LoungeScreenName sql.NullString `json:"lounge_screen_name"`
-	ID               string         `json:"id"`
+func (q *Queries) ListSessionsWithFallback(ctx context.Context) ([]Session, error) {
+	rows, err := q.db.QueryContext(ctx, listSessionsWithFallback)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Session
+	for rows.Next() {
+		var i Session
+		if err := rows.Scan(
+			&i.ID,
+			&i.DisplayName,
+			&i.AdminName,
+			&i.AdminPasswordHash,
+			&i.FriendAccessKey,
+			&i.SpotifyPlaylistID,
+			&i.SongDurationLimitMs,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.SpotifyPlaylistName,
+			&i.MusicService,
+			&i.LoungeTarget,
+			&i.DuplicateWindowMinutes,
+			&i.FallbackService,
+			&i.FallbackPlaylistID,
+			&i.FallbackPosition,
+			&i.TrackConstraints,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-func (q *Queries) SaveLoungeCredentials(ctx context.Context, arg SaveLoungeCredentialsParams) error {
-	_, err := q.db.ExecContext(ctx, saveLoungeCredentials,
-		arg.LoungeScreenID,
-		arg.LoungeToken,
-		arg.LoungeScreenName,
-		arg.ID,
-	)
+const updateSessionDuplicateWindow = `-- name: UpdateSessionDuplicateWindow :exec
+UPDATE sessions SET duplicate_window_minutes = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?
+`
+
+type UpdateSessionDuplicateWindowParams struct {
+	DuplicateWindowMinutes sql.NullInt64 `json:"duplicate_window_minutes"`
+	ID                     string        `json:"id"`
+}
+
+func (q *Queries) UpdateSessionDuplicateWindow(ctx context.Context, arg UpdateSessionDuplicateWindowParams) error {
+	_, err := q.db.ExecContext(ctx, updateSessionDuplicateWindow, arg.DuplicateWindowMinutes, arg.ID)
+	return err
+}
+
+const updateSessionFallback = `-- name: UpdateSessionFallback :exec
+UPDATE sessions SET fallback_service = ?, fallback_playlist_id = ?, fallback_position = 0, updated_at = CURRENT_TIMESTAMP WHERE id = ?
+`
+
+type UpdateSessionFallbackParams struct {
+	FallbackService    sql.NullString `json:"fallback_service"`
+	FallbackPlaylistID sql.NullString `json:"fallback_playlist_id"`
+	ID                 string         `json:"id"`
+}
+
+func (q *Queries) UpdateSessionFallback(ctx context.Context, arg UpdateSessionFallbackParams) error {
+	_, err := q.db.ExecContext(ctx, updateSessionFallback, arg.FallbackService, arg.FallbackPlaylistID, arg.ID)
+	return err
+}
+
+const updateSessionFallbackPosition = `-- name: UpdateSessionFallbackPosition :exec
+UPDATE sessions SET fallback_position = ? WHERE id = ?
+`
+
+type UpdateSessionFallbackPositionParams struct {
+	FallbackPosition int64  `json:"fallback_position"`
+	ID               string `json:"id"`
+}
+
+func (q *Queries) UpdateSessionFallbackPosition(ctx context.Context, arg UpdateSessionFallbackPositionParams) error {
+	_, err := q.db.ExecContext(ctx, updateSessionFallbackPosition, arg.FallbackPosition, arg.ID)
+	return err
+}
+
+const updateSessionLoungeTarget = `-- name: UpdateSessionLoungeTarget :exec
+UPDATE sessions SET lounge_target = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?
+`
+
+type UpdateSessionLoungeTargetParams struct {
+	LoungeTarget string `json:"lounge_target"`
+	ID           string `json:"id"`
+}
+
+func (q *Queries) UpdateSessionLoungeTarget(ctx context.Context, arg UpdateSessionLoungeTargetParams) error {
+	_, err := q.db.ExecContext(ctx, updateSessionLoungeTarget, arg.LoungeTarget, arg.ID)
 	return err
 }
 
@@ -278,3 +348,17 @@ func (q *Queries) UpdateSessionSettings(ctx context.Context, arg UpdateSessionSe
 	_, err := q.db.ExecContext(ctx, updateSessionSettings, arg.SongDurationLimitMs, arg.ID)
 	return err
 }
+
+const updateSessionTrackConstraints = `-- name: UpdateSessionTrackConstraints :exec
+UPDATE sessions SET track_constraints = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?
+`
+
+type UpdateSessionTrackConstraintsParams struct {
+	TrackConstraints sql.NullString `json:"track_constraints"`
+	ID               string         `json:"id"`
+}
+
+func (q *Queries) UpdateSessionTrackConstraints(ctx context.Context, arg UpdateSessionTrackConstraintsParams) error {
+	_, err := q.db.ExecContext(ctx, updateSessionTrackConstraints, arg.TrackConstraints, arg.ID)
+	return err
+}
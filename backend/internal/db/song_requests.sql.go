@@ -10,19 +10,38 @@ import (
 	"database/sql"
 )
 
-const approveSongRequest = `-- name: ApproveSongRequest :exec
-UPDATE song_requests SET status = 'approved', processed_at = CURRENT_TIMESTAMP WHERE id = ?
+const approveSongRequest = `-- name: ApproveSongRequest :execrows
+UPDATE song_requests SET status = 'approved', processed_at = CURRENT_TIMESTAMP, processed_by = ? WHERE id = ? AND status = 'pending'
 `
 
-func (q *Queries) ApproveSongRequest(ctx context.Context, id int64) error {
-	_, err := q.db.ExecContext(ctx, approveSongRequest, id)
-	return err
+type ApproveSongRequestParams struct {
+	ProcessedBy sql.NullString `json:"processed_by"`
+	ID          int64          `json:"id"`
+}
+
+func (q *Queries) ApproveSongRequest(ctx context.Context, arg ApproveSongRequestParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, approveSongRequest, arg.ProcessedBy, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const countPendingSongRequests = `-- name: CountPendingSongRequests :one
+SELECT COUNT(*) FROM song_requests WHERE session_id = ? AND status = 'pending'
+`
+
+func (q *Queries) CountPendingSongRequests(ctx context.Context, sessionID string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countPendingSongRequests, sessionID)
+	var None int64
+	err := row.Scan(&None)
+	return None, err
 }
 
 const createSongRequest = `-- name: CreateSongRequest :one
-INSERT INTO song_requests (session_id, external_track_id, track_name, artist_names, album_name, album_art_url, duration_ms, external_uri, requester_name)
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
-RETURNING id, session_id, external_track_id, track_name, artist_names, album_name, album_art_url, duration_ms, external_uri, status, requested_at, processed_at, rejection_reason, requester_name
+INSERT INTO song_requests (session_id, external_track_id, track_name, artist_names, album_name, album_art_url, duration_ms, external_uri, requester_name, note)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, session_id, external_track_id, track_name, artist_names, album_name, album_art_url, duration_ms, external_uri, status, requested_at, processed_at, rejection_reason, requester_name, note, note_hidden, processed_by, delivery_status, delivery_error
 `
 
 type CreateSongRequestParams struct {
@@ -35,6 +54,7 @@ type CreateSongRequestParams struct {
 	DurationMs      int64          `json:"duration_ms"`
 	ExternalUri     string         `json:"external_uri"`
 	RequesterName   sql.NullString `json:"requester_name"`
+	Note            sql.NullString `json:"note"`
 }
 
 func (q *Queries) CreateSongRequest(ctx context.Context, arg CreateSongRequestParams) (SongRequest, error) {
@@ -48,6 +68,7 @@ func (q *Queries) CreateSongRequest(ctx context.Context, arg CreateSongRequestPa
 		arg.DurationMs,
 		arg.ExternalUri,
 		arg.RequesterName,
+		arg.Note,
 	)
 	var i SongRequest
 	err := row.Scan(
@@ -65,6 +86,11 @@ func (q *Queries) CreateSongRequest(ctx context.Context, arg CreateSongRequestPa
 		&i.ProcessedAt,
 		&i.RejectionReason,
 		&i.RequesterName,
+		&i.Note,
+		&i.NoteHidden,
+		&i.ProcessedBy,
+		&i.DeliveryStatus,
+		&i.DeliveryError,
 	)
 	return i, err
 }
@@ -87,8 +113,55 @@ func (q *Queries) DeleteSongRequest(ctx context.Context, id int64) error {
 	return err
 }
 
+const getActiveSongRequests = `-- name: GetActiveSongRequests :many
+SELECT id, session_id, external_track_id, track_name, artist_names, album_name, album_art_url, duration_ms, external_uri, status, requested_at, processed_at, rejection_reason, requester_name, note, note_hidden, processed_by, delivery_status, delivery_error FROM song_requests WHERE session_id = ? AND status != 'rejected' ORDER BY requested_at DESC
+`
+
+func (q *Queries) GetActiveSongRequests(ctx context.Context, sessionID string) ([]SongRequest, error) {
+	rows, err := q.db.QueryContext(ctx, getActiveSongRequests, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SongRequest
+	for rows.Next() {
+		var i SongRequest
+		if err := rows.Scan(
+			&i.ID,
+			&i.SessionID,
+			&i.ExternalTrackID,
+			&i.TrackName,
+			&i.ArtistNames,
+			&i.AlbumName,
+			&i.AlbumArtUrl,
+			&i.DurationMs,
+			&i.ExternalUri,
+			&i.Status,
+			&i.RequestedAt,
+			&i.ProcessedAt,
+			&i.RejectionReason,
+			&i.RequesterName,
+			&i.Note,
+			&i.NoteHidden,
+			&i.ProcessedBy,
+			&i.DeliveryStatus,
+			&i.DeliveryError,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getPendingSongRequests = `-- name: GetPendingSongRequests :many
-SELECT id, session_id, external_track_id, track_name, artist_names, album_name, album_art_url, duration_ms, external_uri, status, requested_at, processed_at, rejection_reason, requester_name FROM song_requests WHERE session_id = ? AND status = 'pending' ORDER BY requested_at ASC
+SELECT id, session_id, external_track_id, track_name, artist_names, album_name, album_art_url, duration_ms, external_uri, status, requested_at, processed_at, rejection_reason, requester_name, note, note_hidden, processed_by, delivery_status, delivery_error FROM song_requests WHERE session_id = ? AND status = 'pending' ORDER BY requested_at ASC
 `
 
 func (q *Queries) GetPendingSongRequests(ctx context.Context, sessionID string) ([]SongRequest, error) {
@@ -115,6 +188,11 @@ func (q *Queries) GetPendingSongRequests(ctx context.Context, sessionID string)
 			&i.ProcessedAt,
 			&i.RejectionReason,
 			&i.RequesterName,
+			&i.Note,
+			&i.NoteHidden,
+			&i.ProcessedBy,
+			&i.DeliveryStatus,
+			&i.DeliveryError,
 		); err != nil {
 			return nil, err
 		}
@@ -129,8 +207,40 @@ func (q *Queries) GetPendingSongRequests(ctx context.Context, sessionID string)
 	return items, nil
 }
 
+const getRequestedTrackStatuses = `-- name: GetRequestedTrackStatuses :many
+SELECT external_track_id, status FROM song_requests WHERE session_id = ? AND status != 'rejected'
+`
+
+type GetRequestedTrackStatusesRow struct {
+	ExternalTrackID string `json:"external_track_id"`
+	Status          string `json:"status"`
+}
+
+func (q *Queries) GetRequestedTrackStatuses(ctx context.Context, sessionID string) ([]GetRequestedTrackStatusesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getRequestedTrackStatuses, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetRequestedTrackStatusesRow
+	for rows.Next() {
+		var i GetRequestedTrackStatusesRow
+		if err := rows.Scan(&i.ExternalTrackID, &i.Status); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getSongRequestByID = `-- name: GetSongRequestByID :one
-SELECT id, session_id, external_track_id, track_name, artist_names, album_name, album_art_url, duration_ms, external_uri, status, requested_at, processed_at, rejection_reason, requester_name FROM song_requests WHERE id = ?
+SELECT id, session_id, external_track_id, track_name, artist_names, album_name, album_art_url, duration_ms, external_uri, status, requested_at, processed_at, rejection_reason, requester_name, note, note_hidden, processed_by, delivery_status, delivery_error FROM song_requests WHERE id = ?
 `
 
 func (q *Queries) GetSongRequestByID(ctx context.Context, id int64) (SongRequest, error) {
@@ -151,12 +261,69 @@ func (q *Queries) GetSongRequestByID(ctx context.Context, id int64) (SongRequest
 		&i.ProcessedAt,
 		&i.RejectionReason,
 		&i.RequesterName,
+		&i.Note,
+		&i.NoteHidden,
+		&i.ProcessedBy,
+		&i.DeliveryStatus,
+		&i.DeliveryError,
 	)
 	return i, err
 }
 
+const getSongRequestsByRequester = `-- name: GetSongRequestsByRequester :many
+SELECT id, session_id, external_track_id, track_name, artist_names, album_name, album_art_url, duration_ms, external_uri, status, requested_at, processed_at, rejection_reason, requester_name, note, note_hidden, processed_by, delivery_status, delivery_error FROM song_requests WHERE session_id = ? AND requester_name = ? ORDER BY requested_at DESC
+`
+
+type GetSongRequestsByRequesterParams struct {
+	SessionID     string         `json:"session_id"`
+	RequesterName sql.NullString `json:"requester_name"`
+}
+
+func (q *Queries) GetSongRequestsByRequester(ctx context.Context, arg GetSongRequestsByRequesterParams) ([]SongRequest, error) {
+	rows, err := q.db.QueryContext(ctx, getSongRequestsByRequester, arg.SessionID, arg.RequesterName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SongRequest
+	for rows.Next() {
+		var i SongRequest
+		if err := rows.Scan(
+			&i.ID,
+			&i.SessionID,
+			&i.ExternalTrackID,
+			&i.TrackName,
+			&i.ArtistNames,
+			&i.AlbumName,
+			&i.AlbumArtUrl,
+			&i.DurationMs,
+			&i.ExternalUri,
+			&i.Status,
+			&i.RequestedAt,
+			&i.ProcessedAt,
+			&i.RejectionReason,
+			&i.RequesterName,
+			&i.Note,
+			&i.NoteHidden,
+			&i.ProcessedBy,
+			&i.DeliveryStatus,
+			&i.DeliveryError,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getSongRequestsBySessionID = `-- name: GetSongRequestsBySessionID :many
-SELECT id, session_id, external_track_id, track_name, artist_names, album_name, album_art_url, duration_ms, external_uri, status, requested_at, processed_at, rejection_reason, requester_name FROM song_requests WHERE session_id = ? ORDER BY requested_at DESC
+SELECT id, session_id, external_track_id, track_name, artist_names, album_name, album_art_url, duration_ms, external_uri, status, requested_at, processed_at, rejection_reason, requester_name, note, note_hidden, processed_by, delivery_status, delivery_error FROM song_requests WHERE session_id = ? ORDER BY requested_at DESC
 `
 
 func (q *Queries) GetSongRequestsBySessionID(ctx context.Context, sessionID string) ([]SongRequest, error) {
@@ -183,6 +350,11 @@ func (q *Queries) GetSongRequestsBySessionID(ctx context.Context, sessionID stri
 			&i.ProcessedAt,
 			&i.RejectionReason,
 			&i.RequesterName,
+			&i.Note,
+			&i.NoteHidden,
+			&i.ProcessedBy,
+			&i.DeliveryStatus,
+			&i.DeliveryError,
 		); err != nil {
 			return nil, err
 		}
@@ -197,35 +369,78 @@ func (q *Queries) GetSongRequestsBySessionID(ctx context.Context, sessionID stri
 	return items, nil
 }
 
-const isDuplicateRequest = `-- name: IsDuplicateRequest :one
-SELECT EXISTS(
-    SELECT 1 FROM song_requests
-    WHERE session_id = ? AND external_track_id = ? AND status != 'rejected'
-) AS is_duplicate
+const rejectSongRequest = `-- name: RejectSongRequest :execrows
+UPDATE song_requests SET status = 'rejected', processed_at = CURRENT_TIMESTAMP, rejection_reason = ?, processed_by = ? WHERE id = ? AND status = 'pending'
 `
 
-type IsDuplicateRequestParams struct {
-	SessionID       string `json:"session_id"`
-	ExternalTrackID string `json:"external_track_id"`
+type RejectSongRequestParams struct {
+	RejectionReason sql.NullString `json:"rejection_reason"`
+	ProcessedBy     sql.NullString `json:"processed_by"`
+	ID              int64          `json:"id"`
 }
 
-func (q *Queries) IsDuplicateRequest(ctx context.Context, arg IsDuplicateRequestParams) (int64, error) {
-	row := q.db.QueryRowContext(ctx, isDuplicateRequest, arg.SessionID, arg.ExternalTrackID)
-	var is_duplicate int64
-	err := row.Scan(&is_duplicate)
-	return is_duplicate, err
+func (q *Queries) RejectSongRequest(ctx context.Context, arg RejectSongRequestParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, rejectSongRequest, arg.RejectionReason, arg.ProcessedBy, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
-const rejectSongRequest = `-- name: RejectSongRequest :exec
-UPDATE song_requests SET status = 'rejected', processed_at = CURRENT_TIMESTAMP, rejection_reason = ? WHERE id = ?
+const revertSongRequest = `-- name: RevertSongRequest :execresult
+UPDATE song_requests SET status = 'pending', processed_at = NULL, processed_by = NULL, rejection_reason = NULL,
+    delivery_status = NULL, delivery_error = NULL
+WHERE id = ? AND status = ?
 `
 
-type RejectSongRequestParams struct {
-	RejectionReason sql.NullString `json:"rejection_reason"`
-	ID              int64          `json:"id"`
+type RevertSongRequestParams struct {
+	ID     int64  `json:"id"`
+	Status string `json:"status"`
+}
+
+func (q *Queries) RevertSongRequest(ctx context.Context, arg RevertSongRequestParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, revertSongRequest, arg.ID, arg.Status)
 }
 
-func (q *Queries) RejectSongRequest(ctx context.Context, arg RejectSongRequestParams) error {
-	_, err := q.db.ExecContext(ctx, rejectSongRequest, arg.RejectionReason, arg.ID)
+const setSongRequestDelivery = `-- name: SetSongRequestDelivery :exec
+UPDATE song_requests SET delivery_status = ?, delivery_error = ? WHERE id = ?
+`
+
+type SetSongRequestDeliveryParams struct {
+	DeliveryStatus sql.NullString `json:"delivery_status"`
+	DeliveryError  sql.NullString `json:"delivery_error"`
+	ID             int64          `json:"id"`
+}
+
+func (q *Queries) SetSongRequestDelivery(ctx context.Context, arg SetSongRequestDeliveryParams) error {
+	_, err := q.db.ExecContext(ctx, setSongRequestDelivery, arg.DeliveryStatus, arg.DeliveryError, arg.ID)
 	return err
 }
+
+const setSongRequestNoteHidden = `-- name: SetSongRequestNoteHidden :exec
+UPDATE song_requests SET note_hidden = ? WHERE id = ?
+`
+
+type SetSongRequestNoteHiddenParams struct {
+	NoteHidden bool  `json:"note_hidden"`
+	ID         int64 `json:"id"`
+}
+
+func (q *Queries) SetSongRequestNoteHidden(ctx context.Context, arg SetSongRequestNoteHiddenParams) error {
+	_, err := q.db.ExecContext(ctx, setSongRequestNoteHidden, arg.NoteHidden, arg.ID)
+	return err
+}
+
+const withdrawSongRequest = `-- name: WithdrawSongRequest :execresult
+DELETE FROM song_requests WHERE id = ? AND session_id = ? AND requester_name = ? AND status = 'pending'
+`
+
+type WithdrawSongRequestParams struct {
+	ID            int64          `json:"id"`
+	SessionID     string         `json:"session_id"`
+	RequesterName sql.NullString `json:"requester_name"`
+}
+
+func (q *Queries) WithdrawSongRequest(ctx context.Context, arg WithdrawSongRequestParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, withdrawSongRequest, arg.ID, arg.SessionID, arg.RequesterName)
+}
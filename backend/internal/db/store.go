@@ -0,0 +1,20 @@
+package db
+
+import "database/sql"
+
+// Store is the query set used by the application. Each database dialect has
+// its own generated queries: *Queries for SQLite here, and pgdb for
+// PostgreSQL, adapted to this package's types by database.NewQueries.
+type Store interface {
+	Querier
+
+	// InTx returns a Store that runs its queries in tx.
+	InTx(tx *sql.Tx) Store
+}
+
+// InTx returns a Store that runs its queries in tx.
+func (q *Queries) InTx(tx *sql.Tx) Store {
+	return q.WithTx(tx)
+}
+
+var _ Store = (*Queries)(nil)
@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/songify/backend/internal/db"
+	"github.com/songify/backend/internal/middleware"
+	"github.com/songify/backend/internal/models"
+	"github.com/songify/backend/internal/services"
+)
+
+// Audit actions recorded in audit_events.
+const (
+	auditSessionCreate         = "session.create"
+	auditDurationLimitUpdate   = "settings.duration_limit"
+	auditDuplicateWindowUpdate = "settings.duplicate_window"
+	auditPlaylistUpdate        = "settings.spotify_playlist"
+	auditFallbackUpdate        = "settings.fallback_playlist"
+	auditConstraintsUpdate     = "settings.track_constraints"
+	auditPatternCreate         = "pattern.create"
+	auditPatternDelete         = "pattern.delete"
+	auditAutoRuleCreate        = "auto_moderation.create"
+	auditAutoRuleUpdate        = "auto_moderation.update"
+	auditAutoRuleDelete        = "auto_moderation.delete"
+	auditRequestSubmit         = "request.submit"
+	auditRequestWithdraw       = "request.withdraw"
+	auditRequestApprove        = "request.approve"
+	auditRequestPlayNext       = "request.play_next"
+	auditRequestReject         = "request.reject"
+	auditRequestNote           = "request.note_visibility"
+	auditRequestUndo           = "request.undo"
+	auditRequestAutoApprove    = "request.auto_approve"
+	auditRequestAutoReject     = "request.auto_reject"
+	auditRequestsArchive       = "requests.archive"
+	auditScheduleCreate        = "schedule.create"
+	auditScheduleCancel        = "schedule.cancel"
+	auditLoungePair            = "lounge.pair"
+	auditLoungeDisconnect      = "lounge.disconnect"
+	auditLoungeReconnect       = "lounge.reconnect"
+	auditLoungePrimary         = "lounge.primary"
+	auditLoungeTarget          = "lounge.target"
+)
+
+// Audit target types recorded in audit_events.
+const (
+	auditTargetSession  = "session"
+	auditTargetRequest  = "song_request"
+	auditTargetPattern  = "prohibited_pattern"
+	auditTargetAutoRule = "auto_moderation_rule"
+	auditTargetScreen   = "lounge_screen"
+	auditTargetSchedule = "scheduled_play"
+)
+
+// auditRoleSystem is the role recorded for changes made by the server itself,
+// such as auto-moderation decisions.
+const auditRoleSystem = "system"
+
+const (
+	defaultAuditPageSize = 50
+	maxAuditPageSize     = 200
+)
+
+// auditEvent describes one change for the audit log. Before and After are
+// stored as JSON and may be nil. Actor and Role default to the caller's
+// claims.
+type auditEvent struct {
+	SessionID  string
+	Actor      string
+	Role       string
+	Action     string
+	TargetType string
+	TargetID   string
+	Before     any
+	After      any
+}
+
+// recordAudit appends an event to the audit log, attributed to the caller.
+// Use it inside a transaction when the change and its audit entry must be
+// committed together.
+func recordAudit(ctx context.Context, queries db.Querier, claims *services.Claims, event auditEvent) error {
+	if event.Actor == "" && claims != nil {
+		event.Actor = claims.Identity
+	}
+	if event.Role == "" && claims != nil {
+		event.Role = string(claims.Role)
+	}
+	before, err := auditValue(event.Before)
+	if err != nil {
+		return err
+	}
+	after, err := auditValue(event.After)
+	if err != nil {
+		return err
+	}
+	return queries.CreateAuditEvent(ctx, db.CreateAuditEventParams{
+		SessionID:   event.SessionID,
+		Actor:       event.Actor,
+		Role:        event.Role,
+		Action:      event.Action,
+		TargetType:  event.TargetType,
+		TargetID:    event.TargetID,
+		BeforeValue: before,
+		AfterValue:  after,
+	})
+}
+
+// auditChange records an event for a change that has already been made.
+// The change is not undone if the audit entry cannot be written; the failure
+// is logged instead.
+func auditChange(r *http.Request, queries db.Querier, event auditEvent) {
+	if err := recordAudit(r.Context(), queries, middleware.GetClaims(r.Context()), event); err != nil {
+		slog.ErrorContext(r.Context(), "audit: failed to record event",
+			slog.String("session_id", event.SessionID),
+			slog.String("action", event.Action),
+			slog.String("target_id", event.TargetID),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// auditValue encodes a before/after value as JSON, or NULL for nil.
+func auditValue(v any) (sql.NullString, error) {
+	if v == nil {
+		return sql.NullString{}, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return sql.NullString{}, fmt.Errorf("failed to encode audit value: %w", err)
+	}
+	return sql.NullString{String: string(b), Valid: true}, nil
+}
+
+// ListAuditEvents returns the session's audit log, newest first (admin only).
+// Pages are selected with limit and before, an event ID taken from the
+// previous page's nextBefore.
+func (h *SessionHandler) ListAuditEvents(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "id")
+	claims := middleware.GetClaims(r.Context())
+
+	if err := requireAdmin(claims, sessionID); err != nil {
+		writeError(w, http.StatusForbidden, "admin access required")
+		return
+	}
+
+	limit := int64(defaultAuditPageSize)
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 1 || n > maxAuditPageSize {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", maxAuditPageSize))
+			return
+		}
+		limit = n
+	}
+	before := int64(math.MaxInt64)
+	if v := r.URL.Query().Get("before"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 1 {
+			writeError(w, http.StatusBadRequest, "invalid before cursor")
+			return
+		}
+		before = n
+	}
+
+	// Fetch one extra row to know whether another page follows
+	events, err := h.queries.ListAuditEvents(r.Context(), db.ListAuditEventsParams{
+		SessionID: sessionID,
+		ID:        before,
+		Limit:     limit + 1,
+	})
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to fetch audit log", err)
+		return
+	}
+
+	resp := models.AuditLogResponse{Events: make([]models.AuditEventResponse, 0, len(events))}
+	if int64(len(events)) > limit {
+		events = events[:limit]
+		next := events[len(events)-1].ID
+		resp.NextBefore = &next
+	}
+	for _, event := range events {
+		resp.Events = append(resp.Events, auditEventToResponse(event))
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// auditEventToResponse converts a database audit event to the API response format.
+func auditEventToResponse(event db.AuditEvent) models.AuditEventResponse {
+	resp := models.AuditEventResponse{
+		ID:         event.ID,
+		Actor:      event.Actor,
+		Role:       event.Role,
+		Action:     event.Action,
+		TargetType: event.TargetType,
+		TargetID:   event.TargetID,
+		CreatedAt:  event.CreatedAt.Time,
+	}
+	if event.BeforeValue.Valid {
+		resp.Before = json.RawMessage(event.BeforeValue.String)
+	}
+	if event.AfterValue.Valid {
+		resp.After = json.RawMessage(event.AfterValue.String)
+	}
+	return resp
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/songify/backend/internal/broker"
+	"github.com/songify/backend/internal/database"
+	"github.com/songify/backend/internal/database/dbtest"
+	"github.com/songify/backend/internal/middleware"
+	"github.com/songify/backend/internal/models"
+	"github.com/songify/backend/internal/services"
+)
+
+func TestAuditLog(t *testing.T) {
+	sqlDB := dbtest.New(t)
+	queries := database.NewQueries(sqlDB)
+
+	dbtest.Session(t, queries, "s1")
+	songRequest := dbtest.SongRequests(t, queries, "s1", "a")[0]
+
+	requests := NewRequestHandler(sqlDB, queries, broker.New(), services.NewProviderRegistry(&fakeTV{}), time.Minute)
+	sessions := &SessionHandler{queries: queries}
+	admin := &services.Claims{SessionID: "s1", Role: services.RoleAdmin, Identity: "host"}
+
+	call := func(handler http.HandlerFunc, method, target string, body any, params map[string]string) *httptest.ResponseRecorder {
+		t.Helper()
+		var buf bytes.Buffer
+		if body != nil {
+			if err := json.NewEncoder(&buf).Encode(body); err != nil {
+				t.Fatal(err)
+			}
+		}
+		req := httptest.NewRequest(method, target, &buf)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", "s1")
+		for k, v := range params {
+			rctx.URLParams.Add(k, v)
+		}
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ClaimsKey, admin))
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec
+	}
+
+	rid := strconv.FormatInt(songRequest.ID, 10)
+	if rec := call(requests.Approve, http.MethodPut, "/approve", nil, map[string]string{"rid": rid}); rec.Code != http.StatusOK {
+		t.Fatalf("approve: status = %d: %s", rec.Code, rec.Body.String())
+	}
+	limit := int64(240000)
+	if rec := call(sessions.UpdateDurationLimit, http.MethodPut, "/settings/duration-limit",
+		models.UpdateDurationLimitRequest{SongDurationLimitMs: &limit}, nil); rec.Code != http.StatusOK {
+		t.Fatalf("duration limit: status = %d: %s", rec.Code, rec.Body.String())
+	}
+
+	list := func(query string) models.AuditLogResponse {
+		t.Helper()
+		rec := call(sessions.ListAuditEvents, http.MethodGet, "/audit?"+query, nil, nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("list %q: status = %d: %s", query, rec.Code, rec.Body.String())
+		}
+		var resp models.AuditLogResponse
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	page := list("limit=1")
+	if len(page.Events) != 1 || page.NextBefore == nil {
+		t.Fatalf("first page = %+v, want one event and a cursor", page)
+	}
+	settings := page.Events[0]
+	if settings.Action != auditDurationLimitUpdate || settings.Actor != "host" || settings.Role != "admin" {
+		t.Errorf("newest event = %+v, want duration limit change by host", settings)
+	}
+	if string(settings.Before) != `{"songDurationLimitMs":null}` || string(settings.After) != `{"songDurationLimitMs":240000}` {
+		t.Errorf("settings before/after = %s / %s", settings.Before, settings.After)
+	}
+
+	page = list("limit=1&before=" + strconv.FormatInt(*page.NextBefore, 10))
+	if len(page.Events) != 1 || page.NextBefore != nil {
+		t.Fatalf("last page = %+v, want one event and no cursor", page)
+	}
+	approve := page.Events[0]
+	if approve.Action != auditRequestApprove || approve.TargetType != auditTargetRequest || approve.TargetID != rid {
+		t.Errorf("oldest event = %+v, want approval of request %s", approve, rid)
+	}
+	var after models.SongRequestResponse
+	if err := json.Unmarshal(approve.After, &after); err != nil || after.Status != "approved" {
+		t.Errorf("approval after = %s, want the approved request", approve.After)
+	}
+
+	if rec := call(sessions.ListAuditEvents, http.MethodGet, "/audit?limit=0", nil, nil); rec.Code != http.StatusBadRequest {
+		t.Errorf("limit=0: status = %d, want 400", rec.Code)
+	}
+}
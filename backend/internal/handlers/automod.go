@@ -0,0 +1,593 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/songify/backend/internal/db"
+	"github.com/songify/backend/internal/middleware"
+	"github.com/songify/backend/internal/models"
+	"github.com/songify/backend/internal/services"
+)
+
+const (
+	autoModActionApprove = "approve"
+	autoModActionReject  = "reject"
+
+	// maxAutoModerationRuleName limits rule names, which are shown as processed_by.
+	maxAutoModerationRuleName = 60
+)
+
+// processedByAdmin marks requests moderated by hand.
+var processedByAdmin = sql.NullString{String: "admin", Valid: true}
+
+// processedByRule marks a request moderated automatically by a rule.
+func processedByRule(rule autoModRule) sql.NullString {
+	return sql.NullString{String: "auto:" + rule.Name, Valid: true}
+}
+
+// autoModRule is an auto-moderation rule with its conditions decoded.
+type autoModRule struct {
+	db.AutoModerationRule
+	Conditions models.AutoModerationConditions
+	after      int // Minutes past midnight, -1 when unset
+	before     int
+	location   *time.Location
+}
+
+// autoModInput is what rules are evaluated against for a new submission, or
+// for a pending request whose vote score changed.
+type autoModInput struct {
+	RequesterName string
+	ArtistNames   string
+	DurationMS    int64
+	QueueLength   int64 // Pending requests before this one
+	VoteScore     int64
+	Now           time.Time
+}
+
+// newAutoModRule decodes and validates a stored rule.
+func newAutoModRule(row db.AutoModerationRule) (autoModRule, error) {
+	rule := autoModRule{AutoModerationRule: row}
+	if err := json.Unmarshal([]byte(row.Conditions), &rule.Conditions); err != nil {
+		return rule, fmt.Errorf("auto-moderation rule %d: %w", row.ID, err)
+	}
+	if err := rule.compile(); err != nil {
+		return rule, fmt.Errorf("auto-moderation rule %d: %w", row.ID, err)
+	}
+	return rule, nil
+}
+
+// compile parses the rule's time window. Errors are user-facing.
+func (rule *autoModRule) compile() error {
+	c := rule.Conditions
+	var err error
+	if rule.after, err = parseClock(c.After); err != nil {
+		return fmt.Errorf("after: %w", err)
+	}
+	if rule.before, err = parseClock(c.Before); err != nil {
+		return fmt.Errorf("before: %w", err)
+	}
+	rule.location = time.UTC
+	if c.TimeZone != "" {
+		if rule.location, err = time.LoadLocation(c.TimeZone); err != nil {
+			return fmt.Errorf("unknown timeZone %q", c.TimeZone)
+		}
+	}
+	return nil
+}
+
+// parseClock parses "HH:MM" into minutes past midnight, or -1 for "".
+func parseClock(s string) (int, error) {
+	if s == "" {
+		return -1, nil
+	}
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, errors.New("time must be HH:MM")
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// matches reports whether every condition set on the rule holds for in.
+func (rule autoModRule) matches(in autoModInput) bool {
+	c := rule.Conditions
+
+	if len(c.RequesterNames) > 0 {
+		found := false
+		for _, name := range c.RequesterNames {
+			if strings.EqualFold(strings.TrimSpace(name), in.RequesterName) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(c.Artists) > 0 && !containsAnyIgnoreCase(in.ArtistNames, c.Artists) {
+		return false
+	}
+	if containsAnyIgnoreCase(in.ArtistNames, c.ExcludeArtists) {
+		return false
+	}
+	if c.MinDurationMS != nil && in.DurationMS < *c.MinDurationMS {
+		return false
+	}
+	if c.MaxDurationMS != nil && in.DurationMS > *c.MaxDurationMS {
+		return false
+	}
+	if c.MinQueueLength != nil && in.QueueLength < *c.MinQueueLength {
+		return false
+	}
+	if c.MaxQueueLength != nil && in.QueueLength > *c.MaxQueueLength {
+		return false
+	}
+	if c.MinVoteScore != nil && in.VoteScore < *c.MinVoteScore {
+		return false
+	}
+	if c.MaxVoteScore != nil && in.VoteScore > *c.MaxVoteScore {
+		return false
+	}
+	return rule.inWindow(in.Now)
+}
+
+// inWindow reports whether now falls in the rule's time-of-day window.
+// A window whose end is earlier than its start wraps past midnight.
+func (rule autoModRule) inWindow(now time.Time) bool {
+	if rule.after < 0 && rule.before < 0 {
+		return true
+	}
+	local := now.In(rule.location)
+	minute := local.Hour()*60 + local.Minute()
+	switch {
+	case rule.before < 0:
+		return minute >= rule.after
+	case rule.after < 0:
+		return minute < rule.before
+	case rule.after <= rule.before:
+		return minute >= rule.after && minute < rule.before
+	default:
+		return minute >= rule.after || minute < rule.before
+	}
+}
+
+// containsAnyIgnoreCase reports whether s contains any non-blank needle.
+func containsAnyIgnoreCase(s string, needles []string) bool {
+	for _, needle := range needles {
+		if needle = strings.TrimSpace(needle); needle != "" && containsIgnoreCase(s, needle) {
+			return true
+		}
+	}
+	return false
+}
+
+// usesVoteScore reports whether the rule looks at the vote score, which
+// only means something once guests have voted.
+func (rule autoModRule) usesVoteScore() bool {
+	return rule.Conditions.MinVoteScore != nil || rule.Conditions.MaxVoteScore != nil
+}
+
+// firstMatchingRule returns the first enabled rule that matches in, or nil.
+// rules must already be in evaluation order.
+func firstMatchingRule(rules []autoModRule, in autoModInput) *autoModRule {
+	for i := range rules {
+		if rules[i].Enabled && rules[i].matches(in) {
+			return &rules[i]
+		}
+	}
+	return nil
+}
+
+// loadAutoModerationRules fetches a session's rules in evaluation order.
+func loadAutoModerationRules(ctx context.Context, queries db.Querier, sessionID string) ([]autoModRule, error) {
+	rows, err := queries.GetAutoModerationRulesBySessionID(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	rules := make([]autoModRule, 0, len(rows))
+	for _, row := range rows {
+		rule, err := newAutoModRule(row)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// applyAutoModeration approves or rejects a pending request according
+// to rule. An approved song is queued on the session's playback target in
+// the background. A request moderated by someone else in the meantime is
+// left alone. Returns the request as it stands afterwards.
+func (h *RequestHandler) applyAutoModeration(ctx context.Context, session db.Session, songRequest db.SongRequest, rule *autoModRule) db.SongRequest {
+	logger := slog.With(
+		slog.String("session_id", session.ID),
+		slog.Int64("request_id", songRequest.ID),
+		slog.Int64("rule_id", rule.ID),
+		slog.String("action", rule.Action),
+	)
+
+	var err error
+	action := auditRequestAutoApprove
+	switch rule.Action {
+	case autoModActionApprove:
+		err = h.approveByRule(ctx, session, songRequest, rule)
+	case autoModActionReject:
+		action = auditRequestAutoReject
+		reason := rule.Reason
+		if !reason.Valid {
+			reason = sql.NullString{String: "Automatically rejected: " + rule.Name, Valid: true}
+		}
+		err = h.rejectByRule(ctx, songRequest, rule, reason)
+	}
+	if errors.Is(err, errNotPending) {
+		// Moderated by someone else in the meantime; leave their decision alone
+		logger.Info("auto-moderation: request no longer pending", slog.String("rule", rule.Name))
+		if current, err := h.queries.GetSongRequestByID(ctx, songRequest.ID); err == nil {
+			return current
+		}
+		return songRequest
+	}
+	if err != nil {
+		logger.Error("auto-moderation: failed to update request", slog.String("error", err.Error()))
+		return songRequest
+	}
+
+	updated, err := h.queries.GetSongRequestByID(ctx, songRequest.ID)
+	if err != nil {
+		logger.Error("auto-moderation: failed to fetch updated request", slog.String("error", err.Error()))
+		return songRequest
+	}
+	logger.Info("auto-moderation: rule applied", slog.String("rule", rule.Name))
+
+	if err := recordAudit(ctx, h.queries, nil, auditEvent{
+		SessionID:  session.ID,
+		Actor:      processedByRule(*rule).String,
+		Role:       auditRoleSystem,
+		Action:     action,
+		TargetType: auditTargetRequest,
+		TargetID:   strconv.FormatInt(songRequest.ID, 10),
+		Before:     songRequestToResponse(songRequest),
+		After:      songRequestToResponse(updated),
+	}); err != nil {
+		logger.Error("auto-moderation: failed to record audit event", slog.String("error", err.Error()))
+	}
+	return updated
+}
+
+// approveByRule approves a request on behalf of rule and, in the same
+// transaction, queues it for the session's playback target. It returns
+// errNotPending if the request was already moderated.
+func (h *RequestHandler) approveByRule(ctx context.Context, session db.Session, songRequest db.SongRequest, rule *autoModRule) error {
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := h.queries.InTx(tx)
+
+	approved, err := qtx.ApproveSongRequest(ctx, db.ApproveSongRequestParams{ProcessedBy: processedByRule(*rule), ID: songRequest.ID})
+	if err != nil {
+		return err
+	}
+	if approved == 0 {
+		return errNotPending
+	}
+	if provider, err := h.providers.Get(session.MusicService); err == nil {
+		if provider.PlaybackTarget() != nil {
+			if err := services.QueueEffect(ctx, qtx, songRequest, services.EffectEnqueue); err != nil {
+				return err
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+// rejectByRule rejects a request on behalf of rule with reason. It returns
+// errNotPending if the request was already moderated.
+func (h *RequestHandler) rejectByRule(ctx context.Context, songRequest db.SongRequest, rule *autoModRule, reason sql.NullString) error {
+	rejected, err := h.queries.RejectSongRequest(ctx, db.RejectSongRequestParams{
+		RejectionReason: reason,
+		ProcessedBy:     processedByRule(*rule),
+		ID:              songRequest.ID,
+	})
+	if err != nil {
+		return err
+	}
+	if rejected == 0 {
+		return errNotPending
+	}
+	return nil
+}
+
+// validateAutoModerationRule checks a rule request and returns its stored
+// form. Errors are user-facing.
+func validateAutoModerationRule(req models.AutoModerationRuleRequest) (autoModRule, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return autoModRule{}, errors.New("name is required")
+	}
+	if len([]rune(req.Name)) > maxAutoModerationRuleName {
+		return autoModRule{}, fmt.Errorf("name must be %d characters or fewer", maxAutoModerationRuleName)
+	}
+	if req.Action != autoModActionApprove && req.Action != autoModActionReject {
+		return autoModRule{}, errors.New("action must be 'approve' or 'reject'")
+	}
+
+	c := req.Conditions
+	for _, v := range []*int64{c.MinDurationMS, c.MaxDurationMS, c.MinQueueLength, c.MaxQueueLength} {
+		if v != nil && *v < 0 {
+			return autoModRule{}, errors.New("conditions must not be negative")
+		}
+	}
+	if c.MinDurationMS != nil && c.MaxDurationMS != nil && *c.MinDurationMS > *c.MaxDurationMS {
+		return autoModRule{}, errors.New("minDurationMs must not exceed maxDurationMs")
+	}
+	if c.MinQueueLength != nil && c.MaxQueueLength != nil && *c.MinQueueLength > *c.MaxQueueLength {
+		return autoModRule{}, errors.New("minQueueLength must not exceed maxQueueLength")
+	}
+	if c.MinVoteScore != nil && c.MaxVoteScore != nil && *c.MinVoteScore > *c.MaxVoteScore {
+		return autoModRule{}, errors.New("minVoteScore must not exceed maxVoteScore")
+	}
+
+	rule := autoModRule{Conditions: c}
+	if err := rule.compile(); err != nil {
+		return autoModRule{}, err
+	}
+	conditions, err := json.Marshal(c)
+	if err != nil {
+		return autoModRule{}, err
+	}
+
+	rule.Name = req.Name
+	rule.Action = req.Action
+	rule.Reason = sql.NullString{String: strings.TrimSpace(req.Reason), Valid: strings.TrimSpace(req.Reason) != ""}
+	rule.AutoModerationRule.Conditions = string(conditions)
+	rule.Position = req.Position
+	rule.Enabled = req.Enabled == nil || *req.Enabled
+	return rule, nil
+}
+
+// autoModRuleToResponse converts a rule to the API response format.
+func autoModRuleToResponse(rule autoModRule) models.AutoModerationRuleResponse {
+	resp := models.AutoModerationRuleResponse{
+		ID:         rule.ID,
+		Name:       rule.Name,
+		Action:     rule.Action,
+		Conditions: rule.Conditions,
+		Position:   rule.Position,
+		Enabled:    rule.Enabled,
+	}
+	if rule.Reason.Valid {
+		resp.Reason = &rule.Reason.String
+	}
+	return resp
+}
+
+// GetAutoModerationRules returns the session's auto-moderation rules in
+// evaluation order (admin only).
+func (h *SessionHandler) GetAutoModerationRules(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "id")
+	claims := middleware.GetClaims(r.Context())
+
+	if err := requireAdmin(claims, sessionID); err != nil {
+		writeError(w, http.StatusForbidden, "admin access required")
+		return
+	}
+
+	rules, err := loadAutoModerationRules(r.Context(), h.queries, sessionID)
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to fetch rules", err)
+		return
+	}
+
+	response := make([]models.AutoModerationRuleResponse, len(rules))
+	for i, rule := range rules {
+		response[i] = autoModRuleToResponse(rule)
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// CreateAutoModerationRule adds a rule that approves or rejects new
+// submissions automatically (admin only).
+func (h *SessionHandler) CreateAutoModerationRule(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "id")
+	claims := middleware.GetClaims(r.Context())
+
+	if err := requireAdmin(claims, sessionID); err != nil {
+		writeError(w, http.StatusForbidden, "admin access required")
+		return
+	}
+
+	var req models.AutoModerationRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	rule, err := validateAutoModerationRule(req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	row, err := h.queries.CreateAutoModerationRule(r.Context(), db.CreateAutoModerationRuleParams{
+		SessionID:  sessionID,
+		Name:       rule.Name,
+		Action:     rule.Action,
+		Reason:     rule.Reason,
+		Conditions: rule.AutoModerationRule.Conditions,
+		Position:   rule.Position,
+		Enabled:    rule.Enabled,
+	})
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to create rule", err)
+		return
+	}
+	rule.AutoModerationRule = row
+	resp := autoModRuleToResponse(rule)
+
+	auditChange(r, h.queries, auditEvent{
+		SessionID:  sessionID,
+		Action:     auditAutoRuleCreate,
+		TargetType: auditTargetAutoRule,
+		TargetID:   strconv.FormatInt(row.ID, 10),
+		After:      resp,
+	})
+
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+// UpdateAutoModerationRule replaces a rule by its ID (admin only).
+func (h *SessionHandler) UpdateAutoModerationRule(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "id")
+	claims := middleware.GetClaims(r.Context())
+
+	if err := requireAdmin(claims, sessionID); err != nil {
+		writeError(w, http.StatusForbidden, "admin access required")
+		return
+	}
+
+	ruleID, err := strconv.ParseInt(chi.URLParam(r, "ruleId"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid rule ID")
+		return
+	}
+
+	var req models.AutoModerationRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	rule, err := validateAutoModerationRule(req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	existing, ok := h.autoModerationRule(w, r, sessionID, ruleID)
+	if !ok {
+		return
+	}
+
+	result, err := h.queries.UpdateAutoModerationRule(r.Context(), db.UpdateAutoModerationRuleParams{
+		Name:       rule.Name,
+		Action:     rule.Action,
+		Reason:     rule.Reason,
+		Conditions: rule.AutoModerationRule.Conditions,
+		Position:   rule.Position,
+		Enabled:    rule.Enabled,
+		ID:         ruleID,
+		SessionID:  sessionID,
+	})
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to update rule", err)
+		return
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to check update result", err)
+		return
+	}
+	if rowsAffected == 0 {
+		writeError(w, http.StatusNotFound, "rule not found")
+		return
+	}
+
+	rule.ID = ruleID
+	rule.SessionID = sessionID
+	resp := autoModRuleToResponse(rule)
+
+	auditChange(r, h.queries, auditEvent{
+		SessionID:  sessionID,
+		Action:     auditAutoRuleUpdate,
+		TargetType: auditTargetAutoRule,
+		TargetID:   strconv.FormatInt(ruleID, 10),
+		Before:     autoModRuleToResponse(existing),
+		After:      resp,
+	})
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// DeleteAutoModerationRule removes a rule by its ID (admin only).
+func (h *SessionHandler) DeleteAutoModerationRule(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "id")
+	claims := middleware.GetClaims(r.Context())
+
+	if err := requireAdmin(claims, sessionID); err != nil {
+		writeError(w, http.StatusForbidden, "admin access required")
+		return
+	}
+
+	ruleID, err := strconv.ParseInt(chi.URLParam(r, "ruleId"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid rule ID")
+		return
+	}
+
+	existing, ok := h.autoModerationRule(w, r, sessionID, ruleID)
+	if !ok {
+		return
+	}
+
+	result, err := h.queries.DeleteAutoModerationRule(r.Context(), db.DeleteAutoModerationRuleParams{
+		ID:        ruleID,
+		SessionID: sessionID,
+	})
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to delete rule", err)
+		return
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to check deletion result", err)
+		return
+	}
+	if rowsAffected == 0 {
+		writeError(w, http.StatusNotFound, "rule not found")
+		return
+	}
+
+	auditChange(r, h.queries, auditEvent{
+		SessionID:  sessionID,
+		Action:     auditAutoRuleDelete,
+		TargetType: auditTargetAutoRule,
+		TargetID:   strconv.FormatInt(ruleID, 10),
+		Before:     autoModRuleToResponse(existing),
+	})
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// autoModerationRule fetches one of the session's rules. On failure an error
+// response is written and ok is false.
+func (h *SessionHandler) autoModerationRule(w http.ResponseWriter, r *http.Request, sessionID string, ruleID int64) (rule autoModRule, ok bool) {
+	row, err := h.queries.GetAutoModerationRuleByID(r.Context(), db.GetAutoModerationRuleByIDParams{
+		ID:        ruleID,
+		SessionID: sessionID,
+	})
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusNotFound, "rule not found", err)
+		return rule, false
+	}
+	rule, err = newAutoModRule(row)
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to decode rule", err)
+		return rule, false
+	}
+	return rule, true
+}
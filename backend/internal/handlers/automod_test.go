@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/songify/backend/internal/broker"
+	"github.com/songify/backend/internal/database"
+	"github.com/songify/backend/internal/database/dbtest"
+	"github.com/songify/backend/internal/db"
+	"github.com/songify/backend/internal/middleware"
+	"github.com/songify/backend/internal/models"
+	"github.com/songify/backend/internal/services"
+)
+
+func TestAutoModRuleMatches(t *testing.T) {
+	i64 := func(v int64) *int64 { return &v }
+	// 23:30 in UTC, 01:30 in Berlin (summer time)
+	now := time.Date(2024, 6, 1, 23, 30, 0, 0, time.UTC)
+	in := autoModInput{RequesterName: "Sam", ArtistNames: "Daft Punk, Pharrell Williams", DurationMS: 240000, QueueLength: 3, VoteScore: 2, Now: now}
+
+	tests := []struct {
+		name       string
+		conditions models.AutoModerationConditions
+		want       bool
+	}{
+		{"no conditions", models.AutoModerationConditions{}, true},
+		{"requester", models.AutoModerationConditions{RequesterNames: []string{"alex", " sam "}}, true},
+		{"other requester", models.AutoModerationConditions{RequesterNames: []string{"Alex"}}, false},
+		{"artist allowed", models.AutoModerationConditions{Artists: []string{"pharrell"}}, true},
+		{"artist not allowed", models.AutoModerationConditions{Artists: []string{"Metallica"}}, false},
+		{"artist excluded", models.AutoModerationConditions{ExcludeArtists: []string{"daft punk"}}, false},
+		{"blank exclusion ignored", models.AutoModerationConditions{ExcludeArtists: []string{" "}}, true},
+		{"duration in range", models.AutoModerationConditions{MinDurationMS: i64(60000), MaxDurationMS: i64(300000)}, true},
+		{"too long", models.AutoModerationConditions{MaxDurationMS: i64(180000)}, false},
+		{"queue short enough", models.AutoModerationConditions{MaxQueueLength: i64(3)}, true},
+		{"queue too short", models.AutoModerationConditions{MinQueueLength: i64(5)}, false},
+		{"popular enough", models.AutoModerationConditions{MinVoteScore: i64(2)}, true},
+		{"not popular enough", models.AutoModerationConditions{MinVoteScore: i64(3)}, false},
+		{"too popular to reject", models.AutoModerationConditions{MaxVoteScore: i64(-2)}, false},
+		{"window wraps midnight", models.AutoModerationConditions{After: "22:00", Before: "02:00"}, true},
+		{"outside window", models.AutoModerationConditions{After: "18:00", Before: "23:00"}, false},
+		{"only after", models.AutoModerationConditions{After: "23:30"}, true},
+		{"only before", models.AutoModerationConditions{Before: "23:30"}, false},
+		{"window in time zone", models.AutoModerationConditions{After: "01:00", Before: "02:00", TimeZone: "Europe/Berlin"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := autoModRule{Conditions: tt.conditions}
+			if err := rule.compile(); err != nil {
+				t.Fatal(err)
+			}
+			if got := rule.matches(in); got != tt.want {
+				t.Errorf("matches = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFirstMatchingRule(t *testing.T) {
+	rule := func(id int64, enabled bool, artists ...string) autoModRule {
+		r := autoModRule{AutoModerationRule: db.AutoModerationRule{ID: id, Enabled: enabled}, Conditions: models.AutoModerationConditions{Artists: artists}}
+		if err := r.compile(); err != nil {
+			t.Fatal(err)
+		}
+		return r
+	}
+	rules := []autoModRule{rule(1, false, "ABBA"), rule(2, true, "Metallica"), rule(3, true, "ABBA"), rule(4, true)}
+
+	if got := firstMatchingRule(rules, autoModInput{ArtistNames: "ABBA"}); got == nil || got.ID != 3 {
+		t.Errorf("got %+v, want rule 3 (rule 1 is disabled)", got)
+	}
+	if got := firstMatchingRule(rules, autoModInput{ArtistNames: "Queen"}); got == nil || got.ID != 4 {
+		t.Errorf("got %+v, want catch-all rule 4", got)
+	}
+	if got := firstMatchingRule(rules[:3], autoModInput{ArtistNames: "Queen"}); got != nil {
+		t.Errorf("got rule %d, want no match", got.ID)
+	}
+}
+
+func TestValidateAutoModerationRule(t *testing.T) {
+	neg := int64(-1)
+	tests := []struct {
+		name    string
+		req     models.AutoModerationRuleRequest
+		wantErr bool
+	}{
+		{"valid", models.AutoModerationRuleRequest{Name: "No metal", Action: "reject", Conditions: models.AutoModerationConditions{Artists: []string{"Metallica"}}}, false},
+		{"missing name", models.AutoModerationRuleRequest{Action: "approve"}, true},
+		{"bad action", models.AutoModerationRuleRequest{Name: "x", Action: "skip"}, true},
+		{"bad time", models.AutoModerationRuleRequest{Name: "x", Action: "approve", Conditions: models.AutoModerationConditions{After: "25:00"}}, true},
+		{"bad time zone", models.AutoModerationRuleRequest{Name: "x", Action: "approve", Conditions: models.AutoModerationConditions{TimeZone: "Mars/Base"}}, true},
+		{"negative", models.AutoModerationRuleRequest{Name: "x", Action: "approve", Conditions: models.AutoModerationConditions{MaxQueueLength: &neg}}, true},
+		{"negative score", models.AutoModerationRuleRequest{Name: "x", Action: "reject", Conditions: models.AutoModerationConditions{MaxVoteScore: &neg}}, false},
+		{"score range inverted", models.AutoModerationRuleRequest{Name: "x", Action: "reject", Conditions: models.AutoModerationConditions{MinVoteScore: new(int64), MaxVoteScore: &neg}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := validateAutoModerationRule(tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !rule.Enabled {
+				t.Error("rule should default to enabled")
+			}
+		})
+	}
+}
+
+func TestSubmitAppliesAutoModeration(t *testing.T) {
+	ctx := context.Background()
+	sqlDB := dbtest.New(t)
+	queries := database.NewQueries(sqlDB)
+
+	dbtest.Session(t, queries, "s1")
+	for _, req := range []models.AutoModerationRuleRequest{
+		{Name: "No metal", Action: "reject", Reason: "Not tonight", Conditions: models.AutoModerationConditions{Artists: []string{"Metallica"}}},
+		{Name: "Trusted", Action: "approve", Position: 1, Conditions: models.AutoModerationConditions{RequesterNames: []string{"Sam"}}},
+		// Would match every new request's score of 0 if applied before any votes
+		{Name: "Unloved", Action: "reject", Position: 2, Conditions: models.AutoModerationConditions{MaxVoteScore: new(int64)}},
+	} {
+		rule, err := validateAutoModerationRule(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := queries.CreateAutoModerationRule(ctx, db.CreateAutoModerationRuleParams{
+			SessionID: "s1", Name: rule.Name, Action: rule.Action, Reason: rule.Reason,
+			Conditions: rule.AutoModerationRule.Conditions, Position: rule.Position, Enabled: rule.Enabled,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tv := &fakeTV{}
+	providers := services.NewProviderRegistry(tv)
+	h := NewRequestHandler(sqlDB, queries, broker.New(), providers, time.Minute)
+
+	submit := func(identity, trackID, artist string) models.SongRequestResponse {
+		t.Helper()
+		body, _ := json.Marshal(models.SubmitSongRequestRequest{
+			ExternalTrackID: trackID, TrackName: trackID, ArtistNames: artist, DurationMS: 200000, ExternalURI: trackID,
+		})
+		req := httptest.NewRequest(http.MethodPost, "/api/sessions/s1/requests", bytes.NewReader(body))
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", "s1")
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ClaimsKey, &services.Claims{SessionID: "s1", Role: services.RoleFriend, Identity: identity}))
+		rec := httptest.NewRecorder()
+		h.Submit(rec, req)
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("status = %d: %s", rec.Code, rec.Body.String())
+		}
+		var resp models.SongRequestResponse
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	// Earlier rules win: Sam is trusted, but metal is rejected first
+	resp := submit("Sam", "enter-sandman", "Metallica")
+	if resp.Status != "rejected" || resp.ProcessedBy == nil || *resp.ProcessedBy != "auto:No metal" || *resp.RejectionReason != "Not tonight" {
+		t.Errorf("metal request = %+v, want rejected by No metal", resp)
+	}
+
+	resp = submit("Sam", "one-more-time", "Daft Punk")
+	if resp.Status != "approved" || resp.ProcessedBy == nil || *resp.ProcessedBy != "auto:Trusted" {
+		t.Errorf("trusted request = %+v, want approved by Trusted", resp)
+	}
+	deliverEffects(queries, providers)
+	if len(tv.queued) != 1 || tv.queued[0] != "one-more-time" {
+		t.Errorf("queued = %v, want the approved song sent to the TV", tv.queued)
+	}
+
+	resp = submit("Alex", "around-the-world", "Daft Punk")
+	if resp.Status != "pending" || resp.ProcessedBy != nil {
+		t.Errorf("untrusted request = %+v, want pending until guests vote", resp)
+	}
+}
+
+func TestAutoModerationLeavesModeratedRequests(t *testing.T) {
+	ctx := context.Background()
+	sqlDB := dbtest.New(t)
+	queries := database.NewQueries(sqlDB)
+
+	session := dbtest.Session(t, queries, "s1")
+	stale := dbtest.SongRequests(t, queries, "s1", "a")[0]
+	if _, err := queries.ApproveSongRequest(ctx, db.ApproveSongRequestParams{ProcessedBy: processedByAdmin, ID: stale.ID}); err != nil {
+		t.Fatal(err)
+	}
+
+	h := NewRequestHandler(sqlDB, queries, broker.New(), services.NewProviderRegistry(&fakeTV{}), time.Minute)
+	rule, err := validateAutoModerationRule(models.AutoModerationRuleRequest{Name: "Booed", Action: "reject"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The rule saw the request while it was pending, but an admin got there first
+	got := h.applyAutoModeration(ctx, session, stale, &rule)
+	if got.Status != "approved" || got.ProcessedBy != processedByAdmin {
+		t.Errorf("request = %s by %s, want the admin's approval left alone", got.Status, got.ProcessedBy.String)
+	}
+	events, err := queries.ListAuditEvents(ctx, db.ListAuditEventsParams{SessionID: "s1", ID: math.MaxInt64, Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 0 {
+		t.Errorf("audit events = %+v, want none for a rule that did nothing", events)
+	}
+}
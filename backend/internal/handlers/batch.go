@@ -0,0 +1,223 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/songify/backend/internal/db"
+	"github.com/songify/backend/internal/middleware"
+	"github.com/songify/backend/internal/models"
+	"github.com/songify/backend/internal/services"
+)
+
+// errNotPending is reported for batch IDs that are not pending in the session.
+var errNotPending = errors.New("request is not pending in this session")
+
+// errFilterMismatch is reported for batch IDs excluded by the batch filter.
+var errFilterMismatch = errors.New("request does not match the filter")
+
+// BatchApprove approves several pending requests in one transaction (admin only).
+// When the session has a playback target each song is queued on it in order,
+// in the background.
+func (h *RequestHandler) BatchApprove(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "id")
+	req, ok := h.decodeBatch(w, r, sessionID)
+	if !ok {
+		return
+	}
+
+	target, ok := h.playbackTarget(w, r, sessionID)
+	if !ok {
+		return
+	}
+
+	h.runBatch(w, r, sessionID, req, auditRequestApprove, func(ctx context.Context, q db.Querier, songRequest db.SongRequest) error {
+		approved, err := q.ApproveSongRequest(ctx, db.ApproveSongRequestParams{ProcessedBy: processedByAdmin, ID: songRequest.ID})
+		if err != nil {
+			return err
+		}
+		if approved == 0 {
+			return errNotPending
+		}
+		if target != nil {
+			return services.QueueEffect(ctx, q, songRequest, services.EffectEnqueue)
+		}
+		return nil
+	})
+}
+
+// BatchReject rejects several pending requests in one transaction, with an
+// optional shared reason (admin only).
+func (h *RequestHandler) BatchReject(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "id")
+	req, ok := h.decodeBatch(w, r, sessionID)
+	if !ok {
+		return
+	}
+
+	var reason sql.NullString
+	if req.Reason != "" {
+		reason = sql.NullString{String: req.Reason, Valid: true}
+	}
+
+	h.runBatch(w, r, sessionID, req, auditRequestReject, func(ctx context.Context, q db.Querier, songRequest db.SongRequest) error {
+		rejected, err := q.RejectSongRequest(ctx, db.RejectSongRequestParams{RejectionReason: reason, ProcessedBy: processedByAdmin, ID: songRequest.ID})
+		if err != nil {
+			return err
+		}
+		if rejected == 0 {
+			return errNotPending
+		}
+		return nil
+	})
+}
+
+// decodeBatch authorizes and validates a batch request. On failure an error
+// response is written and ok is false.
+func (h *RequestHandler) decodeBatch(w http.ResponseWriter, r *http.Request, sessionID string) (req models.BatchModerationRequest, ok bool) {
+	claims := middleware.GetClaims(r.Context())
+	if err := requireAdmin(claims, sessionID); err != nil {
+		writeError(w, http.StatusForbidden, "admin access required")
+		return req, false
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return req, false
+	}
+	// An empty filter would match every pending request
+	if len(req.IDs) == 0 && (req.Filter == nil || *req.Filter == (models.BatchFilter{})) {
+		writeError(w, http.StatusBadRequest, "ids or at least one filter criterion is required")
+		return req, false
+	}
+	if req.Filter != nil && req.Filter.OlderThanMinutes != nil && *req.Filter.OlderThanMinutes < 0 {
+		writeError(w, http.StatusBadRequest, "olderThanMinutes must not be negative")
+		return req, false
+	}
+	return req, true
+}
+
+// runBatch selects the requests for a batch and moderates each in order in a
+// single transaction, then writes per-item results. An update returning
+// errNotPending skips that request; any other failure is a database error
+// and rolls back the whole batch. Each update is recorded in
+// the audit log under action in the same transaction.
+func (h *RequestHandler) runBatch(w http.ResponseWriter, r *http.Request, sessionID string, req models.BatchModerationRequest, action string,
+	update func(ctx context.Context, q db.Querier, songRequest db.SongRequest) error,
+) {
+	ctx := r.Context()
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		writeErrorWithCause(ctx, w, http.StatusInternalServerError, "failed to start transaction", err)
+		return
+	}
+	defer tx.Rollback()
+	qtx := h.queries.InTx(tx)
+
+	pending, err := qtx.GetPendingSongRequests(ctx, sessionID)
+	if err != nil {
+		writeErrorWithCause(ctx, w, http.StatusInternalServerError, "failed to fetch requests", err)
+		return
+	}
+
+	selected, skipped := selectBatch(pending, req, time.Now())
+	response := models.BatchModerationResponse{Results: append([]models.BatchItemResult{}, skipped...), Failed: len(skipped)}
+
+	for _, songRequest := range selected {
+		if err := update(ctx, qtx, songRequest); errors.Is(err, errNotPending) {
+			response.Results = append(response.Results, models.BatchItemResult{ID: songRequest.ID, Error: err.Error()})
+			response.Failed++
+			continue
+		} else if err != nil {
+			writeErrorWithCause(ctx, w, http.StatusInternalServerError, "failed to update requests", err)
+			return
+		}
+		updated, err := qtx.GetSongRequestByID(ctx, songRequest.ID)
+		if err != nil {
+			writeErrorWithCause(ctx, w, http.StatusInternalServerError, "failed to fetch updated request", err)
+			return
+		}
+		resp := songRequestToResponse(updated)
+		if err := recordAudit(ctx, qtx, middleware.GetClaims(ctx), auditEvent{
+			SessionID:  sessionID,
+			Action:     action,
+			TargetType: auditTargetRequest,
+			TargetID:   strconv.FormatInt(songRequest.ID, 10),
+			Before:     songRequestToResponse(songRequest),
+			After:      resp,
+		}); err != nil {
+			writeErrorWithCause(ctx, w, http.StatusInternalServerError, "failed to record audit event", err)
+			return
+		}
+		response.Results = append(response.Results, models.BatchItemResult{ID: songRequest.ID, OK: true, Request: &resp})
+		response.Succeeded++
+	}
+
+	if err := tx.Commit(); err != nil {
+		writeErrorWithCause(ctx, w, http.StatusInternalServerError, "failed to commit batch", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, response)
+	if response.Succeeded > 0 {
+		h.broker.Publish(sessionID)
+	}
+}
+
+// selectBatch picks the pending requests a batch applies to. With IDs, they
+// are returned in the given order and IDs that are not pending or do not
+// match the filter are reported as skipped; otherwise every pending request
+// matching the filter is returned, oldest first.
+func selectBatch(pending []db.SongRequest, req models.BatchModerationRequest, now time.Time) (selected []db.SongRequest, skipped []models.BatchItemResult) {
+	matches := func(sr db.SongRequest) bool {
+		f := req.Filter
+		if f == nil {
+			return true
+		}
+		if f.RequesterName != nil && (!sr.RequesterName.Valid || sr.RequesterName.String != *f.RequesterName) {
+			return false
+		}
+		if f.OlderThanMinutes != nil {
+			cutoff := now.Add(-time.Duration(*f.OlderThanMinutes) * time.Minute)
+			if !sr.RequestedAt.Valid || sr.RequestedAt.Time.After(cutoff) {
+				return false
+			}
+		}
+		return true
+	}
+
+	if len(req.IDs) == 0 {
+		for _, sr := range pending {
+			if matches(sr) {
+				selected = append(selected, sr)
+			}
+		}
+		return selected, nil
+	}
+
+	seen := make(map[int64]bool, len(req.IDs))
+	for _, id := range req.IDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		i := slices.IndexFunc(pending, func(sr db.SongRequest) bool { return sr.ID == id })
+		switch {
+		case i < 0:
+			skipped = append(skipped, models.BatchItemResult{ID: id, Error: errNotPending.Error()})
+		case !matches(pending[i]):
+			skipped = append(skipped, models.BatchItemResult{ID: id, Error: errFilterMismatch.Error()})
+		default:
+			selected = append(selected, pending[i])
+		}
+	}
+	return selected, skipped
+}
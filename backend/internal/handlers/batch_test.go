@@ -0,0 +1,306 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/songify/backend/internal/broker"
+	"github.com/songify/backend/internal/database"
+	"github.com/songify/backend/internal/database/dbtest"
+	"github.com/songify/backend/internal/db"
+	"github.com/songify/backend/internal/middleware"
+	"github.com/songify/backend/internal/models"
+	"github.com/songify/backend/internal/services"
+)
+
+// fakeTV is a provider whose playback target fails for one track.
+type fakeTV struct {
+	failTrack string
+	offline   bool
+	queued    []string
+	playlist  []services.Track
+}
+
+func (f *fakeTV) Name() string { return "youtube" }
+func (f *fakeTV) Search(context.Context, services.SearchOptions) (*services.SearchPage, error) {
+	return &services.SearchPage{}, nil
+}
+func (f *fakeTV) GetTrack(context.Context, string) (*services.Track, error) {
+	return nil, services.ErrTrackNotFound
+}
+func (f *fakeTV) ResolveURL(string) (string, error)       { return "", services.ErrUnrecognizedLink }
+func (f *fakeTV) PlaybackTarget() services.PlaybackTarget { return f }
+func (f *fakeTV) IsConnected(string) bool                 { return !f.offline }
+func (f *fakeTV) PlayNow(context.Context, string, string) error {
+	return nil
+}
+func (f *fakeTV) Remove(_ context.Context, _, trackID string) error {
+	for i, queued := range f.queued {
+		if queued == trackID {
+			f.queued = append(f.queued[:i], f.queued[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+func (f *fakeTV) PlaylistTracks(context.Context, string) ([]services.Track, error) {
+	if f.playlist == nil {
+		return nil, services.ErrTrackNotFound
+	}
+	return f.playlist, nil
+}
+func (f *fakeTV) Enqueue(_ context.Context, _, trackID string) error {
+	if f.offline {
+		return errors.New("not connected")
+	}
+	if trackID == f.failTrack {
+		return errors.New("screen unreachable")
+	}
+	f.queued = append(f.queued, trackID)
+	return nil
+}
+
+// deliverEffects runs one pass of the playback outbox, as the background
+// dispatcher would.
+func deliverEffects(queries db.Querier, providers *services.ProviderRegistry) {
+	services.NewOutbox(queries, providers, time.Second, nil).Tick(context.Background())
+}
+
+func TestSelectBatch(t *testing.T) {
+	now := time.Date(2024, 6, 1, 22, 0, 0, 0, time.UTC)
+	ago := func(d time.Duration) sql.NullTime { return sql.NullTime{Time: now.Add(-d), Valid: true} }
+	sam := sql.NullString{String: "Sam", Valid: true}
+	pending := []db.SongRequest{
+		{ID: 1, RequesterName: sam, RequestedAt: ago(90 * time.Minute)},
+		{ID: 2, RequestedAt: ago(60 * time.Minute)},
+		{ID: 3, RequesterName: sam, RequestedAt: ago(5 * time.Minute)},
+	}
+	name := "Sam"
+	thirty := 30
+
+	ids := func(srs []db.SongRequest) []int64 {
+		var out []int64
+		for _, sr := range srs {
+			out = append(out, sr.ID)
+		}
+		return out
+	}
+
+	selected, _ := selectBatch(pending, models.BatchModerationRequest{Filter: &models.BatchFilter{RequesterName: &name}}, now)
+	if got := ids(selected); len(got) != 2 || got[0] != 1 || got[1] != 3 {
+		t.Errorf("requester filter selected %v, want [1 3]", got)
+	}
+
+	selected, _ = selectBatch(pending, models.BatchModerationRequest{Filter: &models.BatchFilter{OlderThanMinutes: &thirty}}, now)
+	if got := ids(selected); len(got) != 2 || got[0] != 1 || got[1] != 2 {
+		t.Errorf("age filter selected %v, want [1 2]", got)
+	}
+
+	selected, skipped := selectBatch(pending, models.BatchModerationRequest{
+		IDs:    []int64{3, 9, 2, 3, 1},
+		Filter: &models.BatchFilter{RequesterName: &name},
+	}, now)
+	if got := ids(selected); len(got) != 2 || got[0] != 3 || got[1] != 1 {
+		t.Errorf("IDs selected %v, want [3 1] in given order", got)
+	}
+	if len(skipped) != 2 || skipped[0].ID != 9 || skipped[1].ID != 2 {
+		t.Errorf("skipped = %+v, want 9 (not pending) and 2 (filter mismatch)", skipped)
+	}
+}
+
+func TestBatchRequiresSelection(t *testing.T) {
+	h := &RequestHandler{}
+	for _, body := range []string{`{}`, `{"ids":[]}`, `{"filter":{}}`, `{"ids":[],"filter":{},"reason":"too many"}`} {
+		req := httptest.NewRequest(http.MethodPost, "/api/sessions/s1/requests/batch/reject", strings.NewReader(body))
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", "s1")
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ClaimsKey, &services.Claims{SessionID: "s1", Role: services.RoleAdmin}))
+		rec := httptest.NewRecorder()
+
+		h.BatchReject(rec, req)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("body %s: status = %d, want 400", body, rec.Code)
+		}
+	}
+}
+
+func TestBatchApprove(t *testing.T) {
+	ctx := context.Background()
+	sqlDB := dbtest.New(t)
+	queries := database.NewQueries(sqlDB)
+
+	dbtest.Session(t, queries, "s1")
+	dbtest.SongRequests(t, queries, "s1", "a", "b", "c")
+
+	tv := &fakeTV{failTrack: "b"}
+	providers := services.NewProviderRegistry(tv)
+	h := NewRequestHandler(sqlDB, queries, broker.New(), providers, time.Minute)
+
+	body, _ := json.Marshal(models.BatchModerationRequest{IDs: []int64{3, 2, 1}})
+	req := httptest.NewRequest(http.MethodPost, "/api/sessions/s1/requests/batch/approve", bytes.NewReader(body))
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "s1")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	req = req.WithContext(context.WithValue(req.Context(), middleware.ClaimsKey, &services.Claims{SessionID: "s1", Role: services.RoleAdmin}))
+	rec := httptest.NewRecorder()
+
+	h.BatchApprove(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp models.BatchModerationResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	// A TV failure does not hold up approval
+	if resp.Succeeded != 3 || resp.Failed != 0 {
+		t.Errorf("succeeded/failed = %d/%d, want 3/0", resp.Succeeded, resp.Failed)
+	}
+	if len(tv.queued) != 0 {
+		t.Errorf("queued = %v before delivery, want nothing", tv.queued)
+	}
+
+	// Songs reach the TV in request order; one that fails holds back the rest
+	deliverEffects(queries, providers)
+	if len(tv.queued) != 1 || tv.queued[0] != "c" {
+		t.Errorf("queued = %v, want [c]", tv.queued)
+	}
+	for id, want := range map[int64]string{3: services.DeliveryDelivered, 2: services.DeliveryPending, 1: services.DeliveryPending} {
+		sr, err := queries.GetSongRequestByID(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sr.Status != "approved" || sr.DeliveryStatus.String != want {
+			t.Errorf("request %d is %s with delivery %q, want approved with %q", id, sr.Status, sr.DeliveryStatus.String, want)
+		}
+	}
+	failing, err := queries.GetSongRequestByID(ctx, 2)
+	if err != nil || failing.DeliveryError.String != "screen unreachable" {
+		t.Errorf("request 2 delivery error = %q, want the TV's error", failing.DeliveryError.String)
+	}
+}
+
+func TestBatchApproveQueuesForDisconnectedTV(t *testing.T) {
+	ctx := context.Background()
+	sqlDB := dbtest.New(t)
+	queries := database.NewQueries(sqlDB)
+
+	dbtest.Session(t, queries, "s1")
+	sr := dbtest.SongRequests(t, queries, "s1", "a")[0]
+
+	tv := &fakeTV{offline: true}
+	providers := services.NewProviderRegistry(tv)
+	h := NewRequestHandler(sqlDB, queries, broker.New(), providers, time.Minute)
+
+	body, _ := json.Marshal(models.BatchModerationRequest{IDs: []int64{sr.ID}})
+	req := httptest.NewRequest(http.MethodPost, "/api/sessions/s1/requests/batch/approve", bytes.NewReader(body))
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "s1")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	req = req.WithContext(context.WithValue(req.Context(), middleware.ClaimsKey, &services.Claims{SessionID: "s1", Role: services.RoleAdmin}))
+	rec := httptest.NewRecorder()
+	h.BatchApprove(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body.String())
+	}
+
+	// The song waits in the outbox for the TV to come back
+	deliverEffects(queries, providers)
+	entries, err := queries.GetPendingOutboxEntries(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].TrackID != "a" || entries[0].Attempts != 0 {
+		t.Errorf("outbox = %+v, want the song pending without using up attempts", entries)
+	}
+	got, err := queries.GetSongRequestByID(ctx, sr.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.DeliveryStatus.String != services.DeliveryPending {
+		t.Errorf("delivery = %q, want pending until the TV connects", got.DeliveryStatus.String)
+	}
+
+	tv.offline = false
+	deliverEffects(queries, providers)
+	if len(tv.queued) != 1 || tv.queued[0] != "a" {
+		t.Errorf("queued = %v after the TV connected, want [a]", tv.queued)
+	}
+}
+
+// racingStore approves a request right after a batch has loaded the pending
+// ones, as another admin might in the meantime.
+type racingStore struct {
+	db.Store
+	approve int64
+}
+
+func (s racingStore) InTx(tx *sql.Tx) db.Store {
+	return racingStore{Store: s.Store.InTx(tx), approve: s.approve}
+}
+
+func (s racingStore) GetPendingSongRequests(ctx context.Context, sessionID string) ([]db.SongRequest, error) {
+	pending, err := s.Store.GetPendingSongRequests(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	_, err = s.Store.ApproveSongRequest(ctx, db.ApproveSongRequestParams{ProcessedBy: processedByAdmin, ID: s.approve})
+	return pending, err
+}
+
+func TestBatchRejectSkipsProcessedRequests(t *testing.T) {
+	ctx := context.Background()
+	sqlDB := dbtest.New(t)
+	queries := database.NewQueries(sqlDB)
+
+	dbtest.Session(t, queries, "s1")
+	requests := dbtest.SongRequests(t, queries, "s1", "a", "b")
+	approved := requests[0].ID
+
+	h := NewRequestHandler(sqlDB, racingStore{Store: queries, approve: approved}, broker.New(), services.NewProviderRegistry(&fakeTV{}), time.Minute)
+
+	body, _ := json.Marshal(models.BatchModerationRequest{IDs: []int64{requests[0].ID, requests[1].ID}})
+	req := httptest.NewRequest(http.MethodPost, "/api/sessions/s1/requests/batch/reject", bytes.NewReader(body))
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "s1")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	req = req.WithContext(context.WithValue(req.Context(), middleware.ClaimsKey, &services.Claims{SessionID: "s1", Role: services.RoleAdmin}))
+	rec := httptest.NewRecorder()
+
+	h.BatchReject(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp models.BatchModerationResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Succeeded != 1 || resp.Failed != 1 {
+		t.Errorf("succeeded/failed = %d/%d, want 1/1", resp.Succeeded, resp.Failed)
+	}
+	for _, result := range resp.Results {
+		if result.ID == approved && (result.OK || result.Error != errNotPending.Error()) {
+			t.Errorf("approved request result = %+v, want a not-pending conflict", result)
+		}
+	}
+	sr, err := queries.GetSongRequestByID(ctx, approved)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sr.Status != "approved" {
+		t.Errorf("status = %s, want the approval left alone", sr.Status)
+	}
+}
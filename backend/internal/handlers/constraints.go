@@ -0,0 +1,336 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/songify/backend/internal/db"
+	"github.com/songify/backend/internal/middleware"
+	"github.com/songify/backend/internal/models"
+	"github.com/songify/backend/internal/services"
+)
+
+// Bounds accepted for track constraint years.
+const (
+	minConstraintYear = 1900
+	maxConstraintYear = 2100
+)
+
+var (
+	// errNoMetadataSource means no registered provider can describe tracks.
+	errNoMetadataSource = errors.New("no music service provides track metadata")
+	// errNoTrackMetadata means a track's genre and release details could not
+	// be found.
+	errNoTrackMetadata = errors.New("no metadata for track")
+)
+
+// sessionTrackConstraints returns the session's track constraints, or nil if
+// none are set. Songs are only looked up on the metadata service when it
+// returns constraints.
+func sessionTrackConstraints(session db.Session) (*models.TrackConstraints, error) {
+	if !session.TrackConstraints.Valid {
+		return nil, nil
+	}
+	var c models.TrackConstraints
+	if err := json.Unmarshal([]byte(session.TrackConstraints.String), &c); err != nil {
+		return nil, fmt.Errorf("failed to decode track constraints: %w", err)
+	}
+	if !hasTrackConstraints(c) {
+		return nil, nil
+	}
+	return &c, nil
+}
+
+// hasTrackConstraints reports whether any constraint is set in c.
+func hasTrackConstraints(c models.TrackConstraints) bool {
+	return len(cleanGenres(c.Genres)) > 0 || len(cleanGenres(c.ExcludeGenres)) > 0 ||
+		c.MinReleaseYear != nil || c.MaxReleaseYear != nil || c.MinPopularity != nil || c.MaxPopularity != nil
+}
+
+// validateTrackConstraints checks and normalizes constraints. It returns the
+// JSON to store, which is NULL when no constraint is set.
+func validateTrackConstraints(c *models.TrackConstraints) (sql.NullString, error) {
+	c.Genres = cleanGenres(c.Genres)
+	c.ExcludeGenres = cleanGenres(c.ExcludeGenres)
+
+	for _, v := range []*int{c.MinReleaseYear, c.MaxReleaseYear} {
+		if v != nil && (*v < minConstraintYear || *v > maxConstraintYear) {
+			return sql.NullString{}, fmt.Errorf("release years must be between %d and %d", minConstraintYear, maxConstraintYear)
+		}
+	}
+	if c.MinReleaseYear != nil && c.MaxReleaseYear != nil && *c.MinReleaseYear > *c.MaxReleaseYear {
+		return sql.NullString{}, errors.New("minReleaseYear must not exceed maxReleaseYear")
+	}
+	for _, v := range []*int{c.MinPopularity, c.MaxPopularity} {
+		if v != nil && (*v < 0 || *v > 100) {
+			return sql.NullString{}, errors.New("popularity must be between 0 and 100")
+		}
+	}
+	if c.MinPopularity != nil && c.MaxPopularity != nil && *c.MinPopularity > *c.MaxPopularity {
+		return sql.NullString{}, errors.New("minPopularity must not exceed maxPopularity")
+	}
+
+	if !hasTrackConstraints(*c) {
+		return sql.NullString{}, nil
+	}
+	b, err := json.Marshal(c)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	return sql.NullString{String: string(b), Valid: true}, nil
+}
+
+// cleanGenres trims genre names and drops empty ones.
+func cleanGenres(genres []string) []string {
+	var cleaned []string
+	for _, g := range genres {
+		if g = strings.TrimSpace(g); g != "" {
+			cleaned = append(cleaned, g)
+		}
+	}
+	return cleaned
+}
+
+// checkTrackConstraints returns the first constraint the song breaks, or nil
+// if it is allowed.
+func checkTrackConstraints(c models.TrackConstraints, meta services.TrackMetadata) *models.RuleViolation {
+	for _, excluded := range c.ExcludeGenres {
+		for _, genre := range meta.Genres {
+			if genreMatches(genre, excluded) {
+				return &models.RuleViolation{
+					Rule:    ruleExcludedGenre,
+					Pattern: excluded,
+					Message: fmt.Sprintf("%s songs are not allowed in this session", genre),
+				}
+			}
+		}
+	}
+
+	if len(c.Genres) > 0 && !anyGenreMatches(meta.Genres, c.Genres) {
+		return &models.RuleViolation{
+			Rule:    ruleGenre,
+			Message: fmt.Sprintf("Only %s songs are allowed in this session", strings.Join(c.Genres, ", ")),
+		}
+	}
+
+	if c.MinReleaseYear != nil || c.MaxReleaseYear != nil {
+		if meta.ReleaseYear == 0 {
+			return &models.RuleViolation{
+				Rule:    ruleReleaseYear,
+				Message: "Couldn't find when this song was released, which this session requires",
+			}
+		}
+		if (c.MinReleaseYear != nil && meta.ReleaseYear < *c.MinReleaseYear) ||
+			(c.MaxReleaseYear != nil && meta.ReleaseYear > *c.MaxReleaseYear) {
+			return &models.RuleViolation{
+				Rule:    ruleReleaseYear,
+				Message: fmt.Sprintf("Song was released in %d; only songs released %s are allowed", meta.ReleaseYear, yearRange(c)),
+			}
+		}
+	}
+
+	if c.MinPopularity != nil && meta.Popularity < *c.MinPopularity {
+		return &models.RuleViolation{
+			Rule:    rulePopularity,
+			Message: "Song is not popular enough for this session",
+		}
+	}
+	if c.MaxPopularity != nil && meta.Popularity > *c.MaxPopularity {
+		return &models.RuleViolation{
+			Rule:    rulePopularity,
+			Message: "Song is too popular for this session; try something less well known",
+		}
+	}
+
+	return nil
+}
+
+// anyGenreMatches reports whether any genre matches one of the wanted names.
+func anyGenreMatches(genres, wanted []string) bool {
+	for _, genre := range genres {
+		for _, w := range wanted {
+			if genreMatches(genre, w) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// genreMatches reports whether genre is name or contains it as whole words,
+// ignoring case and treating hyphens as spaces: "pop" matches "dance pop" and
+// "synth-pop" but not "popcorn", and "rap" does not match "trap".
+func genreMatches(genre, name string) bool {
+	g, n := genreWords(genre), genreWords(name)
+	if len(n) == 0 {
+		return false
+	}
+	for i := 0; i+len(n) <= len(g); i++ {
+		if slices.Equal(g[i:i+len(n)], n) {
+			return true
+		}
+	}
+	return false
+}
+
+// genreWords splits a genre name into lower-case words.
+func genreWords(genre string) []string {
+	return strings.FieldsFunc(strings.ToLower(genre), func(r rune) bool {
+		return r == '-' || r == '_' || unicode.IsSpace(r)
+	})
+}
+
+// yearRange describes the allowed release years, e.g. "1980-1989" or "in
+// 2000 or later".
+func yearRange(c models.TrackConstraints) string {
+	switch {
+	case c.MinReleaseYear != nil && c.MaxReleaseYear != nil:
+		return fmt.Sprintf("%d-%d", *c.MinReleaseYear, *c.MaxReleaseYear)
+	case c.MinReleaseYear != nil:
+		return fmt.Sprintf("in %d or later", *c.MinReleaseYear)
+	default:
+		return fmt.Sprintf("in %d or earlier", *c.MaxReleaseYear)
+	}
+}
+
+// trackMetadata describes a track on the session's service. It is looked up
+// directly when the session's or the submitting service provides metadata,
+// and otherwise through the track's best match on a service that does.
+func trackMetadata(ctx context.Context, providers *services.ProviderRegistry, session db.Session, track services.Track, sourceService, sourceTrackID string) (*services.TrackMetadata, error) {
+	if p, err := providers.Get(session.MusicService); err == nil {
+		if source, ok := p.(services.MetadataSource); ok {
+			return source.TrackMetadata(ctx, track.ID)
+		}
+	}
+	if sourceService != "" {
+		if p, err := providers.Get(sourceService); err == nil {
+			if source, ok := p.(services.MetadataSource); ok {
+				return source.TrackMetadata(ctx, sourceTrackID)
+			}
+		}
+	}
+
+	for _, p := range providers.Providers() {
+		source, ok := p.(services.MetadataSource)
+		if !ok {
+			continue
+		}
+		match, err := services.MatchTrack(ctx, track, p)
+		if err != nil {
+			return nil, err
+		}
+		return source.TrackMetadata(ctx, match.Track.ID)
+	}
+	return nil, errNoMetadataSource
+}
+
+// trackConstraintViolation returns the session's track constraint the song
+// breaks, or nil if it is allowed or the session has none. It returns
+// errNoTrackMetadata when the song's details cannot be found.
+func trackConstraintViolation(ctx context.Context, providers *services.ProviderRegistry, session db.Session, track services.Track, sourceService, sourceTrackID string) (*models.RuleViolation, error) {
+	constraints, err := sessionTrackConstraints(session)
+	if err != nil || constraints == nil {
+		return nil, err
+	}
+
+	meta, err := trackMetadata(ctx, providers, session, track, sourceService, sourceTrackID)
+	if errors.Is(err, services.ErrNoMatch) || errors.Is(err, services.ErrTrackNotFound) || errors.Is(err, errNoMetadataSource) {
+		return nil, fmt.Errorf("%w: %w", errNoTrackMetadata, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return checkTrackConstraints(*constraints, *meta), nil
+}
+
+// enforceTrackConstraints rejects a song that breaks the session's track
+// constraints. sourceService and sourceTrackID identify the song as submitted
+// when it was converted from another service. On failure an error response
+// is written and ok is false.
+func enforceTrackConstraints(w http.ResponseWriter, r *http.Request, providers *services.ProviderRegistry, session db.Session, track services.Track, sourceService, sourceTrackID string) (ok bool) {
+	violation, err := trackConstraintViolation(r.Context(), providers, session, track, sourceService, sourceTrackID)
+	if errors.Is(err, errNoTrackMetadata) {
+		writeError(w, http.StatusUnprocessableEntity, "Couldn't find genre and release details for this song, which this session requires")
+		return false
+	}
+	if errors.Is(err, services.ErrQuotaExhausted) {
+		writeError(w, http.StatusServiceUnavailable, "song details are unavailable for the rest of the day; try again tomorrow")
+		return false
+	}
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusBadGateway, "failed to fetch song details", err)
+		return false
+	}
+
+	if violation != nil {
+		writeError(w, http.StatusBadRequest, violation.Message)
+		return false
+	}
+	return true
+}
+
+// UpdateTrackConstraints sets the session's genre, release year and
+// popularity constraints (admin only). An empty body clears them.
+func (h *SessionHandler) UpdateTrackConstraints(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "id")
+	claims := middleware.GetClaims(r.Context())
+
+	if err := requireAdmin(claims, sessionID); err != nil {
+		writeError(w, http.StatusForbidden, "admin access required")
+		return
+	}
+
+	var req models.TrackConstraints
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	stored, err := validateTrackConstraints(&req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	session, err := h.queries.GetSessionByID(r.Context(), sessionID)
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusNotFound, "session not found", err)
+		return
+	}
+	before, err := sessionTrackConstraints(session)
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to load track constraints", err)
+		return
+	}
+
+	err = h.queries.UpdateSessionTrackConstraints(r.Context(), db.UpdateSessionTrackConstraintsParams{
+		TrackConstraints: stored,
+		ID:               sessionID,
+	})
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to update track constraints", err)
+		return
+	}
+
+	var after *models.TrackConstraints
+	if stored.Valid {
+		after = &req
+	}
+	auditChange(r, h.queries, auditEvent{
+		SessionID:  sessionID,
+		Action:     auditConstraintsUpdate,
+		TargetType: auditTargetSession,
+		TargetID:   sessionID,
+		Before:     map[string]any{"trackConstraints": before},
+		After:      map[string]any{"trackConstraints": after},
+	})
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/songify/backend/internal/broker"
+	"github.com/songify/backend/internal/database"
+	"github.com/songify/backend/internal/database/dbtest"
+	"github.com/songify/backend/internal/middleware"
+	"github.com/songify/backend/internal/models"
+	"github.com/songify/backend/internal/services"
+)
+
+// fakeMetadataTV is a fakeTV that also describes its tracks.
+type fakeMetadataTV struct {
+	*fakeTV
+	metadata map[string]services.TrackMetadata
+	lookups  *int
+}
+
+func (f fakeMetadataTV) TrackMetadata(_ context.Context, trackID string) (*services.TrackMetadata, error) {
+	*f.lookups++
+	meta, ok := f.metadata[trackID]
+	if !ok {
+		return nil, services.ErrTrackNotFound
+	}
+	return &meta, nil
+}
+
+func TestCheckTrackConstraints(t *testing.T) {
+	intp := func(v int) *int { return &v }
+	meta := services.TrackMetadata{Genres: []string{"new wave", "synth-pop"}, ReleaseYear: 1984, Popularity: 72}
+
+	tests := []struct {
+		name        string
+		constraints models.TrackConstraints
+		want        string
+	}{
+		{"no constraints", models.TrackConstraints{}, ""},
+		{"genre matches", models.TrackConstraints{Genres: []string{"Synth"}}, ""},
+		{"genre missing", models.TrackConstraints{Genres: []string{"country"}}, ruleGenre},
+		{"genre excluded", models.TrackConstraints{ExcludeGenres: []string{"wave"}}, ruleExcludedGenre},
+		{"exclusion wins", models.TrackConstraints{Genres: []string{"pop"}, ExcludeGenres: []string{"new wave"}}, ruleExcludedGenre},
+		{"hyphen or space", models.TrackConstraints{Genres: []string{"Synth Pop"}}, ""},
+		{"part of a word", models.TrackConstraints{Genres: []string{"syn"}}, ruleGenre},
+		{"exclusion needs whole words", models.TrackConstraints{ExcludeGenres: []string{"ave"}}, ""},
+		{"in the 80s", models.TrackConstraints{MinReleaseYear: intp(1980), MaxReleaseYear: intp(1989)}, ""},
+		{"too old", models.TrackConstraints{MinReleaseYear: intp(1990)}, ruleReleaseYear},
+		{"too new", models.TrackConstraints{MaxReleaseYear: intp(1979)}, ruleReleaseYear},
+		{"popular enough", models.TrackConstraints{MinPopularity: intp(50)}, ""},
+		{"too popular", models.TrackConstraints{MaxPopularity: intp(40)}, rulePopularity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkTrackConstraints(tt.constraints, meta)
+			if (got == nil) != (tt.want == "") || (got != nil && got.Rule != tt.want) {
+				t.Errorf("violation = %+v, want rule %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := checkTrackConstraints(models.TrackConstraints{MinReleaseYear: intp(1980)}, services.TrackMetadata{}); got == nil || got.Rule != ruleReleaseYear {
+		t.Errorf("unknown release year violation = %+v, want %q", got, ruleReleaseYear)
+	}
+}
+
+func TestValidateTrackConstraints(t *testing.T) {
+	intp := func(v int) *int { return &v }
+
+	tests := []struct {
+		name        string
+		constraints models.TrackConstraints
+		wantErr     bool
+		wantStored  bool
+	}{
+		{"empty clears", models.TrackConstraints{}, false, false},
+		{"blank genres clear", models.TrackConstraints{Genres: []string{" ", ""}}, false, false},
+		{"decade", models.TrackConstraints{MinReleaseYear: intp(1980), MaxReleaseYear: intp(1989)}, false, true},
+		{"years reversed", models.TrackConstraints{MinReleaseYear: intp(1990), MaxReleaseYear: intp(1980)}, true, false},
+		{"year out of range", models.TrackConstraints{MinReleaseYear: intp(80)}, true, false},
+		{"popularity out of range", models.TrackConstraints{MaxPopularity: intp(101)}, true, false},
+		{"popularity reversed", models.TrackConstraints{MinPopularity: intp(60), MaxPopularity: intp(40)}, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stored, err := validateTrackConstraints(&tt.constraints)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, want error %v", err, tt.wantErr)
+			}
+			if stored.Valid != tt.wantStored {
+				t.Errorf("stored = %+v, want valid %v", stored, tt.wantStored)
+			}
+		})
+	}
+}
+
+func TestSubmitTrackConstraints(t *testing.T) {
+	ctx := context.Background()
+	sqlDB := dbtest.New(t)
+	queries := database.NewQueries(sqlDB)
+
+	dbtest.Session(t, queries, "s1")
+
+	lookups := 0
+	tv := fakeMetadataTV{fakeTV: &fakeTV{}, lookups: &lookups, metadata: map[string]services.TrackMetadata{
+		"take-on-me": {Genres: []string{"new wave"}, ReleaseYear: 1985, Popularity: 80},
+		"levitating": {Genres: []string{"dance pop"}, ReleaseYear: 2020, Popularity: 85},
+	}}
+	providers := services.NewProviderRegistry(tv)
+
+	call := func(handler http.HandlerFunc, role services.Role, body any) *httptest.ResponseRecorder {
+		t.Helper()
+		var buf bytes.Buffer
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			t.Fatal(err)
+		}
+		req := httptest.NewRequest(http.MethodPost, "/", &buf)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", "s1")
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ClaimsKey, &services.Claims{SessionID: "s1", Role: role, Identity: "Sam"}))
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec
+	}
+
+	sh := &SessionHandler{queries: queries, providers: providers}
+	from, to := 1980, 1989
+	eighties := models.TrackConstraints{MinReleaseYear: &from, MaxReleaseYear: &to}
+	if rec := call(sh.UpdateTrackConstraints, services.RoleFriend, eighties); rec.Code != http.StatusForbidden {
+		t.Errorf("friend update: status = %d, want 403", rec.Code)
+	}
+	if rec := call(sh.UpdateTrackConstraints, services.RoleAdmin, eighties); rec.Code != http.StatusOK {
+		t.Fatalf("update: status = %d: %s", rec.Code, rec.Body.String())
+	}
+
+	h := NewRequestHandler(sqlDB, queries, broker.New(), providers, time.Minute)
+	submit := func(trackID string) *httptest.ResponseRecorder {
+		return call(h.Submit, services.RoleFriend, models.SubmitSongRequestRequest{
+			ExternalTrackID: trackID, TrackName: trackID, ArtistNames: "x", DurationMS: 200000, ExternalURI: trackID,
+		})
+	}
+
+	if rec := submit("take-on-me"); rec.Code != http.StatusCreated {
+		t.Errorf("80s song: status = %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := submit("levitating"); rec.Code != http.StatusBadRequest {
+		t.Errorf("2020 song: status = %d, want 400: %s", rec.Code, rec.Body.String())
+	}
+	if rec := submit("unknown"); rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("song without metadata: status = %d, want 422: %s", rec.Code, rec.Body.String())
+	}
+
+	session, err := queries.GetSessionByID(ctx, "s1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	allowed := FallbackTrackFilter(queries, providers)
+	if ok, err := allowed(ctx, session, services.Track{ID: "levitating", Name: "Levitating", ArtistNames: "Dua Lipa"}); err != nil || ok {
+		t.Errorf("auto-DJ allowed 2020 song = %v, %v; want false", ok, err)
+	}
+
+	if rec := call(sh.UpdateTrackConstraints, services.RoleAdmin, models.TrackConstraints{}); rec.Code != http.StatusOK {
+		t.Fatalf("clear: status = %d: %s", rec.Code, rec.Body.String())
+	}
+	lookups = 0
+	if rec := submit("levitating"); rec.Code != http.StatusCreated {
+		t.Errorf("after clearing: status = %d: %s", rec.Code, rec.Body.String())
+	}
+	if lookups != 0 {
+		t.Errorf("metadata lookups without constraints = %d, want 0", lookups)
+	}
+}
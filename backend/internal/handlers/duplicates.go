@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/songify/backend/internal/db"
+	"github.com/songify/backend/internal/services"
+)
+
+// duplicateWindow returns how long an approved song blocks re-requests in the
+// session, or nil when it blocks them for the whole session.
+func duplicateWindow(session db.Session) *time.Duration {
+	if !session.DuplicateWindowMinutes.Valid {
+		return nil
+	}
+	window := time.Duration(session.DuplicateWindowMinutes.Int64) * time.Minute
+	return &window
+}
+
+// findDuplicate returns the request among the session's non-rejected
+// requests that is the same song as track, or nil. A request matches on
+// track ID or, across uploads and releases, on normalized title and artist.
+// Pending requests always count; approved ones only within window of being
+// approved, or for the whole session when window is nil.
+func findDuplicate(requests []db.SongRequest, track services.Track, window *time.Duration, now time.Time) *db.SongRequest {
+	for i, req := range requests {
+		if req.Status == "approved" && window != nil {
+			at := req.ProcessedAt
+			if !at.Valid {
+				at = req.RequestedAt
+			}
+			if at.Valid && now.Sub(at.Time) > *window {
+				continue
+			}
+		}
+
+		if req.ExternalTrackID == track.ID || services.SameSong(services.Track{
+			Name:        req.TrackName,
+			ArtistNames: req.ArtistNames,
+		}, track) {
+			return &requests[i]
+		}
+	}
+	return nil
+}
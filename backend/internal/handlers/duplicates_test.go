@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/songify/backend/internal/db"
+	"github.com/songify/backend/internal/services"
+)
+
+func TestFindDuplicate(t *testing.T) {
+	now := time.Date(2024, 6, 1, 23, 0, 0, 0, time.UTC)
+	at := func(ago time.Duration) sql.NullTime { return sql.NullTime{Time: now.Add(-ago), Valid: true} }
+	requests := []db.SongRequest{
+		{ID: 1, ExternalTrackID: "yt-1", TrackName: "Queen - Bohemian Rhapsody (Official Video)", ArtistNames: "Queen Official", Status: "pending", RequestedAt: at(10 * time.Minute)},
+		{ID: 2, ExternalTrackID: "yt-2", TrackName: "Mr. Brightside", ArtistNames: "The Killers", Status: "approved", RequestedAt: at(4 * time.Hour), ProcessedAt: at(3 * time.Hour)},
+	}
+	twoHours := 2 * time.Hour
+	fiveHours := 5 * time.Hour
+
+	tests := []struct {
+		name   string
+		track  services.Track
+		window *time.Duration
+		wantID int64
+	}{
+		{"same ID", services.Track{ID: "yt-1", Name: "anything"}, nil, 1},
+		{"other upload of pending song", services.Track{ID: "yt-9", Name: "Bohemian Rhapsody [Lyrics] - Queen", ArtistNames: "Lyric Vault"}, &twoHours, 1},
+		{"approved, no window", services.Track{ID: "yt-8", Name: "Mr Brightside - Remastered", ArtistNames: "The Killers"}, nil, 2},
+		{"approved, outside window", services.Track{ID: "yt-2", Name: "Mr. Brightside", ArtistNames: "The Killers"}, &twoHours, 0},
+		{"approved, inside window", services.Track{ID: "yt-2", Name: "Mr. Brightside", ArtistNames: "The Killers"}, &fiveHours, 2},
+		{"different song", services.Track{ID: "yt-7", Name: "Somebody Told Me", ArtistNames: "The Killers"}, nil, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := findDuplicate(requests, tt.track, tt.window, now)
+			var gotID int64
+			if got != nil {
+				gotID = got.ID
+			}
+			if gotID != tt.wantID {
+				t.Errorf("findDuplicate() = request %d, want %d", gotID, tt.wantID)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/songify/backend/internal/database"
+	"github.com/songify/backend/internal/database/dbtest"
+	"github.com/songify/backend/internal/db"
+	"github.com/songify/backend/internal/middleware"
+	"github.com/songify/backend/internal/models"
+	"github.com/songify/backend/internal/services"
+)
+
+func TestUpdateFallbackPlaylist(t *testing.T) {
+	ctx := context.Background()
+	sqlDB := dbtest.New(t)
+	queries := database.NewQueries(sqlDB)
+
+	session := dbtest.Session(t, queries, "s1")
+	if _, err := queries.CreateProhibitedPattern(ctx, db.CreateProhibitedPatternParams{
+		SessionID: "s1", PatternType: "artist", Pattern: "nickelback",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	tv := &fakeTV{}
+	h := &SessionHandler{queries: queries, providers: services.NewProviderRegistry(tv)}
+
+	update := func(req models.UpdateFallbackPlaylistRequest) *httptest.ResponseRecorder {
+		t.Helper()
+		body, _ := json.Marshal(req)
+		r := httptest.NewRequest(http.MethodPut, "/api/sessions/s1/settings/fallback-playlist", bytes.NewReader(body))
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", "s1")
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+		r = r.WithContext(context.WithValue(r.Context(), middleware.ClaimsKey, &services.Claims{SessionID: "s1", Role: services.RoleAdmin, Identity: "admin"}))
+		rec := httptest.NewRecorder()
+		h.UpdateFallbackPlaylist(rec, r)
+		return rec
+	}
+
+	playlistID := "PL1"
+	if rec := update(models.UpdateFallbackPlaylistRequest{PlaylistID: &playlistID}); rec.Code != http.StatusBadRequest {
+		t.Errorf("missing playlist: status = %d, want 400", rec.Code)
+	}
+	if rec := update(models.UpdateFallbackPlaylistRequest{MusicService: "spotify", PlaylistID: &playlistID}); rec.Code != http.StatusBadRequest {
+		t.Errorf("unknown service: status = %d, want 400", rec.Code)
+	}
+
+	tv.playlist = []services.Track{{ID: "a", Name: "Song"}}
+	if rec := update(models.UpdateFallbackPlaylistRequest{PlaylistID: &playlistID}); rec.Code != http.StatusOK {
+		t.Fatalf("set: status = %d: %s", rec.Code, rec.Body.String())
+	}
+	session, err := queries.GetSessionByID(ctx, "s1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := fallbackPlaylist(session); got == nil || got.MusicService != "youtube" || got.PlaylistID != "PL1" {
+		t.Errorf("fallback playlist = %+v, want youtube PL1", got)
+	}
+
+	allowed := FallbackTrackFilter(queries, h.providers)
+	if ok, err := allowed(ctx, session, services.Track{Name: "Photograph", ArtistNames: "Nickelback"}); err != nil || ok {
+		t.Errorf("prohibited artist allowed = %v, %v; want false", ok, err)
+	}
+	if ok, err := allowed(ctx, session, services.Track{Name: "Song", ArtistNames: "Someone"}); err != nil || !ok {
+		t.Errorf("other artist allowed = %v, %v; want true", ok, err)
+	}
+
+	if rec := update(models.UpdateFallbackPlaylistRequest{}); rec.Code != http.StatusOK {
+		t.Fatalf("clear: status = %d: %s", rec.Code, rec.Body.String())
+	}
+	session, err = queries.GetSessionByID(ctx, "s1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := fallbackPlaylist(session); got != nil {
+		t.Errorf("fallback playlist = %+v after clearing, want none", got)
+	}
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/songify/backend/internal/models"
+	"github.com/songify/backend/internal/services"
+)
+
+// matchTrack looks a track up on sourceService and finds its best match on
+// the session's provider. On failure an error response is written and ok is false.
+func matchTrack(w http.ResponseWriter, r *http.Request, providers *services.ProviderRegistry, sourceService, trackID string, target services.MusicProvider) (match *services.TrackMatch, ok bool) {
+	source, err := providers.Get(sourceService)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "unknown musicService "+sourceService)
+		return nil, false
+	}
+	if trackID == "" {
+		writeError(w, http.StatusBadRequest, "track ID is required")
+		return nil, false
+	}
+
+	track, err := source.GetTrack(r.Context(), trackID)
+	if errors.Is(err, services.ErrTrackNotFound) {
+		writeError(w, http.StatusNotFound, "song not found")
+		return nil, false
+	}
+	if err == nil {
+		match, err = services.MatchTrack(r.Context(), *track, target)
+	}
+	if errors.Is(err, services.ErrNoMatch) {
+		writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("Couldn't find this song on %s; try searching for it instead", serviceDisplayName(target.Name())))
+		return nil, false
+	}
+	if errors.Is(err, services.ErrQuotaExhausted) {
+		writeError(w, http.StatusServiceUnavailable, "song matching is unavailable for the rest of the day; search for the song instead")
+		return nil, false
+	}
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusBadGateway, "failed to match song", err)
+		return nil, false
+	}
+	return match, true
+}
+
+// trackMatchToResponse describes a match of the given source track.
+func trackMatchToResponse(match *services.TrackMatch, sourceService, sourceTrackID string) *models.TrackMatchResponse {
+	return &models.TrackMatchResponse{
+		MusicService:    sourceService,
+		ExternalTrackID: sourceTrackID,
+		Confidence:      match.Confidence,
+		Method:          match.Method,
+	}
+}
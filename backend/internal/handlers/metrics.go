@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/songify/backend/internal/models"
+	"github.com/songify/backend/internal/services"
+)
+
+// MetricsHandler exposes search cache and API quota metrics.
+type MetricsHandler struct {
+	searchCache  *services.SearchCache
+	youtubeQuota *services.QuotaMeter
+}
+
+// NewMetricsHandler creates a MetricsHandler with the given search cache and YouTube quota meter.
+func NewMetricsHandler(searchCache *services.SearchCache, youtubeQuota *services.QuotaMeter) *MetricsHandler {
+	return &MetricsHandler{searchCache: searchCache, youtubeQuota: youtubeQuota}
+}
+
+// Search returns the cache hit rate (overall and per provider) and today's YouTube quota usage.
+func (h *MetricsHandler) Search(w http.ResponseWriter, r *http.Request) {
+	stats, entries := h.searchCache.Stats()
+	usage := h.youtubeQuota.Usage()
+
+	var total services.SearchCacheStats
+	providers := make(map[string]models.SearchCacheMetrics, len(stats))
+	for name, s := range stats {
+		providers[name] = toSearchCacheMetrics(s)
+		total.Hits += s.Hits
+		total.Misses += s.Misses
+		total.StaleHits += s.StaleHits
+	}
+
+	cache := toSearchCacheMetrics(total)
+	cache.Entries = &entries
+
+	writeJSON(w, http.StatusOK, models.MetricsResponse{
+		Cache:     cache,
+		Providers: providers,
+		YouTubeQuota: models.QuotaMetrics{
+			Used:      usage.Used,
+			Budget:    usage.Budget,
+			SoftLimit: usage.SoftLimit,
+			ResetsAt:  usage.ResetsAt,
+		},
+	})
+}
+
+func toSearchCacheMetrics(s services.SearchCacheStats) models.SearchCacheMetrics {
+	return models.SearchCacheMetrics{
+		Hits:      s.Hits,
+		Misses:    s.Misses,
+		StaleHits: s.StaleHits,
+		HitRate:   s.HitRate(),
+	}
+}
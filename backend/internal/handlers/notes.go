@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxNoteLength is the longest note or dedication, in characters, a guest can attach to a request.
+const maxNoteLength = 140
+
+// sanitizeNote normalizes a guest's note: control and invisible formatting
+// characters (zero-width spaces, bidi overrides) are removed and runs of
+// whitespace collapse to single spaces. Returns an error for notes that are
+// not valid UTF-8 or are too long after cleaning.
+func sanitizeNote(note string) (string, error) {
+	if !utf8.ValidString(note) {
+		return "", errors.New("note must be valid UTF-8 text")
+	}
+
+	cleaned := strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsSpace(r):
+			return ' '
+		case unicode.IsControl(r), unicode.Is(unicode.Cf, r):
+			return -1
+		default:
+			return r
+		}
+	}, note)
+	cleaned = strings.Join(strings.Fields(cleaned), " ")
+
+	if utf8.RuneCountInString(cleaned) > maxNoteLength {
+		return "", fmt.Errorf("note must be at most %d characters", maxNoteLength)
+	}
+	return cleaned, nil
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/songify/backend/internal/db"
+)
+
+func TestSanitizeNote(t *testing.T) {
+	tests := []struct {
+		name    string
+		note    string
+		want    string
+		wantErr bool
+	}{
+		{"empty", "", "", false},
+		{"plain", "for Sam's birthday 🎂", "for Sam's birthday 🎂", false},
+		{"whitespace collapsed", "  happy\n\nbirthday\t Sam ", "happy birthday Sam", false},
+		{"invisible characters removed", "hi\u200b there\u202e\x07", "hi there", false},
+		{"max length", strings.Repeat("é", maxNoteLength), strings.Repeat("é", maxNoteLength), false},
+		{"too long", strings.Repeat("a", maxNoteLength+1), "", true},
+		{"invalid utf-8", "bad \xff byte", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sanitizeNote(tt.note)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("sanitizeNote(%q) = %q, want %q", tt.note, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSessionRulesCheckNote(t *testing.T) {
+	rules := sessionRules{patterns: []db.ProhibitedPattern{
+		{PatternType: "artist", Pattern: "nickelback"},
+		{PatternType: "title", Pattern: "darn"},
+	}}
+
+	if v := rules.checkNote("Darn good song"); v == nil || v.Rule != ruleProhibitedNote {
+		t.Errorf("checkNote(prohibited word) = %+v, want %s", v, ruleProhibitedNote)
+	}
+	if v := rules.checkNote("better than nickelback"); v == nil || v.Pattern != "nickelback" {
+		t.Errorf("checkNote(artist pattern) = %+v, want a violation for nickelback", v)
+	}
+	if v := rules.checkNote("play something fun"); v != nil {
+		t.Errorf("checkNote(clean) = %+v, want nil", v)
+	}
+}
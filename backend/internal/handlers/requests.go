@@ -3,10 +3,13 @@ package handlers
 import (
 	"database/sql"
 	"encoding/json"
+	"errors"
+	"io"
 	"log/slog"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/songify/backend/internal/broker"
@@ -18,17 +21,22 @@ import (
 
 // RequestHandler manages song request operations: listing, submitting, and moderation.
 type RequestHandler struct {
-	queries       *db.Queries
-	broker        *broker.Broker
-	loungeManager *services.LoungeManager
+	db         *sql.DB
+	queries    db.Store
+	broker     broker.Broker
+	providers  *services.ProviderRegistry
+	undoWindow time.Duration
 }
 
-// NewRequestHandler creates a RequestHandler with the given database queries, event broker, and lounge manager.
-func NewRequestHandler(queries *db.Queries, broker *broker.Broker, loungeManager *services.LoungeManager) *RequestHandler {
-	return &RequestHandler{queries: queries, broker: broker, loungeManager: loungeManager}
+// NewRequestHandler creates a RequestHandler with the given database, queries, event broker, and music providers.
+// undoWindow is how long after approval or rejection a request can still be reverted.
+func NewRequestHandler(sqlDB *sql.DB, queries db.Store, broker broker.Broker, providers *services.ProviderRegistry, undoWindow time.Duration) *RequestHandler {
+	return &RequestHandler{db: sqlDB, queries: queries, broker: broker, providers: providers, undoWindow: undoWindow}
 }
 
 // List returns all song requests for the session, ordered by request time.
+// With mine=true only the caller's own requests are returned. Notes an admin
+// has hidden are only included for admins.
 func (h *RequestHandler) List(w http.ResponseWriter, r *http.Request) {
 	sessionID := chi.URLParam(r, "id")
 	claims := middleware.GetClaims(r.Context())
@@ -38,22 +46,38 @@ func (h *RequestHandler) List(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	requests, err := h.queries.GetSongRequestsBySessionID(r.Context(), sessionID)
+	var requests []db.SongRequest
+	var err error
+	if r.URL.Query().Get("mine") == "true" {
+		requests, err = h.queries.GetSongRequestsByRequester(r.Context(), db.GetSongRequestsByRequesterParams{
+			SessionID:     sessionID,
+			RequesterName: sql.NullString{String: claims.Identity, Valid: claims.Identity != ""},
+		})
+	} else {
+		requests, err = h.queries.GetSongRequestsBySessionID(r.Context(), sessionID)
+	}
 	if err != nil {
 		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to fetch requests", err)
 		return
 	}
 
+	isAdmin := claims.Role == services.RoleAdmin
 	response := make([]models.SongRequestResponse, len(requests))
 	for i, req := range requests {
 		response[i] = songRequestToResponse(req)
+		if req.NoteHidden && !isAdmin {
+			response[i].Note = nil
+			response[i].NoteHidden = false
+		}
 	}
 
 	writeJSON(w, http.StatusOK, response)
 }
 
 // Submit adds a new song request after validating against session rules.
-// Checks for duplicates, duration limits, and prohibited patterns.
+// Songs from another music service are first converted to their best match on
+// the session's service. Checks for duplicates, duration limits, prohibited patterns
+// and track constraints, then applies the first matching auto-moderation rule.
 func (h *RequestHandler) Submit(w http.ResponseWriter, r *http.Request) {
 	sessionID := chi.URLParam(r, "id")
 	claims := middleware.GetClaims(r.Context())
@@ -69,6 +93,12 @@ func (h *RequestHandler) Submit(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	note, err := sanitizeNote(req.Note)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	// Get session to check limits and patterns
 	session, err := h.queries.GetSessionByID(r.Context(), sessionID)
 	if err != nil {
@@ -76,36 +106,96 @@ func (h *RequestHandler) Submit(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Check duration limit
-	if session.SongDurationLimitMs.Valid && req.DurationMS > session.SongDurationLimitMs.Int64 {
-		writeError(w, http.StatusBadRequest, "Song exceeds duration limit")
+	// Convert songs found on another service to the session's service
+	var match *models.TrackMatchResponse
+	sourceService, sourceTrackID := "", ""
+	if req.MusicService != "" && req.MusicService != session.MusicService {
+		sourceService, sourceTrackID = req.MusicService, req.ExternalTrackID
+		provider, err := h.providers.Get(session.MusicService)
+		if err != nil {
+			writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "session music service is not available", err)
+			return
+		}
+		converted, ok := matchTrack(w, r, h.providers, req.MusicService, req.ExternalTrackID, provider)
+		if !ok {
+			return
+		}
+		match = trackMatchToResponse(converted, req.MusicService, req.ExternalTrackID)
+		req = models.SubmitSongRequestRequest{
+			ExternalTrackID: converted.Track.ID,
+			TrackName:       converted.Track.Name,
+			ArtistNames:     converted.Track.ArtistNames,
+			AlbumName:       converted.Track.AlbumName,
+			AlbumArtURL:     converted.Track.AlbumArtURL,
+			DurationMS:      converted.Track.DurationMS,
+			ExternalURI:     converted.Track.URI,
+		}
+	}
+
+	// Check duration limit and prohibited patterns
+	rules, err := loadSessionRules(r.Context(), h.queries, session)
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to load session rules", err)
+		return
+	}
+	if violation := rules.check(req.TrackName, req.ArtistNames, req.DurationMS); violation != nil {
+		writeError(w, http.StatusBadRequest, violation.Message)
+		return
+	}
+	if violation := rules.checkNote(note); violation != nil {
+		writeError(w, http.StatusBadRequest, violation.Message)
 		return
 	}
 
-	// Check for duplicate
-	isDuplicate, err := h.queries.IsDuplicateRequest(r.Context(), db.IsDuplicateRequestParams{
-		SessionID:       sessionID,
-		ExternalTrackID: req.ExternalTrackID,
-	})
-	if err == nil && isDuplicate == 1 {
-		writeError(w, http.StatusConflict, "Song already requested")
-		return
-	}
-
-	// Check prohibited patterns
-	patterns, err := h.queries.GetProhibitedPatternsBySessionID(r.Context(), sessionID)
-	if err == nil {
-		for _, p := range patterns {
-			if p.PatternType == "artist" && containsIgnoreCase(req.ArtistNames, p.Pattern) {
-				writeError(w, http.StatusBadRequest, "Artist is prohibited")
-				return
-			}
-			if p.PatternType == "title" && containsIgnoreCase(req.TrackName, p.Pattern) {
-				writeError(w, http.StatusBadRequest, "Song title contains prohibited words")
-				return
-			}
+	// Check for the same song already pending, or approved within the session's duplicate window
+	active, err := h.queries.GetActiveSongRequests(r.Context(), sessionID)
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to check for duplicates", err)
+		return
+	}
+	track := services.Track{ID: req.ExternalTrackID, Name: req.TrackName, ArtistNames: req.ArtistNames}
+	if existing := findDuplicate(active, track, duplicateWindow(session), time.Now()); existing != nil {
+		writeJSON(w, http.StatusConflict, models.DuplicateRequestResponse{
+			Error:           "Song already requested",
+			ExistingRequest: songRequestToResponse(*existing),
+		})
+		return
+	}
+
+	// Check genre, release year and popularity against the service's metadata
+	track.DurationMS = req.DurationMS
+	if !enforceTrackConstraints(w, r, h.providers, session, track, sourceService, sourceTrackID) {
+		return
+	}
+
+	// Find the auto-moderation rule, if any, that decides this request. Rules
+	// on the vote score wait for votes to come in; see Vote.
+	autoRules, err := loadAutoModerationRules(r.Context(), h.queries, sessionID)
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to load auto-moderation rules", err)
+		return
+	}
+	submitRules := autoRules[:0]
+	for _, rule := range autoRules {
+		if !rule.usesVoteScore() {
+			submitRules = append(submitRules, rule)
 		}
 	}
+	var autoRule *autoModRule
+	if len(submitRules) > 0 {
+		queueLength, err := h.queries.CountPendingSongRequests(r.Context(), sessionID)
+		if err != nil {
+			writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to count pending requests", err)
+			return
+		}
+		autoRule = firstMatchingRule(submitRules, autoModInput{
+			RequesterName: claims.Identity,
+			ArtistNames:   req.ArtistNames,
+			DurationMS:    req.DurationMS,
+			QueueLength:   queueLength,
+			Now:           time.Now(),
+		})
+	}
 
 	var albumArtURL sql.NullString
 	if req.AlbumArtURL != "" {
@@ -127,17 +217,98 @@ func (h *RequestHandler) Submit(w http.ResponseWriter, r *http.Request) {
 		DurationMs:      req.DurationMS,
 		ExternalUri:     req.ExternalURI,
 		RequesterName:   requesterName,
+		Note:            sql.NullString{String: note, Valid: note != ""},
 	})
 	if err != nil {
 		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to create request", err)
 		return
 	}
+	auditChange(r, h.queries, auditEvent{
+		SessionID:  sessionID,
+		Action:     auditRequestSubmit,
+		TargetType: auditTargetRequest,
+		TargetID:   strconv.FormatInt(songRequest.ID, 10),
+		After:      songRequestToResponse(songRequest),
+	})
+	if autoRule != nil {
+		songRequest = h.applyAutoModeration(r.Context(), session, songRequest, autoRule)
+	}
+
+	response := songRequestToResponse(songRequest)
+	response.Match = match
+	writeJSON(w, http.StatusCreated, response)
+	h.broker.Publish(sessionID)
+}
+
+// Withdraw deletes a pending song request on behalf of the guest who made it.
+// Once a request has been approved or rejected it can no longer be withdrawn.
+func (h *RequestHandler) Withdraw(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "id")
+	requestID := chi.URLParam(r, "rid")
+	claims := middleware.GetClaims(r.Context())
+
+	if err := requireSession(claims, sessionID); err != nil {
+		writeError(w, http.StatusForbidden, "access denied")
+		return
+	}
+
+	rid, err := strconv.ParseInt(requestID, 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request ID")
+		return
+	}
+
+	songRequest, err := h.queries.GetSongRequestByID(r.Context(), rid)
+	if err != nil || songRequest.SessionID != sessionID {
+		writeErrorWithCause(r.Context(), w, http.StatusNotFound, "request not found", err)
+		return
+	}
+	if claims.Identity == "" || !songRequest.RequesterName.Valid || songRequest.RequesterName.String != claims.Identity {
+		writeError(w, http.StatusForbidden, "only the guest who made a request can withdraw it")
+		return
+	}
+	if songRequest.Status != "pending" {
+		writeError(w, http.StatusConflict, "only pending requests can be withdrawn")
+		return
+	}
+
+	// Conditional delete so a request approved in the meantime is left alone
+	result, err := h.queries.WithdrawSongRequest(r.Context(), db.WithdrawSongRequestParams{
+		ID:            rid,
+		SessionID:     sessionID,
+		RequesterName: songRequest.RequesterName,
+	})
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to withdraw request", err)
+		return
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to check withdrawal result", err)
+		return
+	}
+	if rowsAffected == 0 {
+		writeError(w, http.StatusConflict, "only pending requests can be withdrawn")
+		return
+	}
+
+	auditChange(r, h.queries, auditEvent{
+		SessionID:  sessionID,
+		Action:     auditRequestWithdraw,
+		TargetType: auditTargetRequest,
+		TargetID:   requestID,
+		Before:     songRequestToResponse(songRequest),
+	})
 
-	writeJSON(w, http.StatusCreated, songRequestToResponse(songRequest))
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
 	h.broker.Publish(sessionID)
 }
 
 // Approve marks a pending song request as approved (admin only).
+// The optional body can hide the request's note from guests at the same time.
+// If the session's service has a playback target (e.g. a Lounge TV) the song
+// is queued on it in the background, waiting for the target to connect if
+// need be; the request's delivery status tracks the outcome.
 func (h *RequestHandler) Approve(w http.ResponseWriter, r *http.Request) {
 	sessionID := chi.URLParam(r, "id")
 	requestID := chi.URLParam(r, "rid")
@@ -166,20 +337,54 @@ func (h *RequestHandler) Approve(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// If Lounge is connected, send addVideo before marking approved
-	loungeConnected := h.loungeManager.IsConnected(sessionID)
-	slog.Info("approve: lounge check", slog.String("session_id", sessionID), slog.Bool("lounge_connected", loungeConnected), slog.String("track_id", songRequest.ExternalTrackID))
-	if loungeConnected {
-		if err := h.loungeManager.SendAddVideo(sessionID, songRequest.ExternalTrackID); err != nil {
-			writeErrorWithCause(r.Context(), w, http.StatusBadGateway, "failed to send video to TV", err)
+	var req models.ApproveSongRequestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	target, ok := h.playbackTarget(w, r, sessionID)
+	if !ok {
+		return
+	}
+	slog.Info("approve: playback check", slog.String("session_id", sessionID), slog.Bool("playback_target", target != nil), slog.String("track_id", songRequest.ExternalTrackID))
+
+	tx, err := h.db.BeginTx(r.Context(), nil)
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to start transaction", err)
+		return
+	}
+	defer tx.Rollback()
+	qtx := h.queries.InTx(tx)
+
+	if req.HideNote && songRequest.Note.Valid {
+		if err := qtx.SetSongRequestNoteHidden(r.Context(), db.SetSongRequestNoteHiddenParams{NoteHidden: true, ID: rid}); err != nil {
+			writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to hide note", err)
 			return
 		}
 	}
 
-	if err := h.queries.ApproveSongRequest(r.Context(), rid); err != nil {
+	// Conditional on the request still being pending, so it is queued only once
+	approved, err := qtx.ApproveSongRequest(r.Context(), db.ApproveSongRequestParams{ProcessedBy: processedByAdmin, ID: rid})
+	if err != nil {
 		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to approve request", err)
 		return
 	}
+	if approved == 0 {
+		writeError(w, http.StatusConflict, "only pending requests can be approved")
+		return
+	}
+	if target != nil {
+		if err := services.QueueEffect(r.Context(), qtx, songRequest, services.EffectEnqueue); err != nil {
+			writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to queue song for TV", err)
+			return
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to commit approval", err)
+		return
+	}
 
 	// Fetch updated request
 	updatedRequest, err := h.queries.GetSongRequestByID(r.Context(), rid)
@@ -188,11 +393,21 @@ func (h *RequestHandler) Approve(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	auditChange(r, h.queries, auditEvent{
+		SessionID:  sessionID,
+		Action:     auditRequestApprove,
+		TargetType: auditTargetRequest,
+		TargetID:   requestID,
+		Before:     songRequestToResponse(songRequest),
+		After:      songRequestToResponse(updatedRequest),
+	})
+
 	writeJSON(w, http.StatusOK, songRequestToResponse(updatedRequest))
 	h.broker.Publish(sessionID)
 }
 
-// PlayNext approves a song request and plays it immediately on the TV (admin only).
+// PlayNext approves a pending song request and plays it immediately on the TV (admin only).
+// As with Approve, the TV is told in the background.
 func (h *RequestHandler) PlayNext(w http.ResponseWriter, r *http.Request) {
 	sessionID := chi.URLParam(r, "id")
 	requestID := chi.URLParam(r, "rid")
@@ -220,18 +435,40 @@ func (h *RequestHandler) PlayNext(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Send setVideo to play immediately on TV
-	loungeConnected := h.loungeManager.IsConnected(sessionID)
-	slog.Info("play-next: lounge check", slog.String("session_id", sessionID), slog.Bool("lounge_connected", loungeConnected), slog.String("track_id", songRequest.ExternalTrackID))
-	if loungeConnected {
-		if err := h.loungeManager.SendSetVideo(sessionID, songRequest.ExternalTrackID); err != nil {
-			writeErrorWithCause(r.Context(), w, http.StatusBadGateway, "failed to play video on TV", err)
+	// Play immediately on the playback target
+	target, ok := h.playbackTarget(w, r, sessionID)
+	if !ok {
+		return
+	}
+	slog.Info("play-next: playback check", slog.String("session_id", sessionID), slog.Bool("playback_target", target != nil), slog.String("track_id", songRequest.ExternalTrackID))
+
+	tx, err := h.db.BeginTx(r.Context(), nil)
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to start transaction", err)
+		return
+	}
+	defer tx.Rollback()
+	qtx := h.queries.InTx(tx)
+
+	// Conditional on the request still being pending, so it is queued only once
+	approved, err := qtx.ApproveSongRequest(r.Context(), db.ApproveSongRequestParams{ProcessedBy: processedByAdmin, ID: rid})
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to approve request", err)
+		return
+	}
+	if approved == 0 {
+		writeError(w, http.StatusConflict, "only pending requests can be approved")
+		return
+	}
+	if target != nil {
+		if err := services.QueueEffect(r.Context(), qtx, songRequest, services.EffectPlayNow); err != nil {
+			writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to queue song for TV", err)
 			return
 		}
 	}
 
-	if err := h.queries.ApproveSongRequest(r.Context(), rid); err != nil {
-		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to approve request", err)
+	if err := tx.Commit(); err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to commit approval", err)
 		return
 	}
 
@@ -241,10 +478,92 @@ func (h *RequestHandler) PlayNext(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	auditChange(r, h.queries, auditEvent{
+		SessionID:  sessionID,
+		Action:     auditRequestPlayNext,
+		TargetType: auditTargetRequest,
+		TargetID:   requestID,
+		Before:     songRequestToResponse(songRequest),
+		After:      songRequestToResponse(updatedRequest),
+	})
+
 	writeJSON(w, http.StatusOK, songRequestToResponse(updatedRequest))
 	h.broker.Publish(sessionID)
 }
 
+// UpdateNoteVisibility hides or shows a request's note to guests (admin only).
+func (h *RequestHandler) UpdateNoteVisibility(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "id")
+	requestID := chi.URLParam(r, "rid")
+	claims := middleware.GetClaims(r.Context())
+
+	if err := requireAdmin(claims, sessionID); err != nil {
+		writeError(w, http.StatusForbidden, "admin access required")
+		return
+	}
+
+	rid, err := strconv.ParseInt(requestID, 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request ID")
+		return
+	}
+
+	var req models.UpdateNoteVisibilityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	songRequest, err := h.queries.GetSongRequestByID(r.Context(), rid)
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusNotFound, "request not found", err)
+		return
+	}
+
+	if songRequest.SessionID != sessionID {
+		writeError(w, http.StatusForbidden, "access denied")
+		return
+	}
+
+	if err := h.queries.SetSongRequestNoteHidden(r.Context(), db.SetSongRequestNoteHiddenParams{NoteHidden: req.Hidden, ID: rid}); err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to update note visibility", err)
+		return
+	}
+	before := songRequestToResponse(songRequest)
+	songRequest.NoteHidden = req.Hidden
+
+	auditChange(r, h.queries, auditEvent{
+		SessionID:  sessionID,
+		Action:     auditRequestNote,
+		TargetType: auditTargetRequest,
+		TargetID:   requestID,
+		Before:     before,
+		After:      songRequestToResponse(songRequest),
+	})
+
+	writeJSON(w, http.StatusOK, songRequestToResponse(songRequest))
+	h.broker.Publish(sessionID)
+}
+
+// playbackTarget returns the session provider's playback target, or nil when
+// approved songs are played by the client. The target need not be connected:
+// the outbox holds the songs until it is. On failure an error response is
+// written and ok is false.
+func (h *RequestHandler) playbackTarget(w http.ResponseWriter, r *http.Request, sessionID string) (target services.PlaybackTarget, ok bool) {
+	session, err := h.queries.GetSessionByID(r.Context(), sessionID)
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusNotFound, "session not found", err)
+		return nil, false
+	}
+	provider, err := h.providers.Get(session.MusicService)
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "session music service is not available", err)
+		return nil, false
+	}
+
+	return provider.PlaybackTarget(), true
+}
+
 // Reject marks a pending song request as rejected with an optional reason (admin only).
 func (h *RequestHandler) Reject(w http.ResponseWriter, r *http.Request) {
 	sessionID := chi.URLParam(r, "id")
@@ -285,13 +604,21 @@ func (h *RequestHandler) Reject(w http.ResponseWriter, r *http.Request) {
 		reason = sql.NullString{String: req.Reason, Valid: true}
 	}
 
-	if err := h.queries.RejectSongRequest(r.Context(), db.RejectSongRequestParams{
+	// Conditional on the request still being pending, so an approved song
+	// is never left queued on the TV as rejected
+	rejected, err := h.queries.RejectSongRequest(r.Context(), db.RejectSongRequestParams{
 		RejectionReason: reason,
+		ProcessedBy:     processedByAdmin,
 		ID:              rid,
-	}); err != nil {
+	})
+	if err != nil {
 		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to reject request", err)
 		return
 	}
+	if rejected == 0 {
+		writeError(w, http.StatusConflict, "only pending requests can be rejected")
+		return
+	}
 
 	// Fetch updated request
 	updatedRequest, err := h.queries.GetSongRequestByID(r.Context(), rid)
@@ -300,6 +627,15 @@ func (h *RequestHandler) Reject(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	auditChange(r, h.queries, auditEvent{
+		SessionID:  sessionID,
+		Action:     auditRequestReject,
+		TargetType: auditTargetRequest,
+		TargetID:   requestID,
+		Before:     songRequestToResponse(songRequest),
+		After:      songRequestToResponse(updatedRequest),
+	})
+
 	writeJSON(w, http.StatusOK, songRequestToResponse(updatedRequest))
 	h.broker.Publish(sessionID)
 }
@@ -315,11 +651,25 @@ func (h *RequestHandler) ArchiveAll(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	requests, err := h.queries.GetSongRequestsBySessionID(r.Context(), sessionID)
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to fetch requests", err)
+		return
+	}
+
 	if err := h.queries.DeleteAllSongRequestsBySessionID(r.Context(), sessionID); err != nil {
 		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to archive requests", err)
 		return
 	}
 
+	auditChange(r, h.queries, auditEvent{
+		SessionID:  sessionID,
+		Action:     auditRequestsArchive,
+		TargetType: auditTargetSession,
+		TargetID:   sessionID,
+		Before:     map[string]int{"requests": len(requests)},
+	})
+
 	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
 	h.broker.Publish(sessionID)
 }
@@ -336,6 +686,7 @@ func songRequestToResponse(req db.SongRequest) models.SongRequestResponse {
 		ExternalURI:     req.ExternalUri,
 		Status:          req.Status,
 		RequestedAt:     req.RequestedAt.Time,
+		NoteHidden:      req.NoteHidden,
 	}
 
 	if req.AlbumArtUrl.Valid {
@@ -350,6 +701,18 @@ func songRequestToResponse(req db.SongRequest) models.SongRequestResponse {
 	if req.RequesterName.Valid {
 		resp.RequesterName = &req.RequesterName.String
 	}
+	if req.Note.Valid {
+		resp.Note = &req.Note.String
+	}
+	if req.ProcessedBy.Valid {
+		resp.ProcessedBy = &req.ProcessedBy.String
+	}
+	if req.DeliveryStatus.Valid {
+		resp.DeliveryStatus = &req.DeliveryStatus.String
+	}
+	if req.DeliveryError.Valid {
+		resp.DeliveryError = &req.DeliveryError.String
+	}
 
 	return resp
 }
@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/songify/backend/internal/broker"
+	"github.com/songify/backend/internal/database"
+	"github.com/songify/backend/internal/database/dbtest"
+	"github.com/songify/backend/internal/db"
+	"github.com/songify/backend/internal/middleware"
+	"github.com/songify/backend/internal/models"
+	"github.com/songify/backend/internal/services"
+)
+
+func TestModerateOnlyPending(t *testing.T) {
+	ctx := context.Background()
+	sqlDB := dbtest.New(t)
+	queries := database.NewQueries(sqlDB)
+
+	dbtest.Session(t, queries, "s1")
+	requests := dbtest.SongRequests(t, queries, "s1", "a", "b")
+	ids := []int64{requests[0].ID, requests[1].ID}
+	if _, err := queries.RejectSongRequest(ctx, db.RejectSongRequestParams{ProcessedBy: processedByAdmin, ID: ids[1]}); err != nil {
+		t.Fatal(err)
+	}
+
+	tv := &fakeTV{}
+	providers := services.NewProviderRegistry(tv)
+	h := NewRequestHandler(sqlDB, queries, broker.New(), providers, time.Minute)
+
+	call := func(handler http.HandlerFunc, id int64) int {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPut, "/", strings.NewReader("{}"))
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", "s1")
+		rctx.URLParams.Add("rid", strconv.FormatInt(id, 10))
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ClaimsKey, &services.Claims{SessionID: "s1", Role: services.RoleAdmin}))
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec.Code
+	}
+
+	if code := call(h.Approve, ids[0]); code != http.StatusOK {
+		t.Fatalf("approve: status = %d", code)
+	}
+	// A second approval, e.g. from another admin's stale page, must not queue the song again
+	if code := call(h.Approve, ids[0]); code != http.StatusConflict {
+		t.Errorf("approve again: status = %d, want 409", code)
+	}
+	if code := call(h.PlayNext, ids[0]); code != http.StatusConflict {
+		t.Errorf("play next after approval: status = %d, want 409", code)
+	}
+	if code := call(h.Approve, ids[1]); code != http.StatusConflict {
+		t.Errorf("approve rejected request: status = %d, want 409", code)
+	}
+	// Rejecting an approved song would leave it queued on the TV
+	if code := call(h.Reject, ids[0]); code != http.StatusConflict {
+		t.Errorf("reject approved request: status = %d, want 409", code)
+	}
+	if code := call(h.Reject, ids[1]); code != http.StatusConflict {
+		t.Errorf("reject again: status = %d, want 409", code)
+	}
+	if sr, err := queries.GetSongRequestByID(ctx, ids[0]); err != nil || sr.Status != "approved" {
+		t.Errorf("approved request status = %q after reject, want approved", sr.Status)
+	}
+
+	deliverEffects(queries, providers)
+	if len(tv.queued) != 1 || tv.queued[0] != "a" {
+		t.Errorf("queued = %v, want the song once", tv.queued)
+	}
+}
+
+// sessionRequest builds a request to a route under session s1 with the given
+// claims and URL parameters.
+func sessionRequest(method, target, body string, claims *services.Claims, params map[string]string) *http.Request {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "s1")
+	for k, v := range params {
+		rctx.URLParams.Add(k, v)
+	}
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	return req.WithContext(context.WithValue(req.Context(), middleware.ClaimsKey, claims))
+}
+
+// submitAs submits a request for trackID to session s1 as the named guest and
+// returns its ID.
+func submitAs(t *testing.T, h *RequestHandler, identity, trackID string) int64 {
+	t.Helper()
+	body, _ := json.Marshal(models.SubmitSongRequestRequest{
+		ExternalTrackID: trackID, TrackName: trackID, ArtistNames: "x", DurationMS: 200000, ExternalURI: trackID,
+	})
+	rec := httptest.NewRecorder()
+	h.Submit(rec, sessionRequest(http.MethodPost, "/", string(body), &services.Claims{SessionID: "s1", Role: services.RoleFriend, Identity: identity}, nil))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("submit: status = %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp models.SongRequestResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	return resp.ID
+}
+
+func TestWithdraw(t *testing.T) {
+	ctx := context.Background()
+	sqlDB := dbtest.New(t)
+	queries := database.NewQueries(sqlDB)
+
+	dbtest.Session(t, queries, "s1")
+	h := NewRequestHandler(sqlDB, queries, broker.New(), services.NewProviderRegistry(&fakeTV{}), time.Minute)
+
+	withdraw := func(identity string, id int64) int {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		claims := &services.Claims{SessionID: "s1", Role: services.RoleFriend, Identity: identity}
+		h.Withdraw(rec, sessionRequest(http.MethodDelete, "/", "", claims, map[string]string{"rid": strconv.FormatInt(id, 10)}))
+		return rec.Code
+	}
+
+	own, others, processed := submitAs(t, h, "Sam", "a"), submitAs(t, h, "Alex", "b"), submitAs(t, h, "Sam", "c")
+	if _, err := queries.ApproveSongRequest(ctx, db.ApproveSongRequestParams{ProcessedBy: processedByAdmin, ID: processed}); err != nil {
+		t.Fatal(err)
+	}
+
+	if code := withdraw("Sam", others); code != http.StatusForbidden {
+		t.Errorf("withdraw another guest's request: status = %d, want 403", code)
+	}
+	if code := withdraw("", others); code != http.StatusForbidden {
+		t.Errorf("withdraw without a name: status = %d, want 403", code)
+	}
+	if code := withdraw("Sam", processed); code != http.StatusConflict {
+		t.Errorf("withdraw approved request: status = %d, want 409", code)
+	}
+	if code := withdraw("Sam", own); code != http.StatusOK {
+		t.Fatalf("withdraw own request: status = %d", code)
+	}
+	if _, err := queries.GetSongRequestByID(ctx, own); err == nil {
+		t.Error("withdrawn request still exists")
+	}
+	if _, err := queries.GetSongRequestByID(ctx, others); err != nil {
+		t.Errorf("another guest's request: %v, want it left alone", err)
+	}
+}
+
+func TestListMine(t *testing.T) {
+	sqlDB := dbtest.New(t)
+	queries := database.NewQueries(sqlDB)
+
+	dbtest.Session(t, queries, "s1")
+	h := NewRequestHandler(sqlDB, queries, broker.New(), services.NewProviderRegistry(&fakeTV{}), time.Minute)
+	submitAs(t, h, "Sam", "a")
+	submitAs(t, h, "Alex", "b")
+	submitAs(t, h, "Sam", "c")
+	submitAs(t, h, "", "d")
+
+	list := func(target, identity string) []string {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		h.List(rec, sessionRequest(http.MethodGet, target, "", &services.Claims{SessionID: "s1", Role: services.RoleFriend, Identity: identity}, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("list: status = %d: %s", rec.Code, rec.Body.String())
+		}
+		var resp []models.SongRequestResponse
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		var tracks []string
+		for _, sr := range resp {
+			tracks = append(tracks, sr.ExternalTrackID)
+		}
+		sort.Strings(tracks)
+		return tracks
+	}
+
+	if got := list("/?mine=true", "Sam"); fmt.Sprint(got) != "[a c]" {
+		t.Errorf("Sam's requests = %v, want [a c]", got)
+	}
+	if got := list("/?mine=true", ""); len(got) != 0 {
+		t.Errorf("anonymous guest's requests = %v, want none", got)
+	}
+	if got := list("/", "Sam"); fmt.Sprint(got) != "[a b c d]" {
+		t.Errorf("all requests = %v, want [a b c d]", got)
+	}
+}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/songify/backend/internal/db"
+	"github.com/songify/backend/internal/middleware"
+	"github.com/songify/backend/internal/models"
+	"github.com/songify/backend/internal/services"
+)
+
+// Rule names reported when a song would be blocked by session settings.
+const (
+	ruleDurationLimit    = "durationLimit"
+	ruleProhibitedArtist = "prohibitedArtist"
+	ruleProhibitedTitle  = "prohibitedTitle"
+	ruleProhibitedNote   = "prohibitedNote"
+	ruleGenre            = "genre"
+	ruleExcludedGenre    = "excludedGenre"
+	ruleReleaseYear      = "releaseYear"
+	rulePopularity       = "popularity"
+)
+
+// sessionRules holds the settings a song must satisfy to be requested in a session.
+type sessionRules struct {
+	durationLimitMs *int64
+	patterns        []db.ProhibitedPattern
+}
+
+// loadSessionRules fetches the duration limit and prohibited patterns for a session.
+func loadSessionRules(ctx context.Context, queries db.Querier, session db.Session) (sessionRules, error) {
+	rules := sessionRules{}
+	if session.SongDurationLimitMs.Valid {
+		rules.durationLimitMs = &session.SongDurationLimitMs.Int64
+	}
+
+	patterns, err := queries.GetProhibitedPatternsBySessionID(ctx, session.ID)
+	if err != nil {
+		return rules, fmt.Errorf("failed to load prohibited patterns: %w", err)
+	}
+	rules.patterns = patterns
+	return rules, nil
+}
+
+// check returns the first rule the song would break, or nil if it is allowed.
+func (r sessionRules) check(trackName, artistNames string, durationMS int64) *models.RuleViolation {
+	if r.durationLimitMs != nil && durationMS > *r.durationLimitMs {
+		return &models.RuleViolation{
+			Rule:    ruleDurationLimit,
+			Message: "Song exceeds duration limit",
+		}
+	}
+
+	for _, p := range r.patterns {
+		if p.PatternType == "artist" && containsIgnoreCase(artistNames, p.Pattern) {
+			return &models.RuleViolation{
+				Rule:    ruleProhibitedArtist,
+				Pattern: p.Pattern,
+				Message: "Artist is prohibited",
+			}
+		}
+		if p.PatternType == "title" && containsIgnoreCase(trackName, p.Pattern) {
+			return &models.RuleViolation{
+				Rule:    ruleProhibitedTitle,
+				Pattern: p.Pattern,
+				Message: "Song title contains prohibited words",
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkNote returns a violation if a request note contains any of the
+// session's prohibited patterns, title or artist, or nil if it is allowed.
+func (r sessionRules) checkNote(note string) *models.RuleViolation {
+	for _, p := range r.patterns {
+		if containsIgnoreCase(note, p.Pattern) {
+			return &models.RuleViolation{
+				Rule:    ruleProhibitedNote,
+				Pattern: p.Pattern,
+				Message: "Note contains prohibited words",
+			}
+		}
+	}
+	return nil
+}
+
+// FallbackTrackFilter returns a services.TrackFilter that lets the auto-DJ
+// play only songs within the session's duration limit, prohibited patterns
+// and track constraints.
+func FallbackTrackFilter(queries db.Querier, providers *services.ProviderRegistry) services.TrackFilter {
+	return func(ctx context.Context, session db.Session, track services.Track) (bool, error) {
+		rules, err := loadSessionRules(ctx, queries, session)
+		if err != nil {
+			return false, err
+		}
+		if rules.check(track.Name, track.ArtistNames, track.DurationMS) != nil {
+			return false, nil
+		}
+		violation, err := trackConstraintViolation(ctx, providers, session, track, "", "")
+		if errors.Is(err, errNoTrackMetadata) {
+			return false, nil
+		}
+		return violation == nil && err == nil, err
+	}
+}
+
+// excludeBlockedRules returns the caller's session rules when a search asks for
+// excludeBlocked=true, or nil when it does not. Excluding blocked results needs
+// a session token; on failure an error response is written and ok is false.
+func excludeBlockedRules(w http.ResponseWriter, r *http.Request, queries db.Querier) (rules *sessionRules, ok bool) {
+	if r.URL.Query().Get("excludeBlocked") != "true" {
+		return nil, true
+	}
+
+	claims := middleware.GetClaims(r.Context())
+	if claims == nil {
+		writeError(w, http.StatusUnauthorized, "session token required to exclude blocked results")
+		return nil, false
+	}
+
+	session, err := queries.GetSessionByID(r.Context(), claims.SessionID)
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusNotFound, "session not found", err)
+		return nil, false
+	}
+
+	loaded, err := loadSessionRules(r.Context(), queries, session)
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to load session rules", err)
+		return nil, false
+	}
+	return &loaded, true
+}
@@ -0,0 +1,284 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/songify/backend/internal/db"
+	"github.com/songify/backend/internal/middleware"
+	"github.com/songify/backend/internal/models"
+	"github.com/songify/backend/internal/services"
+)
+
+// Schedule holds an approved request back to play at a set time or after the
+// current track (admin only). A song still waiting to reach the playback
+// target is held back, and one already queued there is taken back out in the
+// background, so it only plays when the scheduler fires it.
+func (h *RequestHandler) Schedule(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "id")
+	requestID := chi.URLParam(r, "rid")
+	claims := middleware.GetClaims(r.Context())
+
+	if err := requireAdmin(claims, sessionID); err != nil {
+		writeError(w, http.StatusForbidden, "admin access required")
+		return
+	}
+
+	rid, err := strconv.ParseInt(requestID, 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request ID")
+		return
+	}
+
+	var req models.SchedulePlayRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	var playAt sql.NullTime
+	switch req.Mode {
+	case services.ScheduleAt:
+		if req.PlayAt == nil {
+			writeError(w, http.StatusBadRequest, "playAt is required for mode at")
+			return
+		}
+		if req.PlayAt.Before(time.Now()) {
+			writeError(w, http.StatusBadRequest, "playAt must be in the future")
+			return
+		}
+		playAt = sql.NullTime{Time: req.PlayAt.UTC(), Valid: true}
+	case services.ScheduleAfterCurrent:
+	default:
+		writeError(w, http.StatusBadRequest, "mode must be at or after_current")
+		return
+	}
+
+	songRequest, err := h.queries.GetSongRequestByID(r.Context(), rid)
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusNotFound, "request not found", err)
+		return
+	}
+
+	if songRequest.SessionID != sessionID {
+		writeError(w, http.StatusForbidden, "access denied")
+		return
+	}
+
+	if songRequest.Status != "approved" {
+		writeError(w, http.StatusConflict, "only approved requests can be scheduled")
+		return
+	}
+	scheduled, err := h.queries.CountScheduledPlaysForRequest(r.Context(), rid)
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to check schedules", err)
+		return
+	}
+	if scheduled > 0 {
+		writeError(w, http.StatusConflict, "request is already scheduled")
+		return
+	}
+
+	session, err := h.queries.GetSessionByID(r.Context(), sessionID)
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusNotFound, "session not found", err)
+		return
+	}
+	provider, err := h.providers.Get(session.MusicService)
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "session music service is not available", err)
+		return
+	}
+	target := provider.PlaybackTarget()
+	if target == nil {
+		writeError(w, http.StatusBadRequest, "this session's music service cannot play scheduled songs")
+		return
+	}
+
+	tx, err := h.db.BeginTx(r.Context(), nil)
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to start transaction", err)
+		return
+	}
+	defer tx.Rollback()
+	qtx := h.queries.InTx(tx)
+
+	// Keep the song off the TV's queue so it does not play early
+	cancelled, err := qtx.CancelOutboxEntries(r.Context(), sql.NullInt64{Int64: rid, Valid: true})
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to cancel delivery", err)
+		return
+	}
+	if cancelled > 0 {
+		// Nothing left to deliver until the scheduler plays it
+		if err := qtx.SetSongRequestDelivery(r.Context(), db.SetSongRequestDeliveryParams{ID: rid}); err != nil {
+			writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to update delivery status", err)
+			return
+		}
+	} else if songRequest.DeliveryStatus.String == services.DeliveryDelivered {
+		if err := services.QueueEffect(r.Context(), qtx, songRequest, services.EffectRemove); err != nil {
+			writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to queue removal from TV", err)
+			return
+		}
+	}
+
+	play, err := qtx.CreateScheduledPlay(r.Context(), db.CreateScheduledPlayParams{
+		SessionID: sessionID,
+		RequestID: rid,
+		Mode:      req.Mode,
+		PlayAt:    playAt,
+	})
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to schedule request", err)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to commit schedule", err)
+		return
+	}
+
+	resp := scheduledPlayToResponse(play)
+	auditChange(r, h.queries, auditEvent{
+		SessionID:  sessionID,
+		Action:     auditScheduleCreate,
+		TargetType: auditTargetSchedule,
+		TargetID:   strconv.FormatInt(play.ID, 10),
+		After:      resp,
+	})
+
+	h.broker.Publish(sessionID)
+
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+// ListSchedules returns the session's scheduled plays, newest first (admin only).
+func (h *RequestHandler) ListSchedules(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "id")
+	claims := middleware.GetClaims(r.Context())
+
+	if err := requireAdmin(claims, sessionID); err != nil {
+		writeError(w, http.StatusForbidden, "admin access required")
+		return
+	}
+
+	plays, err := h.queries.GetScheduledPlaysBySessionID(r.Context(), sessionID)
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to fetch schedules", err)
+		return
+	}
+
+	resp := make([]models.ScheduledPlayResponse, 0, len(plays))
+	for _, play := range plays {
+		resp = append(resp, scheduledPlayToResponse(play))
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// CancelSchedule cancels a play that has not fired yet (admin only). The song
+// goes back to the end of the playback target's queue in the background.
+func (h *RequestHandler) CancelSchedule(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "id")
+	claims := middleware.GetClaims(r.Context())
+
+	if err := requireAdmin(claims, sessionID); err != nil {
+		writeError(w, http.StatusForbidden, "admin access required")
+		return
+	}
+
+	scheduleID, err := strconv.ParseInt(chi.URLParam(r, "scheduleId"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid schedule ID")
+		return
+	}
+
+	play, err := h.queries.GetScheduledPlayByID(r.Context(), db.GetScheduledPlayByIDParams{ID: scheduleID, SessionID: sessionID})
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusNotFound, "schedule not found", err)
+		return
+	}
+
+	target, ok := h.playbackTarget(w, r, sessionID)
+	if !ok {
+		return
+	}
+
+	tx, err := h.db.BeginTx(r.Context(), nil)
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to start transaction", err)
+		return
+	}
+	defer tx.Rollback()
+	qtx := h.queries.InTx(tx)
+
+	// Conditional on the status, so a play the scheduler just fired stays fired
+	result, err := qtx.CancelScheduledPlay(r.Context(), db.CancelScheduledPlayParams{ID: scheduleID, SessionID: sessionID})
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to cancel schedule", err)
+		return
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to check cancellation result", err)
+		return
+	}
+	if rowsAffected == 0 {
+		writeError(w, http.StatusConflict, "only scheduled plays can be cancelled")
+		return
+	}
+
+	// The request is still approved, so queue it like any other
+	songRequest, err := qtx.GetSongRequestByID(r.Context(), play.RequestID)
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to fetch request", err)
+		return
+	}
+	if songRequest.Status == "approved" && target != nil {
+		if err := services.QueueEffect(r.Context(), qtx, songRequest, services.EffectEnqueue); err != nil {
+			writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to queue song for TV", err)
+			return
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to commit cancellation", err)
+		return
+	}
+
+	auditChange(r, h.queries, auditEvent{
+		SessionID:  sessionID,
+		Action:     auditScheduleCancel,
+		TargetType: auditTargetSchedule,
+		TargetID:   strconv.FormatInt(scheduleID, 10),
+		Before:     scheduledPlayToResponse(play),
+	})
+
+	h.broker.Publish(sessionID)
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// scheduledPlayToResponse converts a database scheduled play to the API response format.
+func scheduledPlayToResponse(play db.ScheduledPlay) models.ScheduledPlayResponse {
+	resp := models.ScheduledPlayResponse{
+		ID:        play.ID,
+		RequestID: play.RequestID,
+		Mode:      play.Mode,
+		Status:    play.Status,
+		CreatedAt: play.CreatedAt.Time,
+	}
+	if play.PlayAt.Valid {
+		resp.PlayAt = &play.PlayAt.Time
+	}
+	if play.Error.Valid {
+		resp.Error = &play.Error.String
+	}
+	if play.FiredAt.Valid {
+		resp.FiredAt = &play.FiredAt.Time
+	}
+	return resp
+}
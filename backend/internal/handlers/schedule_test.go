@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/songify/backend/internal/broker"
+	"github.com/songify/backend/internal/database"
+	"github.com/songify/backend/internal/database/dbtest"
+	"github.com/songify/backend/internal/middleware"
+	"github.com/songify/backend/internal/models"
+	"github.com/songify/backend/internal/services"
+)
+
+func TestSchedule(t *testing.T) {
+	sqlDB := dbtest.New(t)
+	queries := database.NewQueries(sqlDB)
+
+	dbtest.Session(t, queries, "s1")
+	var ids []int64
+	for _, sr := range dbtest.SongRequests(t, queries, "s1", "dance", "waiting", "early") {
+		ids = append(ids, sr.ID)
+	}
+
+	tv := &fakeTV{}
+	providers := services.NewProviderRegistry(tv)
+	h := NewRequestHandler(sqlDB, queries, broker.New(), providers, time.Minute)
+
+	call := func(handler http.HandlerFunc, method string, body any, params map[string]string) *httptest.ResponseRecorder {
+		t.Helper()
+		var buf bytes.Buffer
+		if body != nil {
+			if err := json.NewEncoder(&buf).Encode(body); err != nil {
+				t.Fatal(err)
+			}
+		}
+		req := httptest.NewRequest(method, "/", &buf)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", "s1")
+		for k, v := range params {
+			rctx.URLParams.Add(k, v)
+		}
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ClaimsKey, &services.Claims{SessionID: "s1", Role: services.RoleAdmin, Identity: "admin"}))
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec
+	}
+	approve := func(id int64) {
+		t.Helper()
+		if rec := call(h.Approve, http.MethodPut, nil, map[string]string{"rid": strconv.FormatInt(id, 10)}); rec.Code != http.StatusOK {
+			t.Fatalf("approve: status = %d: %s", rec.Code, rec.Body.String())
+		}
+	}
+	schedule := func(id int64, req models.SchedulePlayRequest) *httptest.ResponseRecorder {
+		return call(h.Schedule, http.MethodPost, req, map[string]string{"rid": strconv.FormatInt(id, 10)})
+	}
+
+	// One song already on the TV, one approved while it was away
+	approve(ids[0])
+	deliverEffects(queries, providers)
+	tv.offline = true
+	approve(ids[2])
+
+	playAt := time.Now().Add(time.Hour)
+	rec := schedule(ids[0], models.SchedulePlayRequest{Mode: services.ScheduleAt, PlayAt: &playAt})
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("schedule: status = %d: %s", rec.Code, rec.Body.String())
+	}
+	var created models.ScheduledPlayResponse
+	if err := json.NewDecoder(rec.Body).Decode(&created); err != nil {
+		t.Fatal(err)
+	}
+	if created.Status != services.ScheduleStatusScheduled || created.PlayAt == nil || !created.PlayAt.Equal(playAt) {
+		t.Errorf("schedule = %+v, want scheduled at %v", created, playAt)
+	}
+	rec = schedule(ids[2], models.SchedulePlayRequest{Mode: services.ScheduleAfterCurrent})
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("schedule undelivered: status = %d: %s", rec.Code, rec.Body.String())
+	}
+	var held models.ScheduledPlayResponse
+	if err := json.NewDecoder(rec.Body).Decode(&held); err != nil {
+		t.Fatal(err)
+	}
+
+	// Once the TV is back, neither song is left to play early
+	tv.offline = false
+	deliverEffects(queries, providers)
+	if len(tv.queued) != 0 {
+		t.Errorf("queued = %v, want the scheduled songs held back from the TV", tv.queued)
+	}
+	if early, err := queries.GetSongRequestByID(context.Background(), ids[2]); err != nil || early.DeliveryStatus.Valid {
+		t.Errorf("held back delivery = %q, want none until the song is played", early.DeliveryStatus.String)
+	}
+
+	past := time.Now().Add(-time.Minute)
+	tests := []struct {
+		name string
+		id   int64
+		req  models.SchedulePlayRequest
+		want int
+	}{
+		{"already scheduled", ids[0], models.SchedulePlayRequest{Mode: services.ScheduleAfterCurrent}, http.StatusConflict},
+		{"pending request", ids[1], models.SchedulePlayRequest{Mode: services.ScheduleAfterCurrent}, http.StatusConflict},
+		{"time in the past", ids[1], models.SchedulePlayRequest{Mode: services.ScheduleAt, PlayAt: &past}, http.StatusBadRequest},
+		{"missing time", ids[1], models.SchedulePlayRequest{Mode: services.ScheduleAt}, http.StatusBadRequest},
+		{"unknown mode", ids[1], models.SchedulePlayRequest{Mode: "soon"}, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		if rec := schedule(tt.id, tt.req); rec.Code != tt.want {
+			t.Errorf("%s: status = %d, want %d: %s", tt.name, rec.Code, tt.want, rec.Body.String())
+		}
+	}
+
+	scheduleID := map[string]string{"scheduleId": strconv.FormatInt(created.ID, 10)}
+	if rec := call(h.CancelSchedule, http.MethodDelete, nil, scheduleID); rec.Code != http.StatusOK {
+		t.Fatalf("cancel: status = %d: %s", rec.Code, rec.Body.String())
+	}
+	deliverEffects(queries, providers)
+	if len(tv.queued) != 1 || tv.queued[0] != "dance" {
+		t.Errorf("queued = %v, want the cancelled song back in the TV queue", tv.queued)
+	}
+	if rec := call(h.CancelSchedule, http.MethodDelete, nil, scheduleID); rec.Code != http.StatusConflict {
+		t.Errorf("cancel twice: status = %d, want 409", rec.Code)
+	}
+
+	// A play cancelled while the TV is away is queued once it is back
+	tv.offline = true
+	if rec := call(h.CancelSchedule, http.MethodDelete, nil, map[string]string{"scheduleId": strconv.FormatInt(held.ID, 10)}); rec.Code != http.StatusOK {
+		t.Fatalf("cancel while offline: status = %d: %s", rec.Code, rec.Body.String())
+	}
+	deliverEffects(queries, providers)
+	tv.offline = false
+	deliverEffects(queries, providers)
+	if len(tv.queued) != 2 || tv.queued[1] != "early" {
+		t.Errorf("queued = %v, want the song cancelled while offline queued after reconnecting", tv.queued)
+	}
+
+	rec = call(h.ListSchedules, http.MethodGet, nil, nil)
+	var list []models.ScheduledPlayResponse
+	if err := json.NewDecoder(rec.Body).Decode(&list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[0].Status != services.ScheduleStatusCancelled || list[1].Status != services.ScheduleStatusCancelled {
+		t.Errorf("schedules = %+v, want both plays cancelled", list)
+	}
+}
@@ -0,0 +1,316 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/songify/backend/internal/db"
+	"github.com/songify/backend/internal/middleware"
+	"github.com/songify/backend/internal/models"
+	"github.com/songify/backend/internal/services"
+)
+
+// searchYearRe matches a year filter: "1999" or "1990-1999".
+var searchYearRe = regexp.MustCompile(`^\d{4}(-\d{4})?$`)
+
+// searchDurations are the duration buckets YouTube supports.
+var searchDurations = map[string]bool{"any": true, "short": true, "medium": true, "long": true}
+
+// SearchHandler searches the music service a session is configured for.
+type SearchHandler struct {
+	providers *services.ProviderRegistry
+	queries   db.Querier
+}
+
+// NewSearchHandler creates a SearchHandler with the given music providers and database queries.
+func NewSearchHandler(providers *services.ProviderRegistry, queries db.Querier) *SearchHandler {
+	return &SearchHandler{providers: providers, queries: queries}
+}
+
+// Search returns a page of results from the session's music service, each
+// annotated with whether it is already requested or blocked by session rules.
+// Accepts the same query parameters as the service-specific search endpoints.
+func (h *SearchHandler) Search(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "id")
+	claims := middleware.GetClaims(r.Context())
+
+	if err := requireSession(claims, sessionID); err != nil {
+		writeError(w, http.StatusForbidden, "access denied")
+		return
+	}
+
+	session, err := h.queries.GetSessionByID(r.Context(), sessionID)
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusNotFound, "session not found", err)
+		return
+	}
+
+	provider, err := h.providers.Get(session.MusicService)
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "session music service is not available", err)
+		return
+	}
+
+	opts, err := parseSearchOptions(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	page, err := provider.Search(r.Context(), opts)
+	if errors.Is(err, services.ErrInvalidSearch) {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if errors.Is(err, services.ErrQuotaExhausted) {
+		writeError(w, http.StatusServiceUnavailable, "search is unavailable for the rest of the day; previously searched terms still work")
+		return
+	}
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "search failed", err)
+		return
+	}
+
+	results, err := h.annotate(r.Context(), session, page.Tracks)
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to check results against session", err)
+		return
+	}
+
+	response := models.SessionSearchResponse{
+		MusicService: session.MusicService,
+		Results:      results,
+		NextOffset:   page.NextOffset,
+	}
+	if page.NextPageToken != "" {
+		response.NextPageToken = &page.NextPageToken
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// ResolveLink turns a pasted share link or URI into a song ready to submit,
+// annotated like a search result. Links for another music service and links
+// to playlists or albums are rejected with a message saying why.
+func (h *SearchHandler) ResolveLink(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "id")
+	claims := middleware.GetClaims(r.Context())
+
+	if err := requireSession(claims, sessionID); err != nil {
+		writeError(w, http.StatusForbidden, "access denied")
+		return
+	}
+
+	var req models.ResolveLinkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if strings.TrimSpace(req.URL) == "" {
+		writeError(w, http.StatusBadRequest, "url is required")
+		return
+	}
+
+	session, err := h.queries.GetSessionByID(r.Context(), sessionID)
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusNotFound, "session not found", err)
+		return
+	}
+
+	provider, err := h.providers.Get(session.MusicService)
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "session music service is not available", err)
+		return
+	}
+
+	trackID, err := provider.ResolveURL(req.URL)
+	if errors.Is(err, services.ErrUnrecognizedLink) {
+		writeError(w, http.StatusBadRequest, h.unrecognizedLinkMessage(req.URL, provider.Name()))
+		return
+	}
+	if errors.Is(err, services.ErrLinkNotTrack) {
+		writeError(w, http.StatusBadRequest, "That link is a playlist or album; paste a link to a single song")
+		return
+	}
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusBadRequest, "invalid link", err)
+		return
+	}
+
+	track, err := provider.GetTrack(r.Context(), trackID)
+	if errors.Is(err, services.ErrTrackNotFound) {
+		writeError(w, http.StatusNotFound, "song not found")
+		return
+	}
+	if errors.Is(err, services.ErrQuotaExhausted) {
+		writeError(w, http.StatusServiceUnavailable, "song lookup is unavailable for the rest of the day; search for the song instead")
+		return
+	}
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusBadGateway, "failed to look up song", err)
+		return
+	}
+
+	results, err := h.annotate(r.Context(), session, []services.Track{*track})
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to check song against session", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, results[0])
+}
+
+// Match converts a track from another music service to its best match on the
+// session's service, returning it as an annotated search result with the
+// match confidence. Takes musicService and trackId query parameters.
+func (h *SearchHandler) Match(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "id")
+	claims := middleware.GetClaims(r.Context())
+
+	if err := requireSession(claims, sessionID); err != nil {
+		writeError(w, http.StatusForbidden, "access denied")
+		return
+	}
+
+	session, err := h.queries.GetSessionByID(r.Context(), sessionID)
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusNotFound, "session not found", err)
+		return
+	}
+
+	provider, err := h.providers.Get(session.MusicService)
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "session music service is not available", err)
+		return
+	}
+
+	sourceService := r.URL.Query().Get("musicService")
+	trackID := r.URL.Query().Get("trackId")
+	if sourceService == session.MusicService {
+		writeError(w, http.StatusBadRequest, "track is already from the session's music service")
+		return
+	}
+	match, ok := matchTrack(w, r, h.providers, sourceService, trackID, provider)
+	if !ok {
+		return
+	}
+
+	results, err := h.annotate(r.Context(), session, []services.Track{match.Track})
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to check song against session", err)
+		return
+	}
+	results[0].Match = trackMatchToResponse(match, sourceService, trackID)
+
+	writeJSON(w, http.StatusOK, results[0])
+}
+
+// unrecognizedLinkMessage explains why a link cannot be used in a session,
+// naming the service it belongs to when another provider recognizes it.
+func (h *SearchHandler) unrecognizedLinkMessage(rawURL, sessionService string) string {
+	for _, p := range h.providers.Providers() {
+		if p.Name() == sessionService {
+			continue
+		}
+		if _, err := p.ResolveURL(rawURL); !errors.Is(err, services.ErrUnrecognizedLink) {
+			return fmt.Sprintf("That is a %s link, but this session uses %s", serviceDisplayName(p.Name()), serviceDisplayName(sessionService))
+		}
+	}
+	return fmt.Sprintf("That link is not a %s song link", serviceDisplayName(sessionService))
+}
+
+// annotate converts provider tracks to search results, flagging those already
+// requested in the session or blocked by its rules.
+func (h *SearchHandler) annotate(ctx context.Context, session db.Session, tracks []services.Track) ([]models.SearchResultResponse, error) {
+	rules, err := loadSessionRules(ctx, h.queries, session)
+	if err != nil {
+		return nil, err
+	}
+	requested, err := h.queries.GetRequestedTrackStatuses(ctx, session.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load requests: %w", err)
+	}
+	requestStatus := make(map[string]string, len(requested))
+	for _, req := range requested {
+		requestStatus[req.ExternalTrackID] = req.Status
+	}
+
+	results := make([]models.SearchResultResponse, len(tracks))
+	for i, track := range tracks {
+		result := models.SearchResultResponse{
+			ExternalTrackID: track.ID,
+			TrackName:       track.Name,
+			ArtistNames:     track.ArtistNames,
+			AlbumName:       track.AlbumName,
+			AlbumArtURL:     track.AlbumArtURL,
+			DurationMS:      track.DurationMS,
+			ExternalURI:     track.URI,
+		}
+		if status, ok := requestStatus[track.ID]; ok {
+			result.AlreadyRequested = true
+			result.RequestStatus = &status
+		}
+		result.Violation = rules.check(track.Name, track.ArtistNames, track.DurationMS)
+		results[i] = result
+	}
+	return results, nil
+}
+
+// serviceDisplayName returns the user-facing name of a music service.
+func serviceDisplayName(name string) string {
+	switch name {
+	case "spotify":
+		return "Spotify"
+	case "youtube":
+		return "YouTube"
+	case "subsonic":
+		return "Subsonic"
+	default:
+		return name
+	}
+}
+
+// parseSearchOptions reads and validates provider-neutral search query
+// parameters. A search needs q, or an artist or album filter.
+func parseSearchOptions(query url.Values) (services.SearchOptions, error) {
+	opts := services.SearchOptions{
+		Query:     query.Get("q"),
+		PageToken: query.Get("pageToken"),
+		Artist:    query.Get("artist"),
+		Album:     query.Get("album"),
+		Year:      query.Get("year"),
+		Category:  query.Get("category"),
+		Duration:  query.Get("duration"),
+	}
+	if opts.Query == "" && opts.Artist == "" && opts.Album == "" {
+		return opts, errors.New("query parameter 'q' is required")
+	}
+	if opts.Year != "" && !searchYearRe.MatchString(opts.Year) {
+		return opts, errors.New("year must be YYYY or YYYY-YYYY")
+	}
+	if opts.Category == "music" {
+		opts.Category = services.YouTubeCategoryMusic
+	} else if _, err := strconv.Atoi(opts.Category); opts.Category != "" && err != nil {
+		return opts, errors.New("category must be 'music' or a numeric category ID")
+	}
+	if opts.Duration != "" && !searchDurations[opts.Duration] {
+		return opts, errors.New("duration must be one of: any, short, medium, long")
+	}
+
+	var err error
+	if opts.Limit, err = intQueryParam(query, "limit", 20, 1, 50); err != nil {
+		return opts, err
+	}
+	if opts.Offset, err = intQueryParam(query, "offset", 0, 0, 1000); err != nil {
+		return opts, err
+	}
+	return opts, nil
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/songify/backend/internal/db"
+	"github.com/songify/backend/internal/services"
+)
+
+func TestSessionRulesCheck(t *testing.T) {
+	limit := int64(300000)
+	rules := sessionRules{
+		durationLimitMs: &limit,
+		patterns: []db.ProhibitedPattern{
+			{PatternType: "artist", Pattern: "nickelback"},
+			{PatternType: "title", Pattern: "baby shark"},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		trackName   string
+		artistNames string
+		durationMS  int64
+		wantRule    string
+	}{
+		{"allowed", "Get Lucky", "Daft Punk", 240000, ""},
+		{"too long", "Echoes", "Pink Floyd", 1400000, ruleDurationLimit},
+		{"prohibited artist", "Photograph", "Nickelback", 240000, ruleProhibitedArtist},
+		{"prohibited title", "Baby Shark Dance", "Pinkfong", 120000, ruleProhibitedTitle},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violation := rules.check(tt.trackName, tt.artistNames, tt.durationMS)
+			if tt.wantRule == "" {
+				if violation != nil {
+					t.Errorf("check() = %+v, want nil", violation)
+				}
+				return
+			}
+			if violation == nil || violation.Rule != tt.wantRule {
+				t.Errorf("check() = %+v, want rule %q", violation, tt.wantRule)
+			}
+		})
+	}
+}
+
+func TestParseSpotifySearchOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    services.SpotifySearchOptions
+		wantErr bool
+	}{
+		{"defaults", "q=daft+punk", services.SpotifySearchOptions{Query: "daft punk", Limit: 20}, false},
+		{"paged with filters", "q=lucky&artist=Daft+Punk&year=2010-2015&limit=10&offset=30",
+			services.SpotifySearchOptions{Query: "lucky", Artist: "Daft Punk", Year: "2010-2015", Limit: 10, Offset: 30}, false},
+		{"filter only", "album=Discovery", services.SpotifySearchOptions{Album: "Discovery", Limit: 20}, false},
+		{"missing query", "", services.SpotifySearchOptions{}, true},
+		{"bad year", "q=x&year=90s", services.SpotifySearchOptions{}, true},
+		{"offset too large", "q=x&offset=5000", services.SpotifySearchOptions{}, true},
+		{"bad limit", "q=x&limit=abc", services.SpotifySearchOptions{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, _ := url.ParseQuery(tt.query)
+			got, err := parseSpotifySearchOptions(values)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("options = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseYouTubeSearchOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    services.YouTubeSearchOptions
+		wantErr bool
+	}{
+		{"defaults", "q=lofi", services.YouTubeSearchOptions{Query: "lofi", Limit: 20}, false},
+		{"music category", "q=lofi&category=music&duration=short&pageToken=CAoQAA",
+			services.YouTubeSearchOptions{Query: "lofi", Limit: 20, CategoryID: "10", Duration: "short", PageToken: "CAoQAA"}, false},
+		{"numeric category", "q=lofi&category=24", services.YouTubeSearchOptions{Query: "lofi", Limit: 20, CategoryID: "24"}, false},
+		{"bad category", "q=lofi&category=films", services.YouTubeSearchOptions{}, true},
+		{"bad duration", "q=lofi&duration=tiny", services.YouTubeSearchOptions{}, true},
+		{"missing query", "category=music", services.YouTubeSearchOptions{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, _ := url.ParseQuery(tt.query)
+			got, err := parseYouTubeSearchOptions(values)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("options = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnrecognizedLinkMessage(t *testing.T) {
+	h := NewSearchHandler(services.NewProviderRegistry(
+		services.NewSpotifyProvider(nil),
+		services.NewYouTubeProvider(nil, nil),
+	), nil)
+
+	tests := []struct {
+		url            string
+		sessionService string
+		want           string
+	}{
+		{"https://youtu.be/dQw4w9WgXcQ", "spotify", "That is a YouTube link, but this session uses Spotify"},
+		{"spotify:track:4uLU6hMCjMI75M1A2tKUQC", "youtube", "That is a Spotify link, but this session uses YouTube"},
+		{"https://open.spotify.com/album/4aawyAB9vmqN3uQ7FjRGTy", "youtube", "That is a Spotify link, but this session uses YouTube"},
+		{"https://example.com/song", "spotify", "That link is not a Spotify song link"},
+	}
+
+	for _, tt := range tests {
+		if got := h.unrecognizedLinkMessage(tt.url, tt.sessionService); got != tt.want {
+			t.Errorf("unrecognizedLinkMessage(%q, %q) = %q, want %q", tt.url, tt.sessionService, got, tt.want)
+		}
+	}
+}
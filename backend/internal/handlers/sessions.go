@@ -3,6 +3,7 @@ package handlers
 import (
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
 	"strconv"
@@ -23,18 +24,20 @@ import (
 
 // SessionHandler manages session lifecycle: creation, joining, and settings.
 type SessionHandler struct {
-	queries          *db.Queries
+	queries          db.Querier
 	authService      *services.AuthService
 	friendKeyService *services.FriendKeyService
+	providers        *services.ProviderRegistry
 	cfg              *config.Config
 }
 
 // NewSessionHandler creates a SessionHandler with the required dependencies.
-func NewSessionHandler(queries *db.Queries, authService *services.AuthService, friendKeyService *services.FriendKeyService, cfg *config.Config) *SessionHandler {
+func NewSessionHandler(queries db.Querier, authService *services.AuthService, friendKeyService *services.FriendKeyService, providers *services.ProviderRegistry, cfg *config.Config) *SessionHandler {
 	return &SessionHandler{
 		queries:          queries,
 		authService:      authService,
 		friendKeyService: friendKeyService,
+		providers:        providers,
 		cfg:              cfg,
 	}
 }
@@ -54,10 +57,10 @@ func (h *SessionHandler) Create(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if req.MusicService == "" {
-		req.MusicService = "spotify"
+		req.MusicService = h.providers.Default()
 	}
-	if req.MusicService != "spotify" && req.MusicService != "youtube" {
-		writeError(w, http.StatusBadRequest, "musicService must be 'spotify' or 'youtube'")
+	if _, err := h.providers.Get(req.MusicService); err != nil {
+		writeError(w, http.StatusBadRequest, "musicService must be one of: "+strings.Join(h.providers.Names(), ", "))
 		return
 	}
 
@@ -126,6 +129,24 @@ func (h *SessionHandler) Create(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// The creator has no token yet, so attribute the event explicitly
+	auditChange(r, h.queries, auditEvent{
+		SessionID:  session.ID,
+		Actor:      session.AdminName,
+		Role:       string(services.RoleAdmin),
+		Action:     auditSessionCreate,
+		TargetType: auditTargetSession,
+		TargetID:   session.ID,
+		After: map[string]any{
+			"displayName":         session.DisplayName,
+			"musicService":        session.MusicService,
+			"spotifyPlaylistId":   req.SpotifyPlaylistID,
+			"songDurationLimitMs": req.SongDurationLimitMs,
+			"prohibitedArtists":   req.ProhibitedArtists,
+			"prohibitedTitles":    req.ProhibitedTitles,
+		},
+	})
+
 	token, err := h.authService.GenerateToken(session.ID, services.RoleAdmin, session.AdminName)
 	if err != nil {
 		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to generate token", err)
@@ -301,9 +322,16 @@ func (h *SessionHandler) Get(w http.ResponseWriter, r *http.Request) {
 	if session.SongDurationLimitMs.Valid {
 		resp.SongDurationLimitMs = &session.SongDurationLimitMs.Int64
 	}
+	if session.DuplicateWindowMinutes.Valid {
+		resp.DuplicateWindowMinutes = &session.DuplicateWindowMinutes.Int64
+	}
+	if constraints, err := sessionTrackConstraints(session); err == nil {
+		resp.TrackConstraints = constraints
+	}
 
 	if isAdmin {
 		resp.FriendAccessKey = session.FriendAccessKey
+		resp.FallbackPlaylist = fallbackPlaylist(session)
 	}
 
 	// Fetch prohibited patterns
@@ -343,7 +371,13 @@ func (h *SessionHandler) UpdateDurationLimit(w http.ResponseWriter, r *http.Requ
 		durationLimit = sql.NullInt64{Int64: *req.SongDurationLimitMs, Valid: true}
 	}
 
-	err := h.queries.UpdateSessionSettings(r.Context(), db.UpdateSessionSettingsParams{
+	session, err := h.queries.GetSessionByID(r.Context(), sessionID)
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusNotFound, "session not found", err)
+		return
+	}
+
+	err = h.queries.UpdateSessionSettings(r.Context(), db.UpdateSessionSettingsParams{
 		ID:                  sessionID,
 		SongDurationLimitMs: durationLimit,
 	})
@@ -352,9 +386,180 @@ func (h *SessionHandler) UpdateDurationLimit(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	before := models.UpdateDurationLimitRequest{}
+	if session.SongDurationLimitMs.Valid {
+		before.SongDurationLimitMs = &session.SongDurationLimitMs.Int64
+	}
+	auditChange(r, h.queries, auditEvent{
+		SessionID:  sessionID,
+		Action:     auditDurationLimitUpdate,
+		TargetType: auditTargetSession,
+		TargetID:   sessionID,
+		Before:     before,
+		After:      req,
+	})
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// UpdateDuplicateWindow sets or clears how long an approved song blocks
+// requests for the same song. Pending requests always block duplicates.
+func (h *SessionHandler) UpdateDuplicateWindow(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "id")
+	claims := middleware.GetClaims(r.Context())
+
+	if err := requireAdmin(claims, sessionID); err != nil {
+		writeError(w, http.StatusForbidden, "admin access required")
+		return
+	}
+
+	var req models.UpdateDuplicateWindowRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	var window sql.NullInt64
+	if req.DuplicateWindowMinutes != nil {
+		if *req.DuplicateWindowMinutes < 0 {
+			writeError(w, http.StatusBadRequest, "duplicateWindowMinutes must not be negative")
+			return
+		}
+		window = sql.NullInt64{Int64: *req.DuplicateWindowMinutes, Valid: true}
+	}
+
+	session, err := h.queries.GetSessionByID(r.Context(), sessionID)
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusNotFound, "session not found", err)
+		return
+	}
+
+	err = h.queries.UpdateSessionDuplicateWindow(r.Context(), db.UpdateSessionDuplicateWindowParams{
+		ID:                     sessionID,
+		DuplicateWindowMinutes: window,
+	})
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to update duplicate window", err)
+		return
+	}
+
+	before := models.UpdateDuplicateWindowRequest{}
+	if session.DuplicateWindowMinutes.Valid {
+		before.DuplicateWindowMinutes = &session.DuplicateWindowMinutes.Int64
+	}
+	auditChange(r, h.queries, auditEvent{
+		SessionID:  sessionID,
+		Action:     auditDuplicateWindowUpdate,
+		TargetType: auditTargetSession,
+		TargetID:   sessionID,
+		Before:     before,
+		After:      req,
+	})
+
 	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
 }
 
+// UpdateFallbackPlaylist sets or clears the playlist the auto-DJ plays from
+// when the session's TV runs out of songs (admin only). The playlist may come
+// from another music service; its tracks are matched to the session's.
+func (h *SessionHandler) UpdateFallbackPlaylist(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "id")
+	claims := middleware.GetClaims(r.Context())
+
+	if err := requireAdmin(claims, sessionID); err != nil {
+		writeError(w, http.StatusForbidden, "admin access required")
+		return
+	}
+
+	var req models.UpdateFallbackPlaylistRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	session, err := h.queries.GetSessionByID(r.Context(), sessionID)
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusNotFound, "session not found", err)
+		return
+	}
+
+	var service, playlistID sql.NullString
+	if req.PlaylistID != nil && strings.TrimSpace(*req.PlaylistID) != "" {
+		if req.MusicService == "" {
+			req.MusicService = session.MusicService
+		}
+		sessionProvider, err := h.providers.Get(session.MusicService)
+		if err != nil || sessionProvider.PlaybackTarget() == nil {
+			writeError(w, http.StatusBadRequest, "fallback playlists need a session that plays on a TV")
+			return
+		}
+		provider, err := h.providers.Get(req.MusicService)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "unknown music service")
+			return
+		}
+		source, ok := provider.(services.PlaylistSource)
+		if !ok {
+			writeError(w, http.StatusBadRequest, "music service does not support fallback playlists")
+			return
+		}
+
+		// Check the playlist can be read before saving it
+		id := strings.TrimSpace(*req.PlaylistID)
+		tracks, err := source.PlaylistTracks(r.Context(), id)
+		if errors.Is(err, services.ErrTrackNotFound) {
+			writeError(w, http.StatusBadRequest, "playlist not found")
+			return
+		}
+		if err != nil {
+			writeErrorWithCause(r.Context(), w, http.StatusBadGateway, "failed to fetch playlist", err)
+			return
+		}
+		if len(tracks) == 0 {
+			writeError(w, http.StatusBadRequest, "playlist has no playable tracks")
+			return
+		}
+		service = sql.NullString{String: req.MusicService, Valid: true}
+		playlistID = sql.NullString{String: id, Valid: true}
+	}
+
+	err = h.queries.UpdateSessionFallback(r.Context(), db.UpdateSessionFallbackParams{
+		FallbackService:    service,
+		FallbackPlaylistID: playlistID,
+		ID:                 sessionID,
+	})
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to update fallback playlist", err)
+		return
+	}
+
+	var after *models.FallbackPlaylist
+	if playlistID.Valid {
+		after = &models.FallbackPlaylist{MusicService: service.String, PlaylistID: playlistID.String}
+	}
+	auditChange(r, h.queries, auditEvent{
+		SessionID:  sessionID,
+		Action:     auditFallbackUpdate,
+		TargetType: auditTargetSession,
+		TargetID:   sessionID,
+		Before:     map[string]any{"fallbackPlaylist": fallbackPlaylist(session)},
+		After:      map[string]any{"fallbackPlaylist": after},
+	})
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// fallbackPlaylist returns the session's fallback playlist, or nil if none is set.
+func fallbackPlaylist(session db.Session) *models.FallbackPlaylist {
+	if !session.FallbackPlaylistID.Valid {
+		return nil
+	}
+	return &models.FallbackPlaylist{
+		MusicService: session.FallbackService.String,
+		PlaylistID:   session.FallbackPlaylistID.String,
+	}
+}
+
 // GetProhibitedPatterns returns all artist/title patterns that block song requests.
 func (h *SessionHandler) GetProhibitedPatterns(w http.ResponseWriter, r *http.Request) {
 	sessionID := chi.URLParam(r, "id")
@@ -419,11 +624,20 @@ func (h *SessionHandler) CreateProhibitedPattern(w http.ResponseWriter, r *http.
 		return
 	}
 
-	writeJSON(w, http.StatusCreated, models.ProhibitedPatternResponse{
+	resp := models.ProhibitedPatternResponse{
 		ID:          pattern.ID,
 		PatternType: pattern.PatternType,
 		Pattern:     pattern.Pattern,
+	}
+	auditChange(r, h.queries, auditEvent{
+		SessionID:  sessionID,
+		Action:     auditPatternCreate,
+		TargetType: auditTargetPattern,
+		TargetID:   strconv.FormatInt(pattern.ID, 10),
+		After:      resp,
 	})
+
+	writeJSON(w, http.StatusCreated, resp)
 }
 
 // DeleteProhibitedPattern removes a blocked pattern by its ID.
@@ -443,6 +657,15 @@ func (h *SessionHandler) DeleteProhibitedPattern(w http.ResponseWriter, r *http.
 		return
 	}
 
+	pattern, err := h.queries.GetProhibitedPatternByID(r.Context(), db.GetProhibitedPatternByIDParams{
+		ID:        patternID,
+		SessionID: sessionID,
+	})
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusNotFound, "pattern not found", err)
+		return
+	}
+
 	result, err := h.queries.DeleteProhibitedPatternBySession(r.Context(), db.DeleteProhibitedPatternBySessionParams{
 		ID:        patternID,
 		SessionID: sessionID,
@@ -462,5 +685,17 @@ func (h *SessionHandler) DeleteProhibitedPattern(w http.ResponseWriter, r *http.
 		return
 	}
 
+	auditChange(r, h.queries, auditEvent{
+		SessionID:  sessionID,
+		Action:     auditPatternDelete,
+		TargetType: auditTargetPattern,
+		TargetID:   patternIDStr,
+		Before: models.ProhibitedPatternResponse{
+			ID:          pattern.ID,
+			PatternType: pattern.PatternType,
+			Pattern:     pattern.Pattern,
+		},
+	})
+
 	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
 }
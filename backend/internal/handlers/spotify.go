@@ -4,6 +4,8 @@ import (
 	"database/sql"
 	"encoding/json"
 	"net/http"
+	"net/url"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/songify/backend/internal/db"
@@ -15,55 +17,94 @@ import (
 // SpotifyHandler handles Spotify-specific requests: search and playlist linking.
 type SpotifyHandler struct {
 	spotifyService *services.SpotifyService
-	queries        *db.Queries
+	queries        db.Querier
 }
 
 // NewSpotifyHandler creates a SpotifyHandler with the given Spotify service and database queries.
-func NewSpotifyHandler(spotifyService *services.SpotifyService, queries *db.Queries) *SpotifyHandler {
+func NewSpotifyHandler(spotifyService *services.SpotifyService, queries db.Querier) *SpotifyHandler {
 	return &SpotifyHandler{spotifyService: spotifyService, queries: queries}
 }
 
-// Search handles track search queries, returning matching tracks from Spotify.
+// Search handles track search queries, returning a page of matching tracks from Spotify.
+// Supports limit/offset pagination, artist/album/year filters, and, for callers
+// with a session token, excludeBlocked=true to hide tracks that break session rules.
 func (h *SpotifyHandler) Search(w http.ResponseWriter, r *http.Request) {
-	query := r.URL.Query().Get("q")
-	if query == "" {
-		writeError(w, http.StatusBadRequest, "query parameter 'q' is required")
+	opts, err := parseSpotifySearchOptions(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rules, ok := excludeBlockedRules(w, r, h.queries)
+	if !ok {
 		return
 	}
 
-	tracks, err := h.spotifyService.Search(r.Context(), query, 20)
+	result, err := h.spotifyService.Search(r.Context(), opts)
 	if err != nil {
 		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "search failed", err)
 		return
 	}
 
 	response := models.SpotifySearchResponse{
-		Tracks: make([]models.SpotifyTrackResponse, len(tracks)),
+		Tracks:     make([]models.SpotifyTrackResponse, 0, len(result.Tracks)),
+		Total:      result.Total,
+		Offset:     result.Offset,
+		NextOffset: result.NextOffset,
 	}
 
-	for i, track := range tracks {
-		artists := make([]string, len(track.Artists))
-		for j, artist := range track.Artists {
-			artists[j] = artist.Name
+	for _, track := range result.Tracks {
+		trackResp := spotifyTrackToResponse(track)
+		if rules != nil {
+			trackResp.Violation = rules.check(trackResp.Name, strings.Join(trackResp.Artists, ", "), int64(trackResp.DurationMS))
+			if trackResp.Violation != nil {
+				response.Hidden = append(response.Hidden, trackResp)
+				continue
+			}
 		}
+		response.Tracks = append(response.Tracks, trackResp)
+	}
 
-		var albumArt string
-		if len(track.Album.Images) > 0 {
-			albumArt = track.Album.Images[0].URL
-		}
+	writeJSON(w, http.StatusOK, response)
+}
 
-		response.Tracks[i] = models.SpotifyTrackResponse{
-			ID:          track.ID,
-			Name:        track.Name,
-			URI:         track.URI,
-			DurationMS:  track.DurationMS,
-			AlbumName:   track.Album.Name,
-			AlbumArtURL: albumArt,
-			Artists:     artists,
-		}
+// parseSpotifySearchOptions reads and validates Spotify search query parameters.
+func parseSpotifySearchOptions(query url.Values) (services.SpotifySearchOptions, error) {
+	opts, err := parseSearchOptions(query)
+	if err != nil {
+		return services.SpotifySearchOptions{}, err
 	}
+	return services.SpotifySearchOptions{
+		Query:  opts.Query,
+		Artist: opts.Artist,
+		Album:  opts.Album,
+		Year:   opts.Year,
+		Limit:  opts.Limit,
+		Offset: opts.Offset,
+	}, nil
+}
 
-	writeJSON(w, http.StatusOK, response)
+// spotifyTrackToResponse converts a Spotify API track to the API response format.
+func spotifyTrackToResponse(track services.SpotifyTrack) models.SpotifyTrackResponse {
+	artists := make([]string, len(track.Artists))
+	for j, artist := range track.Artists {
+		artists[j] = artist.Name
+	}
+
+	var albumArt string
+	if len(track.Album.Images) > 0 {
+		albumArt = track.Album.Images[0].URL
+	}
+
+	return models.SpotifyTrackResponse{
+		ID:          track.ID,
+		Name:        track.Name,
+		URI:         track.URI,
+		DurationMS:  track.DurationMS,
+		AlbumName:   track.Album.Name,
+		AlbumArtURL: albumArt,
+		Artists:     artists,
+	}
 }
 
 // UpdatePlaylist sets or updates the Spotify playlist for the session.
@@ -87,7 +128,13 @@ func (h *SpotifyHandler) UpdatePlaylist(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	err := h.queries.UpdateSessionPlaylist(r.Context(), db.UpdateSessionPlaylistParams{
+	session, err := h.queries.GetSessionByID(r.Context(), sessionID)
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusNotFound, "session not found", err)
+		return
+	}
+
+	err = h.queries.UpdateSessionPlaylist(r.Context(), db.UpdateSessionPlaylistParams{
 		ID:                  sessionID,
 		SpotifyPlaylistID:   sql.NullString{String: req.SpotifyPlaylistID, Valid: true},
 		SpotifyPlaylistName: sql.NullString{String: req.SpotifyPlaylistName, Valid: req.SpotifyPlaylistName != ""},
@@ -97,5 +144,17 @@ func (h *SpotifyHandler) UpdatePlaylist(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	auditChange(r, h.queries, auditEvent{
+		SessionID:  sessionID,
+		Action:     auditPlaylistUpdate,
+		TargetType: auditTargetSession,
+		TargetID:   sessionID,
+		Before: models.UpdatePlaylistRequest{
+			SpotifyPlaylistID:   session.SpotifyPlaylistID.String,
+			SpotifyPlaylistName: session.SpotifyPlaylistName.String,
+		},
+		After: req,
+	})
+
 	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
 }
@@ -12,18 +12,22 @@ import (
 
 // SSEHandler serves Server-Sent Events streams for real-time request updates.
 type SSEHandler struct {
-	broker *broker.Broker
+	broker   broker.Broker
+	shutdown <-chan struct{}
 }
 
-// NewSSEHandler creates an SSEHandler backed by the given broker.
-func NewSSEHandler(b *broker.Broker) *SSEHandler {
-	return &SSEHandler{broker: b}
+// NewSSEHandler creates an SSEHandler backed by the given broker. Open
+// streams are ended when shutdown is closed.
+func NewSSEHandler(b broker.Broker, shutdown <-chan struct{}) *SSEHandler {
+	return &SSEHandler{broker: b, shutdown: shutdown}
 }
 
 // Stream opens an SSE connection scoped to a session. It sends an initial
 // "connected" event, then pushes "requests_changed" each time the broker
 // signals for this session. A heartbeat comment is sent every 30 seconds
-// to keep the connection alive through proxies.
+// to keep the connection alive through proxies. When the server shuts down
+// it sends "server_restarting" and closes the stream, so clients reconnect
+// to another instance or once the server is back.
 func (h *SSEHandler) Stream(w http.ResponseWriter, r *http.Request) {
 	sessionID := chi.URLParam(r, "id")
 	claims := middleware.GetClaims(r.Context())
@@ -59,6 +63,10 @@ func (h *SSEHandler) Stream(w http.ResponseWriter, r *http.Request) {
 		select {
 		case <-ctx.Done():
 			return
+		case <-h.shutdown:
+			fmt.Fprintf(w, "event: server_restarting\ndata: reconnect\n\n")
+			flusher.Flush()
+			return
 		case <-ch:
 			fmt.Fprintf(w, "event: requests_changed\ndata: refresh\n\n")
 			flusher.Flush()
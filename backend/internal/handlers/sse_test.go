@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/songify/backend/internal/broker"
+	"github.com/songify/backend/internal/middleware"
+	"github.com/songify/backend/internal/services"
+)
+
+func TestStreamEndsOnShutdown(t *testing.T) {
+	shutdown := make(chan struct{})
+	close(shutdown)
+	h := NewSSEHandler(broker.New(), shutdown)
+
+	req := httptest.NewRequest("GET", "/sessions/s1/requests/stream", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "s1")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	req = req.WithContext(context.WithValue(req.Context(), middleware.ClaimsKey, &services.Claims{SessionID: "s1", Role: services.RoleFriend, Identity: "Sam"}))
+	rec := httptest.NewRecorder()
+	h.Stream(rec, req)
+
+	want := "event: connected\ndata: ok\n\nevent: server_restarting\ndata: reconnect\n\n"
+	if got := rec.Body.String(); got != want {
+		t.Errorf("stream = %q, want %q", got, want)
+	}
+	if !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/event-stream") {
+		t.Errorf("Content-Type = %q", rec.Header().Get("Content-Type"))
+	}
+}
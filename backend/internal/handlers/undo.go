@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/songify/backend/internal/db"
+	"github.com/songify/backend/internal/middleware"
+	"github.com/songify/backend/internal/services"
+)
+
+// Undo returns an approved or rejected request to pending (admin only).
+// Only requests processed within the undo window can be reverted. An approved
+// song that has not reached the playback target yet is held back; one that
+// has is taken back out of its queue in the background. The revert is
+// recorded in the audit log.
+func (h *RequestHandler) Undo(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "id")
+	requestID := chi.URLParam(r, "rid")
+	claims := middleware.GetClaims(r.Context())
+
+	if err := requireAdmin(claims, sessionID); err != nil {
+		writeError(w, http.StatusForbidden, "admin access required")
+		return
+	}
+
+	rid, err := strconv.ParseInt(requestID, 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request ID")
+		return
+	}
+
+	songRequest, err := h.queries.GetSongRequestByID(r.Context(), rid)
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusNotFound, "request not found", err)
+		return
+	}
+
+	if songRequest.SessionID != sessionID {
+		writeError(w, http.StatusForbidden, "access denied")
+		return
+	}
+
+	if songRequest.Status != "approved" && songRequest.Status != "rejected" {
+		writeError(w, http.StatusConflict, "only approved or rejected requests can be undone")
+		return
+	}
+	if !songRequest.ProcessedAt.Valid || time.Since(songRequest.ProcessedAt.Time) > h.undoWindow {
+		writeError(w, http.StatusConflict, "the undo window for this request has passed")
+		return
+	}
+
+	tx, err := h.db.BeginTx(r.Context(), nil)
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to start transaction", err)
+		return
+	}
+	defer tx.Rollback()
+	qtx := h.queries.InTx(tx)
+
+	// Conditional on the status we checked, so a concurrent undo reverts only once
+	result, err := qtx.RevertSongRequest(r.Context(), db.RevertSongRequestParams{ID: rid, Status: songRequest.Status})
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to undo request", err)
+		return
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to check undo result", err)
+		return
+	}
+	if rowsAffected == 0 {
+		writeError(w, http.StatusConflict, "request was changed by someone else")
+		return
+	}
+
+	// Take an approved song back off the TV before it can play
+	if songRequest.Status == "approved" {
+		cancelled, err := qtx.CancelOutboxEntries(r.Context(), sql.NullInt64{Int64: rid, Valid: true})
+		if err != nil {
+			writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to cancel delivery", err)
+			return
+		}
+		if cancelled == 0 && songRequest.DeliveryStatus.String == services.DeliveryDelivered {
+			if err := services.QueueEffect(r.Context(), qtx, songRequest, services.EffectRemove); err != nil {
+				writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to queue removal from TV", err)
+				return
+			}
+		}
+	}
+
+	updatedRequest, err := qtx.GetSongRequestByID(r.Context(), rid)
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to fetch updated request", err)
+		return
+	}
+
+	if err := recordAudit(r.Context(), qtx, claims, auditEvent{
+		SessionID:  sessionID,
+		Action:     auditRequestUndo,
+		TargetType: auditTargetRequest,
+		TargetID:   requestID,
+		Before:     songRequestToResponse(songRequest),
+		After:      songRequestToResponse(updatedRequest),
+	}); err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to record audit event", err)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to commit undo", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, songRequestToResponse(updatedRequest))
+	h.broker.Publish(sessionID)
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/songify/backend/internal/broker"
+	"github.com/songify/backend/internal/database"
+	"github.com/songify/backend/internal/database/dbtest"
+	"github.com/songify/backend/internal/db"
+	"github.com/songify/backend/internal/middleware"
+	"github.com/songify/backend/internal/models"
+	"github.com/songify/backend/internal/services"
+)
+
+func TestUndo(t *testing.T) {
+	ctx := context.Background()
+	sqlDB := dbtest.New(t)
+	queries := database.NewQueries(sqlDB)
+
+	dbtest.Session(t, queries, "s1")
+	var ids []int64
+	for _, sr := range dbtest.SongRequests(t, queries, "s1", "a", "b", "c") {
+		ids = append(ids, sr.ID)
+	}
+
+	tv := &fakeTV{queued: []string{"a"}}
+	providers := services.NewProviderRegistry(tv)
+	h := NewRequestHandler(sqlDB, queries, broker.New(), providers, time.Minute)
+
+	if _, err := queries.ApproveSongRequest(ctx, db.ApproveSongRequestParams{ProcessedBy: processedByAdmin, ID: ids[0]}); err != nil {
+		t.Fatal(err)
+	}
+	if err := queries.SetSongRequestDelivery(ctx, db.SetSongRequestDeliveryParams{
+		DeliveryStatus: sql.NullString{String: services.DeliveryDelivered, Valid: true},
+		ID:             ids[0],
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := queries.RejectSongRequest(ctx, db.RejectSongRequestParams{ProcessedBy: processedByAdmin, ID: ids[1]}); err != nil {
+		t.Fatal(err)
+	}
+	// A literal timestamp keeps the statement valid in both SQL dialects
+	if _, err := sqlDB.ExecContext(ctx, `UPDATE song_requests SET processed_at = '2000-01-01 00:00:00' WHERE id = `+strconv.FormatInt(ids[1], 10)); err != nil {
+		t.Fatal(err)
+	}
+
+	undo := func(id int64) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, "/api/sessions/s1/requests/"+strconv.FormatInt(id, 10)+"/undo", nil)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", "s1")
+		rctx.URLParams.Add("rid", strconv.FormatInt(id, 10))
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ClaimsKey, &services.Claims{SessionID: "s1", Role: services.RoleAdmin, Identity: "admin"}))
+		rec := httptest.NewRecorder()
+		h.Undo(rec, req)
+		return rec
+	}
+
+	rec := undo(ids[0])
+	if rec.Code != http.StatusOK {
+		t.Fatalf("undo approved: status = %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp models.SongRequestResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Status != "pending" || resp.ProcessedAt != nil || resp.ProcessedBy != nil || resp.DeliveryStatus != nil {
+		t.Errorf("undone request = %+v, want pending with no processing details", resp)
+	}
+	deliverEffects(queries, providers)
+	if len(tv.queued) != 0 {
+		t.Errorf("queued = %v, want the song removed from the TV", tv.queued)
+	}
+
+	events, err := queries.ListAuditEvents(ctx, db.ListAuditEventsParams{SessionID: "s1", ID: math.MaxInt64, Limit: 1})
+	if err != nil || len(events) != 1 {
+		t.Fatalf("audit events = %v, %v", events, err)
+	}
+	event := events[0]
+	if event.Actor != "admin" || event.Action != auditRequestUndo || event.TargetID != strconv.FormatInt(ids[0], 10) {
+		t.Errorf("audit event = %s/%s/%s, want admin/%s/%d", event.Actor, event.Action, event.TargetID, auditRequestUndo, ids[0])
+	}
+	var beforeResp models.SongRequestResponse
+	if err := json.Unmarshal([]byte(event.BeforeValue.String), &beforeResp); err != nil || beforeResp.Status != "approved" {
+		t.Errorf("audit before = %s, want the approved request", event.BeforeValue.String)
+	}
+
+	if rec := undo(ids[0]); rec.Code != http.StatusConflict {
+		t.Errorf("undo pending: status = %d, want 409", rec.Code)
+	}
+	if rec := undo(ids[1]); rec.Code != http.StatusConflict {
+		t.Errorf("undo after window: status = %d, want 409", rec.Code)
+	}
+}
@@ -4,7 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
 
 	"github.com/getsentry/sentry-go"
 	"github.com/songify/backend/internal/logging"
@@ -67,3 +70,17 @@ func writeErrorWithCause(ctx context.Context, w http.ResponseWriter, status int,
 		}
 	}
 }
+
+// intQueryParam parses an optional integer query parameter, returning def when
+// it is absent and an error when it is malformed or outside [min, max].
+func intQueryParam(query url.Values, name string, def, min, max int) (int, error) {
+	raw := query.Get(name)
+	if raw == "" {
+		return def, nil
+	}
+	v, err := strconv.Atoi(raw)
+	if err != nil || v < min || v > max {
+		return 0, fmt.Errorf("%s must be between %d and %d", name, min, max)
+	}
+	return v, nil
+}
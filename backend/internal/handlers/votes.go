@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/songify/backend/internal/db"
+	"github.com/songify/backend/internal/middleware"
+	"github.com/songify/backend/internal/models"
+)
+
+// Vote sets the caller's vote on a pending request in their session, then
+// applies the first auto-moderation rule with a vote score condition that
+// matches the request's new score. Guests need a name to vote, and each
+// name has one vote per request.
+func (h *RequestHandler) Vote(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "id")
+	claims := middleware.GetClaims(r.Context())
+
+	if err := requireSession(claims, sessionID); err != nil {
+		writeError(w, http.StatusForbidden, "access denied")
+		return
+	}
+	if claims.Identity == "" {
+		writeError(w, http.StatusForbidden, "join with a name to vote")
+		return
+	}
+
+	rid, err := strconv.ParseInt(chi.URLParam(r, "rid"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request ID")
+		return
+	}
+
+	var req models.VoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.Value < -1 || req.Value > 1 {
+		writeError(w, http.StatusBadRequest, "value must be 1, -1 or 0")
+		return
+	}
+
+	session, err := h.queries.GetSessionByID(r.Context(), sessionID)
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusNotFound, "session not found", err)
+		return
+	}
+	songRequest, err := h.queries.GetSongRequestByID(r.Context(), rid)
+	if err != nil || songRequest.SessionID != sessionID {
+		writeErrorWithCause(r.Context(), w, http.StatusNotFound, "request not found", err)
+		return
+	}
+	if songRequest.Status != "pending" {
+		writeError(w, http.StatusConflict, "only pending requests can be voted on")
+		return
+	}
+
+	if req.Value == 0 {
+		err = h.queries.DeleteRequestVote(r.Context(), db.DeleteRequestVoteParams{RequestID: rid, Voter: claims.Identity})
+	} else {
+		err = h.queries.UpsertRequestVote(r.Context(), db.UpsertRequestVoteParams{RequestID: rid, Voter: claims.Identity, Value: req.Value})
+	}
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to save vote", err)
+		return
+	}
+	score, err := h.queries.GetRequestVoteScore(r.Context(), rid)
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to count votes", err)
+		return
+	}
+
+	// Only rules that look at the score can change their mind on a vote;
+	// the others already had their say when the request was submitted.
+	autoRules, err := loadAutoModerationRules(r.Context(), h.queries, sessionID)
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to load auto-moderation rules", err)
+		return
+	}
+	voteRules := autoRules[:0]
+	for _, rule := range autoRules {
+		if rule.usesVoteScore() {
+			voteRules = append(voteRules, rule)
+		}
+	}
+	status := songRequest.Status
+	if len(voteRules) > 0 {
+		pending, err := h.queries.CountPendingSongRequests(r.Context(), sessionID)
+		if err != nil {
+			writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to count pending requests", err)
+			return
+		}
+		autoRule := firstMatchingRule(voteRules, autoModInput{
+			RequesterName: songRequest.RequesterName.String,
+			ArtistNames:   songRequest.ArtistNames,
+			DurationMS:    songRequest.DurationMs,
+			QueueLength:   max(pending-1, 0),
+			VoteScore:     score,
+			Now:           time.Now(),
+		})
+		if autoRule != nil {
+			songRequest = h.applyAutoModeration(r.Context(), session, songRequest, autoRule)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, models.VoteResponse{Score: score, Request: songRequestToResponse(songRequest)})
+	if songRequest.Status != status {
+		h.broker.Publish(sessionID)
+	}
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/songify/backend/internal/broker"
+	"github.com/songify/backend/internal/database"
+	"github.com/songify/backend/internal/database/dbtest"
+	"github.com/songify/backend/internal/db"
+	"github.com/songify/backend/internal/middleware"
+	"github.com/songify/backend/internal/models"
+	"github.com/songify/backend/internal/services"
+)
+
+func TestVoteAppliesAutoModeration(t *testing.T) {
+	ctx := context.Background()
+	sqlDB := dbtest.New(t)
+	queries := database.NewQueries(sqlDB)
+
+	dbtest.Session(t, queries, "s1")
+	two, minusTwo := int64(2), int64(-2)
+	for _, req := range []models.AutoModerationRuleRequest{
+		{Name: "Crowd pleaser", Action: "approve", Conditions: models.AutoModerationConditions{MinVoteScore: &two}},
+		{Name: "Booed", Action: "reject", Position: 1, Conditions: models.AutoModerationConditions{MaxVoteScore: &minusTwo}},
+	} {
+		rule, err := validateAutoModerationRule(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := queries.CreateAutoModerationRule(ctx, db.CreateAutoModerationRuleParams{
+			SessionID: "s1", Name: rule.Name, Action: rule.Action, Reason: rule.Reason,
+			Conditions: rule.AutoModerationRule.Conditions, Position: rule.Position, Enabled: rule.Enabled,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	requests := dbtest.SongRequests(t, queries, "s1", "one-more-time", "too-long")
+	loved, hated := requests[0], requests[1]
+
+	tv := &fakeTV{}
+	providers := services.NewProviderRegistry(tv)
+	h := NewRequestHandler(sqlDB, queries, broker.New(), providers, time.Minute)
+
+	vote := func(identity string, requestID, value int64) (int, models.VoteResponse) {
+		t.Helper()
+		body, _ := json.Marshal(models.VoteRequest{Value: value})
+		req := httptest.NewRequest(http.MethodPut, "/api/sessions/s1/requests/x/vote", bytes.NewReader(body))
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", "s1")
+		rctx.URLParams.Add("rid", strconv.FormatInt(requestID, 10))
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ClaimsKey, &services.Claims{SessionID: "s1", Role: services.RoleFriend, Identity: identity}))
+		rec := httptest.NewRecorder()
+		h.Vote(rec, req)
+		var resp models.VoteResponse
+		if rec.Code == http.StatusOK {
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return rec.Code, resp
+	}
+
+	if code, _ := vote("", loved.ID, 1); code != http.StatusForbidden {
+		t.Errorf("anonymous vote: status = %d, want 403", code)
+	}
+	if code, _ := vote("Sam", loved.ID, 2); code != http.StatusBadRequest {
+		t.Errorf("vote of 2: status = %d, want 400", code)
+	}
+
+	// Voting twice replaces the earlier vote
+	vote("Sam", loved.ID, 1)
+	if _, resp := vote("Sam", loved.ID, 1); resp.Score != 1 || resp.Request.Status != "pending" {
+		t.Errorf("after Sam's vote = %+v, want score 1 and still pending", resp)
+	}
+	_, resp := vote("Alex", loved.ID, 1)
+	if resp.Score != 2 || resp.Request.Status != "approved" || resp.Request.ProcessedBy == nil || *resp.Request.ProcessedBy != "auto:Crowd pleaser" {
+		t.Errorf("after Alex's vote = %+v, want approved by Crowd pleaser", resp)
+	}
+	deliverEffects(queries, providers)
+	if len(tv.queued) != 1 || tv.queued[0] != "one-more-time" {
+		t.Errorf("queued = %v, want the approved song sent to the TV", tv.queued)
+	}
+	if code, _ := vote("Kim", loved.ID, 1); code != http.StatusConflict {
+		t.Errorf("vote on approved request: status = %d, want 409", code)
+	}
+
+	// Taking a vote back lowers the score again
+	vote("Sam", hated.ID, -1)
+	vote("Alex", hated.ID, 1)
+	if _, resp := vote("Alex", hated.ID, 0); resp.Score != -1 || resp.Request.Status != "pending" {
+		t.Errorf("after Alex takes the vote back = %+v, want score -1 and pending", resp)
+	}
+	if _, resp := vote("Kim", hated.ID, -1); resp.Score != -2 || resp.Request.Status != "rejected" {
+		t.Errorf("after Kim's vote = %+v, want rejected by Booed", resp)
+	}
+}
@@ -2,7 +2,9 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"net/url"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/songify/backend/internal/db"
@@ -15,45 +17,89 @@ import (
 type YouTubeHandler struct {
 	youtubeService *services.YouTubeService
 	loungeManager  *services.LoungeManager
-	queries        *db.Queries
+	queries        db.Querier
 }
 
 // NewYouTubeHandler creates a YouTubeHandler with the given YouTube service, lounge manager, and database queries.
-func NewYouTubeHandler(youtubeService *services.YouTubeService, loungeManager *services.LoungeManager, queries *db.Queries) *YouTubeHandler {
+func NewYouTubeHandler(youtubeService *services.YouTubeService, loungeManager *services.LoungeManager, queries db.Querier) *YouTubeHandler {
 	return &YouTubeHandler{youtubeService: youtubeService, loungeManager: loungeManager, queries: queries}
 }
 
-// Search handles video search queries, returning matching videos from YouTube.
+// Search handles video search queries, returning a page of matching videos from YouTube.
+// Supports limit/pageToken pagination, category ("music" or a category ID) and
+// duration filters, and, for callers with a session token, excludeBlocked=true
+// to hide videos that break session rules.
 func (h *YouTubeHandler) Search(w http.ResponseWriter, r *http.Request) {
-	query := r.URL.Query().Get("q")
-	if query == "" {
-		writeError(w, http.StatusBadRequest, "query parameter 'q' is required")
+	opts, err := parseYouTubeSearchOptions(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rules, ok := excludeBlockedRules(w, r, h.queries)
+	if !ok {
 		return
 	}
 
-	videos, err := h.youtubeService.Search(r.Context(), query, 20)
+	result, err := h.youtubeService.Search(r.Context(), opts)
+	if errors.Is(err, services.ErrQuotaExhausted) {
+		writeError(w, http.StatusServiceUnavailable, "YouTube search is unavailable for the rest of the day; previously searched terms still work")
+		return
+	}
 	if err != nil {
 		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "search failed", err)
 		return
 	}
 
 	response := models.YouTubeSearchResponse{
-		Videos: make([]models.YouTubeVideoResponse, len(videos)),
+		Videos: make([]models.YouTubeVideoResponse, 0, len(result.Videos)),
+	}
+	if result.NextPageToken != "" {
+		response.NextPageToken = &result.NextPageToken
+	}
+	if result.PrevPageToken != "" {
+		response.PrevPageToken = &result.PrevPageToken
 	}
 
-	for i, video := range videos {
-		response.Videos[i] = models.YouTubeVideoResponse{
+	for _, video := range result.Videos {
+		videoResp := models.YouTubeVideoResponse{
 			ID:           video.ID,
 			Title:        video.Title,
 			ChannelTitle: video.ChannelTitle,
 			ThumbnailURL: video.ThumbnailURL,
 			DurationMS:   video.DurationMS,
 		}
+		if rules != nil {
+			videoResp.Violation = rules.check(video.Title, video.ChannelTitle, video.DurationMS)
+			if videoResp.Violation != nil {
+				response.Hidden = append(response.Hidden, videoResp)
+				continue
+			}
+		}
+		response.Videos = append(response.Videos, videoResp)
 	}
 
 	writeJSON(w, http.StatusOK, response)
 }
 
+// parseYouTubeSearchOptions reads and validates YouTube search query parameters.
+func parseYouTubeSearchOptions(query url.Values) (services.YouTubeSearchOptions, error) {
+	opts, err := parseSearchOptions(query)
+	if err != nil {
+		return services.YouTubeSearchOptions{}, err
+	}
+	if opts.Query == "" {
+		return services.YouTubeSearchOptions{}, errors.New("query parameter 'q' is required")
+	}
+	return services.YouTubeSearchOptions{
+		Query:      opts.Query,
+		Limit:      opts.Limit,
+		PageToken:  opts.PageToken,
+		CategoryID: opts.Category,
+		Duration:   opts.Duration,
+	}, nil
+}
+
 // Pair pairs the session with a YouTube TV using a pairing code.
 func (h *YouTubeHandler) Pair(w http.ResponseWriter, r *http.Request) {
 	sessionID := chi.URLParam(r, "id")
@@ -86,15 +132,29 @@ func (h *YouTubeHandler) Pair(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.loungeManager.Pair(r.Context(), sessionID, req.PairingCode); err != nil {
+	var name string
+	if req.Name != nil {
+		name = *req.Name
+	}
+
+	screen, err := h.loungeManager.Pair(r.Context(), sessionID, req.PairingCode, name)
+	if err != nil {
 		writeErrorWithCause(r.Context(), w, http.StatusBadGateway, "failed to pair with TV", err)
 		return
 	}
 
-	writeJSON(w, http.StatusOK, h.buildLoungeStatusResponse(sessionID))
+	auditChange(r, h.queries, auditEvent{
+		SessionID:  sessionID,
+		Action:     auditLoungePair,
+		TargetType: auditTargetScreen,
+		TargetID:   screen.ScreenID,
+		After:      map[string]string{"screenName": screen.ScreenName},
+	})
+
+	h.writeLoungeStatus(w, r, sessionID)
 }
 
-// Disconnect disconnects from a paired YouTube TV.
+// Disconnect disconnects from every paired YouTube TV.
 func (h *YouTubeHandler) Disconnect(w http.ResponseWriter, r *http.Request) {
 	sessionID := chi.URLParam(r, "id")
 	claims := middleware.GetClaims(r.Context())
@@ -105,10 +165,46 @@ func (h *YouTubeHandler) Disconnect(w http.ResponseWriter, r *http.Request) {
 	}
 
 	h.loungeManager.Disconnect(sessionID)
-	writeJSON(w, http.StatusOK, models.LoungeStatusResponse{Status: string(services.LoungeStatusDisconnected)})
+	auditChange(r, h.queries, auditEvent{
+		SessionID:  sessionID,
+		Action:     auditLoungeDisconnect,
+		TargetType: auditTargetSession,
+		TargetID:   sessionID,
+	})
+	h.writeLoungeStatus(w, r, sessionID)
+}
+
+// DisconnectScreen unpairs a single YouTube TV, leaving the others connected.
+func (h *YouTubeHandler) DisconnectScreen(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "id")
+	screenID := chi.URLParam(r, "screenId")
+	claims := middleware.GetClaims(r.Context())
+
+	if err := requireAdmin(claims, sessionID); err != nil {
+		writeError(w, http.StatusForbidden, "admin access required")
+		return
+	}
+
+	if err := h.loungeManager.DisconnectScreen(r.Context(), sessionID, screenID); err != nil {
+		if errors.Is(err, services.ErrLoungeScreenNotFound) {
+			writeError(w, http.StatusNotFound, "screen not found")
+			return
+		}
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to disconnect screen", err)
+		return
+	}
+
+	auditChange(r, h.queries, auditEvent{
+		SessionID:  sessionID,
+		Action:     auditLoungeDisconnect,
+		TargetType: auditTargetScreen,
+		TargetID:   screenID,
+	})
+
+	h.writeLoungeStatus(w, r, sessionID)
 }
 
-// Reconnect re-binds to the TV using existing credentials without a new pairing code.
+// Reconnect re-binds to every paired TV using existing credentials without a new pairing code.
 func (h *YouTubeHandler) Reconnect(w http.ResponseWriter, r *http.Request) {
 	sessionID := chi.URLParam(r, "id")
 	claims := middleware.GetClaims(r.Context())
@@ -123,7 +219,116 @@ func (h *YouTubeHandler) Reconnect(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	writeJSON(w, http.StatusOK, h.buildLoungeStatusResponse(sessionID))
+	auditChange(r, h.queries, auditEvent{
+		SessionID:  sessionID,
+		Action:     auditLoungeReconnect,
+		TargetType: auditTargetSession,
+		TargetID:   sessionID,
+	})
+
+	h.writeLoungeStatus(w, r, sessionID)
+}
+
+// ReconnectScreen re-binds a single paired TV using its stored credentials.
+func (h *YouTubeHandler) ReconnectScreen(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "id")
+	screenID := chi.URLParam(r, "screenId")
+	claims := middleware.GetClaims(r.Context())
+
+	if err := requireAdmin(claims, sessionID); err != nil {
+		writeError(w, http.StatusForbidden, "admin access required")
+		return
+	}
+
+	if err := h.loungeManager.ReconnectScreen(r.Context(), sessionID, screenID); err != nil {
+		if errors.Is(err, services.ErrLoungeScreenNotFound) {
+			writeError(w, http.StatusNotFound, "screen not found")
+			return
+		}
+		writeErrorWithCause(r.Context(), w, http.StatusBadGateway, "failed to reconnect to TV", err)
+		return
+	}
+
+	auditChange(r, h.queries, auditEvent{
+		SessionID:  sessionID,
+		Action:     auditLoungeReconnect,
+		TargetType: auditTargetScreen,
+		TargetID:   screenID,
+	})
+	h.writeLoungeStatus(w, r, sessionID)
+}
+
+// SetPrimary makes a paired TV the one that receives videos in primary mode.
+func (h *YouTubeHandler) SetPrimary(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "id")
+	screenID := chi.URLParam(r, "screenId")
+	claims := middleware.GetClaims(r.Context())
+
+	if err := requireAdmin(claims, sessionID); err != nil {
+		writeError(w, http.StatusForbidden, "admin access required")
+		return
+	}
+
+	before, _ := h.loungeSettings(r, sessionID)
+	if err := h.loungeManager.SetPrimary(r.Context(), sessionID, screenID); err != nil {
+		if errors.Is(err, services.ErrLoungeScreenNotFound) {
+			writeError(w, http.StatusNotFound, "screen not found")
+			return
+		}
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to set primary screen", err)
+		return
+	}
+
+	auditChange(r, h.queries, auditEvent{
+		SessionID:  sessionID,
+		Action:     auditLoungePrimary,
+		TargetType: auditTargetScreen,
+		TargetID:   screenID,
+		Before:     map[string]string{"primaryScreenId": before},
+		After:      map[string]string{"primaryScreenId": screenID},
+	})
+
+	h.writeLoungeStatus(w, r, sessionID)
+}
+
+// SetTarget chooses whether approved videos go to the primary TV or to every paired TV.
+func (h *YouTubeHandler) SetTarget(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "id")
+	claims := middleware.GetClaims(r.Context())
+
+	if err := requireAdmin(claims, sessionID); err != nil {
+		writeError(w, http.StatusForbidden, "admin access required")
+		return
+	}
+
+	var req models.SetLoungeTargetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	target := services.LoungeTarget(req.Target)
+	if target != services.LoungeTargetPrimary && target != services.LoungeTargetAll {
+		writeError(w, http.StatusBadRequest, "target must be 'primary' or 'all'")
+		return
+	}
+
+	_, before := h.loungeSettings(r, sessionID)
+	if err := h.loungeManager.SetTarget(r.Context(), sessionID, target); err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to update target", err)
+		return
+	}
+
+	auditChange(r, h.queries, auditEvent{
+		SessionID:  sessionID,
+		Action:     auditLoungeTarget,
+		TargetType: auditTargetSession,
+		TargetID:   sessionID,
+		Before:     models.SetLoungeTargetRequest{Target: string(before)},
+		After:      req,
+	})
+
+	h.writeLoungeStatus(w, r, sessionID)
 }
 
 // LoungeStatus returns the current YouTube TV connection status.
@@ -136,17 +341,74 @@ func (h *YouTubeHandler) LoungeStatus(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	writeJSON(w, http.StatusOK, h.buildLoungeStatusResponse(sessionID))
+	h.writeLoungeStatus(w, r, sessionID)
+}
+
+// loungeSettings returns the session's primary screen ID and target mode for
+// the audit log, or empty values if they cannot be loaded.
+func (h *YouTubeHandler) loungeSettings(r *http.Request, sessionID string) (primary string, target services.LoungeTarget) {
+	screens, target, err := h.loungeManager.Screens(r.Context(), sessionID)
+	if err != nil {
+		return "", ""
+	}
+	for _, screen := range screens {
+		if screen.IsPrimary {
+			primary = screen.ScreenID
+		}
+	}
+	return primary, target
 }
 
-func (h *YouTubeHandler) buildLoungeStatusResponse(sessionID string) models.LoungeStatusResponse {
-	status, screenName, errMsg := h.loungeManager.Status(sessionID)
-	resp := models.LoungeStatusResponse{Status: string(status)}
-	if screenName != "" {
-		resp.ScreenName = &screenName
+// writeLoungeStatus writes the session's screen list along with a summary status.
+// The summary reflects the best state of any screen (connected > connecting > error),
+// and the name and error of the primary screen.
+func (h *YouTubeHandler) writeLoungeStatus(w http.ResponseWriter, r *http.Request, sessionID string) {
+	screens, target, err := h.loungeManager.Screens(r.Context(), sessionID)
+	if err != nil {
+		writeErrorWithCause(r.Context(), w, http.StatusInternalServerError, "failed to load TV status", err)
+		return
 	}
-	if errMsg != "" {
-		resp.Error = &errMsg
+
+	resp := models.LoungeStatusResponse{
+		Status:  string(services.LoungeStatusDisconnected),
+		Target:  string(target),
+		Screens: make([]models.LoungeScreenResponse, len(screens)),
+	}
+	for i, screen := range screens {
+		resp.Screens[i] = models.LoungeScreenResponse{
+			ScreenID:   screen.ScreenID,
+			ScreenName: screen.ScreenName,
+			Status:     string(screen.Status),
+			IsPrimary:  screen.IsPrimary,
+		}
+		if screen.Error != "" {
+			errMsg := screen.Error
+			resp.Screens[i].Error = &errMsg
+		}
+		if loungeStatusRank(screen.Status) > loungeStatusRank(services.LoungeStatus(resp.Status)) {
+			resp.Status = string(screen.Status)
+		}
+		if screen.IsPrimary {
+			if screen.ScreenName != "" {
+				resp.ScreenName = &resp.Screens[i].ScreenName
+			}
+			resp.Error = resp.Screens[i].Error
+		}
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// loungeStatusRank orders statuses so the summary shows the most useful one.
+func loungeStatusRank(status services.LoungeStatus) int {
+	switch status {
+	case services.LoungeStatusConnected:
+		return 3
+	case services.LoungeStatusConnecting:
+		return 2
+	case services.LoungeStatusError:
+		return 1
+	default:
+		return 0
 	}
-	return resp
 }
@@ -62,6 +62,30 @@ func AuthMiddleware(authService *services.AuthService) func(http.Handler) http.H
 	}
 }
 
+// OptionalAuthMiddleware adds claims to the request context when a valid
+// bearer token is present, and otherwise passes the request through unchanged.
+// Used by public routes that offer extra behavior to session members.
+func OptionalAuthMiddleware(authService *services.AuthService) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			claims, err := authService.ValidateToken(token)
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), ClaimsKey, claims)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
 // AdminOnlyMiddleware restricts access to admin users only.
 // Must be used after AuthMiddleware. Returns 403 for non-admin users.
 func AdminOnlyMiddleware(next http.Handler) http.Handler {
@@ -1,7 +1,11 @@
 package middleware
 
 import (
+	"context"
+	"log/slog"
+	"math"
 	"net/http"
+	"strconv"
 	"sync"
 	"time"
 
@@ -9,45 +13,70 @@ import (
 	"golang.org/x/time/rate"
 )
 
-// visitor tracks rate limiting state for a single IP address.
+// LimitResult is a Limiter's decision for one request.
+type LimitResult struct {
+	Allowed bool
+	// Limit is the number of requests allowed per minute.
+	Limit int
+	// Remaining is how many more requests are allowed right now.
+	Remaining int
+	// Reset is how long until the full budget is available again.
+	Reset time.Duration
+	// RetryAfter is how long until the next request is allowed, when
+	// Allowed is false.
+	RetryAfter time.Duration
+}
+
+// Limiter takes requests from per-client budgets. Budgets refill steadily,
+// up to a minute's worth of requests.
+type Limiter interface {
+	Allow(ctx context.Context, key string) (LimitResult, error)
+}
+
+// KeyFunc identifies the client a request counts against.
+type KeyFunc func(*http.Request) string
+
+// visitor tracks rate limiting state for a single client key.
 type visitor struct {
 	limiter  *rate.Limiter
 	lastSeen time.Time
 }
 
-// RateLimiter implements per-IP rate limiting using a token bucket algorithm.
+// MemoryLimiter implements Limiter with per-client token buckets held in
+// process memory. Each instance of the server keeps its own budgets.
 // Old visitors are automatically cleaned up after 3 minutes of inactivity.
-type RateLimiter struct {
+type MemoryLimiter struct {
 	visitors map[string]*visitor
 	mu       sync.RWMutex
 	rate     rate.Limit
 	burst    int
 }
 
-// NewRateLimiter creates a rate limiter with the specified requests per minute.
-// Starts a background goroutine to clean up inactive visitors.
-func NewRateLimiter(requestsPerMinute int) *RateLimiter {
-	rl := &RateLimiter{
+// NewMemoryLimiter creates an in-memory Limiter allowing the specified
+// requests per minute. Starts a background goroutine to clean up inactive
+// visitors, which stops when ctx is cancelled.
+func NewMemoryLimiter(ctx context.Context, requestsPerMinute int) *MemoryLimiter {
+	ml := &MemoryLimiter{
 		visitors: make(map[string]*visitor),
 		rate:     rate.Limit(float64(requestsPerMinute) / 60.0),
 		burst:    requestsPerMinute,
 	}
 
 	// Clean up old visitors periodically
-	go rl.cleanupVisitors()
+	go ml.cleanupVisitors(ctx)
 
-	return rl
+	return ml
 }
 
-// getVisitor returns the rate limiter for an IP, creating one if needed.
-func (rl *RateLimiter) getVisitor(ip string) *rate.Limiter {
-	rl.mu.Lock()
-	defer rl.mu.Unlock()
+// getVisitor returns the rate limiter for a client key, creating one if needed.
+func (ml *MemoryLimiter) getVisitor(key string) *rate.Limiter {
+	ml.mu.Lock()
+	defer ml.mu.Unlock()
 
-	v, exists := rl.visitors[ip]
+	v, exists := ml.visitors[key]
 	if !exists {
-		limiter := rate.NewLimiter(rl.rate, rl.burst)
-		rl.visitors[ip] = &visitor{limiter: limiter, lastSeen: time.Now()}
+		limiter := rate.NewLimiter(ml.rate, ml.burst)
+		ml.visitors[key] = &visitor{limiter: limiter, lastSeen: time.Now()}
 		return limiter
 	}
 
@@ -55,34 +84,133 @@ func (rl *RateLimiter) getVisitor(ip string) *rate.Limiter {
 	return v.limiter
 }
 
-// cleanupVisitors removes visitors that haven't been seen in 3 minutes.
-func (rl *RateLimiter) cleanupVisitors() {
+// cleanupVisitors removes visitors that haven't been seen in 3 minutes,
+// checking every minute until ctx is cancelled.
+func (ml *MemoryLimiter) cleanupVisitors(ctx context.Context) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
 	for {
-		time.Sleep(time.Minute)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
 
-		rl.mu.Lock()
-		for ip, v := range rl.visitors {
+		ml.mu.Lock()
+		for key, v := range ml.visitors {
 			if time.Since(v.lastSeen) > 3*time.Minute {
-				delete(rl.visitors, ip)
+				delete(ml.visitors, key)
 			}
 		}
-		rl.mu.Unlock()
+		ml.mu.Unlock()
+	}
+}
+
+// Allow takes a token from the client's bucket.
+func (ml *MemoryLimiter) Allow(_ context.Context, key string) (LimitResult, error) {
+	limiter := ml.getVisitor(key)
+	now := time.Now()
+	allowed := limiter.AllowN(now, 1)
+	tokens := limiter.TokensAt(now)
+
+	result := LimitResult{
+		Allowed:   allowed,
+		Limit:     ml.burst,
+		Remaining: max(int(tokens), 0),
+		Reset:     ml.refillTime(float64(ml.burst) - tokens),
+	}
+	if !allowed {
+		result.RetryAfter = ml.refillTime(1 - tokens)
+	}
+	return result, nil
+}
+
+// refillTime returns how long the bucket takes to gain the given tokens.
+func (ml *MemoryLimiter) refillTime(tokens float64) time.Duration {
+	if tokens <= 0 || ml.rate <= 0 {
+		return 0
+	}
+	return time.Duration(tokens / float64(ml.rate) * float64(time.Second))
+}
+
+// RateLimiter is HTTP middleware that limits each client to a Limiter's
+// budget. Clients are identified by IP by default, or by session identity.
+type RateLimiter struct {
+	limiter Limiter
+	keyFunc KeyFunc
+}
+
+// NewRateLimiter creates a per-IP rate limiter with the specified requests per minute,
+// tracked in memory until ctx is cancelled.
+func NewRateLimiter(ctx context.Context, requestsPerMinute int) *RateLimiter {
+	return NewKeyedRateLimiter(NewMemoryLimiter(ctx, requestsPerMinute), ClientIPKey)
+}
+
+// NewIdentityRateLimiter creates a rate limiter keyed by the caller's session
+// identity rather than IP, so guests sharing venue Wi-Fi do not share a budget.
+// Must be used after AuthMiddleware; unauthenticated requests fall back to IP.
+func NewIdentityRateLimiter(ctx context.Context, requestsPerMinute int) *RateLimiter {
+	return NewKeyedRateLimiter(NewMemoryLimiter(ctx, requestsPerMinute), IdentityKey)
+}
+
+// NewKeyedRateLimiter creates a rate limiter that counts requests against
+// the given limiter under the key chosen by keyFunc.
+func NewKeyedRateLimiter(limiter Limiter, keyFunc KeyFunc) *RateLimiter {
+	return &RateLimiter{limiter: limiter, keyFunc: keyFunc}
+}
+
+// ClientIPKey uses the X-Real-IP header set by RealIPMiddleware, falling back to RemoteAddr.
+func ClientIPKey(r *http.Request) string {
+	if ip := r.Header.Get("X-Real-IP"); ip != "" {
+		return ip
 	}
+	return r.RemoteAddr
+}
+
+// IdentityKey identifies a caller by session, role and identity. Callers
+// without an identity name are further distinguished by IP.
+func IdentityKey(r *http.Request) string {
+	claims := GetClaims(r.Context())
+	if claims == nil {
+		return "ip:" + ClientIPKey(r)
+	}
+	key := "session:" + claims.SessionID + ":" + string(claims.Role)
+	if claims.Identity != "" {
+		return key + ":" + claims.Identity
+	}
+	return key + "@" + ClientIPKey(r)
+}
+
+// SessionKey identifies a caller by session, so everyone in a session shares
+// one budget. Unauthenticated requests fall back to IP.
+func SessionKey(r *http.Request) string {
+	claims := GetClaims(r.Context())
+	if claims == nil {
+		return "ip:" + ClientIPKey(r)
+	}
+	return "session:" + claims.SessionID
 }
 
 // Middleware returns the HTTP middleware that enforces rate limiting.
-// Returns 429 Too Many Requests when the limit is exceeded.
+// Every response carries RateLimit-Limit, RateLimit-Remaining and
+// RateLimit-Reset headers; requests over the limit get 429 Too Many Requests
+// with Retry-After. If the limiter fails, requests are let through.
 // Note: Should be placed after RealIPMiddleware in the chain to use the correct client IP.
 func (rl *RateLimiter) Middleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Use X-Real-IP header set by RealIPMiddleware, fall back to RemoteAddr
-		ip := r.Header.Get("X-Real-IP")
-		if ip == "" {
-			ip = r.RemoteAddr
+		result, err := rl.limiter.Allow(r.Context(), rl.keyFunc(r))
+		if err != nil {
+			slog.ErrorContext(r.Context(), "rate limiter unavailable", slog.String("error", err.Error()))
+			next.ServeHTTP(w, r)
+			return
 		}
 
-		limiter := rl.getVisitor(ip)
-		if !limiter.Allow() {
+		h := w.Header()
+		h.Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+		h.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		h.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+		if !result.Allowed {
+			h.Set("Retry-After", strconv.Itoa(max(ceilSeconds(result.RetryAfter), 1)))
 			logging.LogSecurityEvent(r.Context(), logging.SecurityEventRateLimited, "rate limit exceeded")
 			http.Error(w, `{"error":"rate limit exceeded"}`, http.StatusTooManyRequests)
 			return
@@ -91,3 +219,8 @@ func (rl *RateLimiter) Middleware(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r)
 	})
 }
+
+// ceilSeconds rounds a duration up to whole seconds.
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
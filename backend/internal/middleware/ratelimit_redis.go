@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// gcraScript applies the generic cell rate algorithm, the equivalent of a
+// token bucket that stores a single timestamp: the theoretical arrival time
+// (TAT) at which the bucket would be full again. Times are in microseconds
+// from the Redis server's clock, so instances with skewed clocks agree.
+//
+// KEYS[1] is the client key; ARGV[1] the burst and ARGV[2] the interval
+// between requests. Returns {allowed, remaining, reset, retry after}.
+var gcraScript = redis.NewScript(`
+local burst = tonumber(ARGV[1])
+local interval = tonumber(ARGV[2])
+local t = redis.call("TIME")
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+
+local tat = tonumber(redis.call("GET", KEYS[1]) or now)
+if tat < now then
+	tat = now
+end
+local new_tat = tat + interval
+local allow_at = new_tat - burst * interval
+if allow_at > now then
+	return {0, 0, tat - now, allow_at - now}
+end
+
+redis.call("SET", KEYS[1], new_tat, "PX", math.ceil((new_tat - now) / 1000))
+return {1, math.floor((now - allow_at) / interval), new_tat - now, 0}
+`)
+
+// RedisLimiter implements Limiter with budgets stored in Redis, so every
+// instance of the server shares them and they survive restarts.
+type RedisLimiter struct {
+	client   *redis.Client
+	prefix   string
+	limit    int
+	interval time.Duration
+}
+
+// NewRedisLimiter creates a Limiter allowing the specified requests per
+// minute, stored under keys starting with "songify:ratelimit:<name>:".
+// Limiters sharing a name share budgets.
+func NewRedisLimiter(client *redis.Client, name string, requestsPerMinute int) *RedisLimiter {
+	return &RedisLimiter{
+		client:   client,
+		prefix:   "songify:ratelimit:" + name + ":",
+		limit:    requestsPerMinute,
+		interval: time.Minute / time.Duration(max(requestsPerMinute, 1)),
+	}
+}
+
+// Allow takes a request from the client's budget.
+func (rl *RedisLimiter) Allow(ctx context.Context, key string) (LimitResult, error) {
+	if rl.limit <= 0 {
+		return LimitResult{Limit: rl.limit, RetryAfter: time.Minute}, nil
+	}
+
+	res, err := gcraScript.Run(ctx, rl.client, []string{rl.prefix + key}, rl.limit, rl.interval.Microseconds()).Int64Slice()
+	if err != nil {
+		return LimitResult{}, fmt.Errorf("failed to check rate limit: %w", err)
+	}
+	if len(res) != 4 {
+		return LimitResult{}, fmt.Errorf("unexpected rate limit reply %v", res)
+	}
+
+	return LimitResult{
+		Allowed:    res[0] == 1,
+		Limit:      rl.limit,
+		Remaining:  int(res[1]),
+		Reset:      time.Duration(res[2]) * time.Microsecond,
+		RetryAfter: time.Duration(res[3]) * time.Microsecond,
+	}, nil
+}
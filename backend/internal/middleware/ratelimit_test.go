@@ -0,0 +1,128 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/songify/backend/internal/services"
+)
+
+func TestIdentityRateLimiterKeysByIdentity(t *testing.T) {
+	rl := NewIdentityRateLimiter(t.Context(), 2)
+	handler := rl.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	request := func(identity string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/sessions/s1/search?q=x", nil)
+		req.Header.Set("X-Real-IP", "203.0.113.7") // everyone shares the venue's IP
+		claims := &services.Claims{SessionID: "s1", Role: services.RoleFriend, Identity: identity}
+		req = req.WithContext(context.WithValue(req.Context(), ClaimsKey, claims))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	for i := 0; i < 2; i++ {
+		if code := request("alice"); code != http.StatusOK {
+			t.Fatalf("alice request %d: status %d, want 200", i, code)
+		}
+	}
+	if code := request("alice"); code != http.StatusTooManyRequests {
+		t.Errorf("alice over limit: status %d, want 429", code)
+	}
+	if code := request("bob"); code != http.StatusOK {
+		t.Errorf("bob on the same IP: status %d, want 200", code)
+	}
+}
+
+func TestRateLimitHeaders(t *testing.T) {
+	handler := NewRateLimiter(t.Context(), 2).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	request := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/spotify/search?q=x", nil)
+		req.Header.Set("X-Real-IP", "203.0.113.7")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := request()
+	if got := rec.Header().Get("RateLimit-Limit"); got != "2" {
+		t.Errorf("RateLimit-Limit = %q, want 2", got)
+	}
+	if got := rec.Header().Get("RateLimit-Remaining"); got != "1" {
+		t.Errorf("RateLimit-Remaining = %q, want 1", got)
+	}
+	if got := rec.Header().Get("RateLimit-Reset"); got != "30" {
+		t.Errorf("RateLimit-Reset = %q, want 30", got)
+	}
+
+	request()
+	rec = request()
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("over limit: status %d, want 429", rec.Code)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "30" {
+		t.Errorf("Retry-After = %q, want 30", got)
+	}
+	if got := rec.Header().Get("RateLimit-Remaining"); got != "0" {
+		t.Errorf("RateLimit-Remaining = %q, want 0", got)
+	}
+}
+
+func TestRedisLimiterSharedAcrossInstances(t *testing.T) {
+	server := miniredis.RunT(t)
+	newClient := func() *redis.Client {
+		client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+		t.Cleanup(func() { client.Close() })
+		return client
+	}
+	// Two replicas with their own clients share one budget
+	replicas := []*RateLimiter{
+		NewKeyedRateLimiter(NewRedisLimiter(newClient(), "search", 3), ClientIPKey),
+		NewKeyedRateLimiter(NewRedisLimiter(newClient(), "search", 3), ClientIPKey),
+	}
+	request := func(replica int, ip string) *httptest.ResponseRecorder {
+		handler := replicas[replica].Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		req := httptest.NewRequest(http.MethodGet, "/api/spotify/search?q=x", nil)
+		req.Header.Set("X-Real-IP", ip)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for i := 0; i < 3; i++ {
+		rec := request(i%2, "203.0.113.7")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("request %d: status %d, want 200", i, rec.Code)
+		}
+		if got, want := rec.Header().Get("RateLimit-Remaining"), strconv.Itoa(2-i); got != want {
+			t.Errorf("request %d: RateLimit-Remaining = %q, want %s", i, got, want)
+		}
+	}
+	rec := request(1, "203.0.113.7")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("over limit on the other replica: status %d, want 429", rec.Code)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "20" {
+		t.Errorf("Retry-After = %q, want 20", got)
+	}
+	if rec := request(0, "198.51.100.1"); rec.Code != http.StatusOK {
+		t.Errorf("another client: status %d, want 200", rec.Code)
+	}
+
+	// Requests are let through while the store is down
+	server.Close()
+	if rec := request(0, "203.0.113.7"); rec.Code != http.StatusOK {
+		t.Errorf("store unavailable: status %d, want 200", rec.Code)
+	}
+}
@@ -2,7 +2,10 @@
 // These structs are serialized to/from JSON for client communication.
 package models
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // VerifyAdminRequest is sent to verify the admin portal password before
 // allowing session creation. The password is hashed client-side.
@@ -82,10 +85,18 @@ type SessionResponse struct {
 	ProhibitedPatterns  []ProhibitedPatternResponse `json:"prohibitedPatterns,omitempty"`
 	CreatedAt           time.Time                   `json:"createdAt"`
 	IsAdmin             bool                        `json:"isAdmin"`
+	// DuplicateWindowMinutes is how long an approved song blocks re-requests; nil means the whole session
+	DuplicateWindowMinutes *int64 `json:"duplicateWindowMinutes,omitempty"`
+	// FallbackPlaylist is played when the TV runs out of songs (admin only)
+	FallbackPlaylist *FallbackPlaylist `json:"fallbackPlaylist,omitempty"`
+	// TrackConstraints limits requests by genre, release year and popularity
+	TrackConstraints *TrackConstraints `json:"trackConstraints,omitempty"`
 }
 
 // SubmitSongRequestRequest contains the track metadata for a song request.
-// Fields come from Spotify or YouTube search results.
+// Fields come from Spotify or YouTube search results. MusicService names the
+// service the track came from when it differs from the session's; the track
+// is then converted to its best match on the session's service.
 type SubmitSongRequestRequest struct {
 	ExternalTrackID string `json:"externalTrackId"`
 	TrackName       string `json:"trackName"`
@@ -94,6 +105,13 @@ type SubmitSongRequestRequest struct {
 	AlbumArtURL     string `json:"albumArtUrl,omitempty"`
 	DurationMS      int64  `json:"durationMs"`
 	ExternalURI     string `json:"externalUri"`
+	MusicService    string `json:"musicService,omitempty"`
+	Note            string `json:"note,omitempty"` // Optional message or dedication, max 140 characters
+}
+
+// ResolveLinkRequest contains a pasted share link or URI for a song.
+type ResolveLinkRequest struct {
+	URL string `json:"url"`
 }
 
 // SongRequestResponse represents a song request with its current status.
@@ -112,6 +130,79 @@ type SongRequestResponse struct {
 	ProcessedAt     *time.Time `json:"processedAt,omitempty"`
 	RejectionReason *string    `json:"rejectionReason,omitempty"`
 	RequesterName   *string    `json:"requesterName,omitempty"`
+	Note            *string    `json:"note,omitempty"`        // Omitted for guests when hidden by an admin
+	NoteHidden      bool       `json:"noteHidden,omitempty"`  // Set for admins when the note is hidden from guests
+	ProcessedBy     *string    `json:"processedBy,omitempty"` // "admin", or "auto:<rule name>" for auto-moderation
+	// DeliveryStatus is "pending", "delivered" or "failed" for songs sent to
+	// a playback target such as a TV; DeliveryError is the last failure
+	DeliveryStatus *string `json:"deliveryStatus,omitempty"`
+	DeliveryError  *string `json:"deliveryError,omitempty"`
+	// Match is set on submission when the song was converted from another service
+	Match *TrackMatchResponse `json:"match,omitempty"`
+}
+
+// TrackMatchResponse describes how a track from another service was matched
+// to the session's service. Method is "isrc" or "metadata"; Confidence is 0-1.
+type TrackMatchResponse struct {
+	MusicService    string  `json:"musicService"`
+	ExternalTrackID string  `json:"externalTrackId"`
+	Confidence      float64 `json:"confidence"`
+	Method          string  `json:"method"`
+}
+
+// ApproveSongRequestRequest optionally hides the request's note from guests
+// while approving the song. The body may be omitted.
+type ApproveSongRequestRequest struct {
+	HideNote bool `json:"hideNote,omitempty"`
+}
+
+// UpdateNoteVisibilityRequest hides or shows a request's note to guests.
+type UpdateNoteVisibilityRequest struct {
+	Hidden bool `json:"hidden"`
+}
+
+// BatchModerationRequest selects pending requests to approve or reject at once,
+// by ID (processed in the given order), by filter (processed oldest first), or
+// by both. Reason only applies to rejections.
+type BatchModerationRequest struct {
+	IDs    []int64      `json:"ids,omitempty"`
+	Filter *BatchFilter `json:"filter,omitempty"`
+	Reason string       `json:"reason,omitempty"`
+}
+
+// BatchFilter narrows a batch to pending requests matching every set field.
+type BatchFilter struct {
+	RequesterName    *string `json:"requesterName,omitempty"`
+	OlderThanMinutes *int    `json:"olderThanMinutes,omitempty"`
+}
+
+// BatchModerationResponse reports the outcome for each selected request.
+type BatchModerationResponse struct {
+	Results   []BatchItemResult `json:"results"`
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+}
+
+// BatchItemResult is the outcome of one request in a batch. Request is set on
+// success; Error explains a failure.
+type BatchItemResult struct {
+	ID      int64                `json:"id"`
+	OK      bool                 `json:"ok"`
+	Error   string               `json:"error,omitempty"`
+	Request *SongRequestResponse `json:"request,omitempty"`
+}
+
+// VoteRequest sets the caller's vote on a pending request: 1 (up), -1 (down)
+// or 0 to take the vote back.
+type VoteRequest struct {
+	Value int64 `json:"value"`
+}
+
+// VoteResponse reports a request's score after a vote. Request reflects any
+// auto-moderation rule the new score triggered.
+type VoteResponse struct {
+	Score   int64               `json:"score"`
+	Request SongRequestResponse `json:"request"`
 }
 
 // RejectSongRequestRequest optionally includes a reason for rejection.
@@ -125,21 +216,61 @@ type UpdatePlaylistRequest struct {
 	SpotifyPlaylistName string `json:"spotifyPlaylistName"`
 }
 
-// SpotifySearchResponse wraps the track results from a Spotify search.
+// SpotifySearchResponse wraps one page of track results from a Spotify search.
+// Hidden lists results excluded because they break a session rule.
 type SpotifySearchResponse struct {
-	Tracks []SpotifyTrackResponse `json:"tracks"`
+	Tracks     []SpotifyTrackResponse `json:"tracks"`
+	Hidden     []SpotifyTrackResponse `json:"hidden,omitempty"`
+	Total      int                    `json:"total"`
+	Offset     int                    `json:"offset"`
+	NextOffset *int                   `json:"nextOffset,omitempty"`
 }
 
 // SpotifyTrackResponse contains track metadata from Spotify's API,
 // formatted for the frontend to display and submit as a request.
 type SpotifyTrackResponse struct {
-	ID          string   `json:"id"`
-	Name        string   `json:"name"`
-	URI         string   `json:"uri"`
-	DurationMS  int      `json:"durationMs"`
-	AlbumName   string   `json:"albumName"`
-	AlbumArtURL string   `json:"albumArtUrl,omitempty"`
-	Artists     []string `json:"artists"`
+	ID          string         `json:"id"`
+	Name        string         `json:"name"`
+	URI         string         `json:"uri"`
+	DurationMS  int            `json:"durationMs"`
+	AlbumName   string         `json:"albumName"`
+	AlbumArtURL string         `json:"albumArtUrl,omitempty"`
+	Artists     []string       `json:"artists"`
+	Violation   *RuleViolation `json:"violation,omitempty"`
+}
+
+// SessionSearchResponse is one page of search results from the session's music service.
+// Spotify sessions page with NextOffset; YouTube sessions with NextPageToken.
+type SessionSearchResponse struct {
+	MusicService  string                 `json:"musicService"`
+	Results       []SearchResultResponse `json:"results"`
+	NextOffset    *int                   `json:"nextOffset,omitempty"`
+	NextPageToken *string                `json:"nextPageToken,omitempty"`
+}
+
+// SearchResultResponse is a search result in the same shape as SubmitSongRequestRequest,
+// annotated with whether it is already requested or would be blocked by session rules.
+type SearchResultResponse struct {
+	ExternalTrackID  string         `json:"externalTrackId"`
+	TrackName        string         `json:"trackName"`
+	ArtistNames      string         `json:"artistNames"`
+	AlbumName        string         `json:"albumName"`
+	AlbumArtURL      string         `json:"albumArtUrl,omitempty"`
+	DurationMS       int64          `json:"durationMs"`
+	ExternalURI      string         `json:"externalUri"`
+	AlreadyRequested bool           `json:"alreadyRequested"`
+	RequestStatus    *string        `json:"requestStatus,omitempty"` // pending/approved when already requested
+	Violation        *RuleViolation `json:"violation,omitempty"`
+	// Match is set when the result was converted from another service
+	Match *TrackMatchResponse `json:"match,omitempty"`
+}
+
+// RuleViolation describes the session rule a song would break if requested.
+// Rule is one of: "durationLimit", "prohibitedArtist", "prohibitedTitle", "prohibitedNote".
+type RuleViolation struct {
+	Rule    string `json:"rule"`
+	Pattern string `json:"pattern,omitempty"`
+	Message string `json:"message"`
 }
 
 // UpdateDurationLimitRequest sets or clears the maximum song duration.
@@ -148,6 +279,46 @@ type UpdateDurationLimitRequest struct {
 	SongDurationLimitMs *int64 `json:"songDurationLimitMs"` // nil to clear
 }
 
+// UpdateDuplicateWindowRequest sets or clears how long an approved song counts
+// as a duplicate. A nil value makes approved songs duplicates for the whole session.
+type UpdateDuplicateWindowRequest struct {
+	DuplicateWindowMinutes *int64 `json:"duplicateWindowMinutes"` // nil to clear
+}
+
+// UpdateFallbackPlaylistRequest sets or clears the playlist the auto-DJ plays
+// from when the TV runs out of songs. MusicService defaults to the session's.
+type UpdateFallbackPlaylistRequest struct {
+	MusicService string  `json:"musicService,omitempty"`
+	PlaylistID   *string `json:"playlistId"` // nil to clear
+}
+
+// FallbackPlaylist identifies a session's fallback playlist.
+type FallbackPlaylist struct {
+	MusicService string `json:"musicService"`
+	PlaylistID   string `json:"playlistId"`
+}
+
+// TrackConstraints limits requests using music metadata from Spotify. Unset
+// fields are ignored; an empty value removes every constraint. A song passes
+// Genres when any of its artists' genres contains one of them as whole words
+// ("pop" matches "synth-pop" but not "popcorn"), and fails ExcludeGenres the
+// same way. Years and popularity (0-100) are inclusive.
+type TrackConstraints struct {
+	Genres         []string `json:"genres,omitempty"`
+	ExcludeGenres  []string `json:"excludeGenres,omitempty"`
+	MinReleaseYear *int     `json:"minReleaseYear,omitempty"`
+	MaxReleaseYear *int     `json:"maxReleaseYear,omitempty"`
+	MinPopularity  *int     `json:"minPopularity,omitempty"`
+	MaxPopularity  *int     `json:"maxPopularity,omitempty"`
+}
+
+// DuplicateRequestResponse is returned with 409 Conflict when a song was
+// already requested, pointing to the existing request.
+type DuplicateRequestResponse struct {
+	Error           string              `json:"error"`
+	ExistingRequest SongRequestResponse `json:"existingRequest"`
+}
+
 // CreatePatternRequest adds a new prohibited pattern to block certain songs.
 type CreatePatternRequest struct {
 	PatternType string `json:"patternType"` // "artist" or "title"
@@ -161,31 +332,163 @@ type ProhibitedPatternResponse struct {
 	Pattern     string `json:"pattern"`
 }
 
-// YouTubeSearchResponse wraps the video results from a YouTube search.
+// AutoModerationRuleRequest creates or replaces an auto-moderation rule.
+// Action is "approve" or "reject"; Reason is shown on automatic rejections.
+// Rules are evaluated by ascending Position and the first match wins.
+type AutoModerationRuleRequest struct {
+	Name       string                   `json:"name"`
+	Action     string                   `json:"action"`
+	Reason     string                   `json:"reason,omitempty"`
+	Conditions AutoModerationConditions `json:"conditions"`
+	Position   int64                    `json:"position"`
+	Enabled    *bool                    `json:"enabled,omitempty"` // Defaults to true
+}
+
+// AutoModerationConditions must all hold for a rule to fire; unset fields are
+// ignored, so a rule without conditions matches every request. After and
+// Before are "HH:MM" in TimeZone (IANA name, default UTC) and the window may
+// wrap past midnight. Queue length counts pending requests before the new one.
+// Vote score is the sum of guest votes. Rules with a score condition are
+// skipped when a request is submitted and evaluated whenever its score changes.
+type AutoModerationConditions struct {
+	RequesterNames []string `json:"requesterNames,omitempty"`
+	Artists        []string `json:"artists,omitempty"`        // Any artist contains one of these
+	ExcludeArtists []string `json:"excludeArtists,omitempty"` // No artist contains any of these
+	MinDurationMS  *int64   `json:"minDurationMs,omitempty"`
+	MaxDurationMS  *int64   `json:"maxDurationMs,omitempty"`
+	After          string   `json:"after,omitempty"`
+	Before         string   `json:"before,omitempty"`
+	TimeZone       string   `json:"timeZone,omitempty"`
+	MinQueueLength *int64   `json:"minQueueLength,omitempty"`
+	MaxQueueLength *int64   `json:"maxQueueLength,omitempty"`
+	MinVoteScore   *int64   `json:"minVoteScore,omitempty"`
+	MaxVoteScore   *int64   `json:"maxVoteScore,omitempty"`
+}
+
+// AutoModerationRuleResponse represents a session's auto-moderation rule.
+type AutoModerationRuleResponse struct {
+	ID         int64                    `json:"id"`
+	Name       string                   `json:"name"`
+	Action     string                   `json:"action"`
+	Reason     *string                  `json:"reason,omitempty"`
+	Conditions AutoModerationConditions `json:"conditions"`
+	Position   int64                    `json:"position"`
+	Enabled    bool                     `json:"enabled"`
+}
+
+// SchedulePlayRequest schedules an approved request. Mode is "at", which plays
+// it at PlayAt, or "after_current", which plays it when the current track ends.
+type SchedulePlayRequest struct {
+	Mode   string     `json:"mode"`
+	PlayAt *time.Time `json:"playAt,omitempty"` // Required for mode "at"
+}
+
+// ScheduledPlayResponse represents a scheduled play and its outcome.
+type ScheduledPlayResponse struct {
+	ID        int64      `json:"id"`
+	RequestID int64      `json:"requestId"`
+	Mode      string     `json:"mode"`
+	PlayAt    *time.Time `json:"playAt,omitempty"`
+	Status    string     `json:"status"` // scheduled/fired/failed/cancelled
+	Error     *string    `json:"error,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+	FiredAt   *time.Time `json:"firedAt,omitempty"`
+}
+
+// YouTubeSearchResponse wraps one page of video results from a YouTube search.
+// Hidden lists results excluded because they break a session rule.
 type YouTubeSearchResponse struct {
-	Videos []YouTubeVideoResponse `json:"videos"`
+	Videos        []YouTubeVideoResponse `json:"videos"`
+	Hidden        []YouTubeVideoResponse `json:"hidden,omitempty"`
+	NextPageToken *string                `json:"nextPageToken,omitempty"`
+	PrevPageToken *string                `json:"prevPageToken,omitempty"`
 }
 
 // YouTubeVideoResponse contains video metadata from YouTube's API,
 // formatted for the frontend to display and submit as a request.
 type YouTubeVideoResponse struct {
-	ID           string `json:"id"`
-	Title        string `json:"title"`
-	ChannelTitle string `json:"channelTitle"`
-	ThumbnailURL string `json:"thumbnailUrl"`
-	DurationMS   int64  `json:"durationMs"`
+	ID           string         `json:"id"`
+	Title        string         `json:"title"`
+	ChannelTitle string         `json:"channelTitle"`
+	ThumbnailURL string         `json:"thumbnailUrl"`
+	DurationMS   int64          `json:"durationMs"`
+	Violation    *RuleViolation `json:"violation,omitempty"`
 }
 
 // PairLoungeRequest is sent by an admin to pair with a YouTube TV via Lounge API.
+// Name optionally labels the screen (e.g. "Bar", "Patio") instead of the TV's own name.
 type PairLoungeRequest struct {
-	PairingCode string `json:"pairingCode"`
+	PairingCode string  `json:"pairingCode"`
+	Name        *string `json:"name,omitempty"`
+}
+
+// SetLoungeTargetRequest chooses which paired screens receive queued videos.
+type SetLoungeTargetRequest struct {
+	Target string `json:"target"` // primary/all
 }
 
 // LoungeStatusResponse represents the current YouTube TV connection state.
+// Status, ScreenName and Error summarise the session; Screens lists every paired TV.
 type LoungeStatusResponse struct {
-	Status     string  `json:"status"`              // connected/disconnected/error/connecting
-	ScreenName *string `json:"screenName,omitempty"`
+	Status     string                 `json:"status"` // connected/disconnected/error/connecting
+	ScreenName *string                `json:"screenName,omitempty"`
+	Error      *string                `json:"error,omitempty"`
+	Target     string                 `json:"target"` // primary/all
+	Screens    []LoungeScreenResponse `json:"screens"`
+}
+
+// LoungeScreenResponse represents a single paired YouTube TV.
+type LoungeScreenResponse struct {
+	ScreenID   string  `json:"screenId"`
+	ScreenName string  `json:"screenName"`
+	Status     string  `json:"status"` // connected/disconnected/error/connecting
 	Error      *string `json:"error,omitempty"`
+	IsPrimary  bool    `json:"isPrimary"`
+}
+
+// MetricsResponse reports search cache effectiveness and YouTube quota usage.
+type MetricsResponse struct {
+	Cache        SearchCacheMetrics            `json:"cache"`
+	Providers    map[string]SearchCacheMetrics `json:"providers"`
+	YouTubeQuota QuotaMetrics                  `json:"youtubeQuota"`
+}
+
+// SearchCacheMetrics holds lookup counters for the whole cache or one provider.
+type SearchCacheMetrics struct {
+	Hits      int64   `json:"hits"`
+	Misses    int64   `json:"misses"`
+	StaleHits int64   `json:"staleHits"`
+	HitRate   float64 `json:"hitRate"`
+	Entries   *int    `json:"entries,omitempty"`
+}
+
+// QuotaMetrics reports YouTube Data API units spent today.
+type QuotaMetrics struct {
+	Used      int       `json:"used"`
+	Budget    int       `json:"budget"`
+	SoftLimit int       `json:"softLimit"`
+	ResetsAt  time.Time `json:"resetsAt"`
+}
+
+// AuditLogResponse is one page of a session's audit log, newest first.
+// NextBefore is passed as ?before= to fetch the next page and is nil on the last one.
+type AuditLogResponse struct {
+	Events     []AuditEventResponse `json:"events"`
+	NextBefore *int64               `json:"nextBefore,omitempty"`
+}
+
+// AuditEventResponse records who changed what. Before and After hold the
+// target's state as JSON and are omitted when there is none (e.g. on create).
+type AuditEventResponse struct {
+	ID         int64           `json:"id"`
+	Actor      string          `json:"actor"`
+	Role       string          `json:"role"` // admin/friend/system
+	Action     string          `json:"action"`
+	TargetType string          `json:"targetType"`
+	TargetID   string          `json:"targetId"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+	CreatedAt  time.Time       `json:"createdAt"`
 }
 
 // ErrorResponse is the standard error format returned by all endpoints.
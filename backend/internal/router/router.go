@@ -3,12 +3,17 @@
 package router
 
 import (
+	"context"
+	"database/sql"
+	"fmt"
 	"net/http"
+	"sync"
 
 	"github.com/getsentry/sentry-go"
 	sentryhttp "github.com/getsentry/sentry-go/http"
 	"github.com/go-chi/chi/v5"
 	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/redis/go-redis/v9"
 	"github.com/songify/backend/internal/broker"
 	"github.com/songify/backend/internal/config"
 	"github.com/songify/backend/internal/db"
@@ -17,13 +22,36 @@ import (
 	"github.com/songify/backend/internal/services"
 )
 
+// Router serves the API and owns the background workers and connections
+// behind it.
+type Router struct {
+	http.Handler
+	workers     sync.WaitGroup
+	lounge      *services.LoungeManager
+	closeLimits func() error
+}
+
 // New creates and configures the HTTP router with all routes and middleware.
 // The router is organized into:
 //   - Public routes: health check, config, admin verification
 //   - Session routes: create, join, rejoin (unauthenticated)
 //   - Protected session routes: requires JWT auth
 //   - Admin-only routes: settings, patterns, request moderation
-func New(cfg *config.Config, queries *db.Queries, eventBroker *broker.Broker) http.Handler {
+//
+// Background workers run until ctx is cancelled, which also ends open SSE
+// streams. It fails if the rate limiting configuration is invalid.
+func New(ctx context.Context, cfg *config.Config, sqlDB *sql.DB, queries db.Store, eventBroker broker.Broker) (*Router, error) {
+	rt := &Router{}
+	newLimiter, closeLimits, err := limiterFactory(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	rt.closeLimits = closeLimits
+	sessionSearchKey, err := rateLimitKey(cfg.SessionSearchRateLimitKey)
+	if err != nil {
+		return nil, err
+	}
+
 	r := chi.NewRouter()
 
 	// Global middleware
@@ -48,25 +76,54 @@ func New(cfg *config.Config, queries *db.Queries, eventBroker *broker.Broker) ht
 	// Services
 	authService := services.NewAuthService(cfg.JWTSecret, cfg.AdminTokenDuration, cfg.FriendTokenDuration)
 	friendKeyService := services.NewFriendKeyService(queries)
-	spotifyService := services.NewSpotifyService(cfg.SpotifyClientID, cfg.SpotifyClientSecret)
-	youtubeService := services.NewYouTubeService(cfg.YouTubeAPIKey)
+	searchCache := services.NewSearchCache(cfg.SearchCacheSize, cfg.SearchCacheTTL)
+	youtubeQuota := services.NewQuotaMeter(cfg.YouTubeDailyQuota, cfg.YouTubeDailyQuota*cfg.YouTubeQuotaSoftPercent/100)
+	spotifyService := services.NewSpotifyService(cfg.SpotifyClientID, cfg.SpotifyClientSecret, searchCache)
+	youtubeService := services.NewYouTubeService(cfg.YouTubeAPIKey, searchCache, youtubeQuota)
 
 	// Lounge manager (YouTube TV pairing, credentials persisted to DB)
 	loungeManager := services.NewLoungeManager(queries)
+	rt.lounge = loungeManager
+	rt.run(ctx, loungeManager.Run)
+
+	// Music providers a session can use; the first is the default
+	musicProviders := []services.MusicProvider{
+		services.NewSpotifyProvider(spotifyService),
+		services.NewYouTubeProvider(youtubeService, loungeManager),
+	}
+	if cfg.SubsonicURL != "" {
+		subsonicService := services.NewSubsonicService(cfg.SubsonicURL, cfg.SubsonicUsername, cfg.SubsonicPassword, searchCache)
+		musicProviders = append(musicProviders, services.NewSubsonicProvider(subsonicService, services.SubsonicQueueMode(cfg.SubsonicQueueMode)))
+	}
+	providers := services.NewProviderRegistry(musicProviders...)
+
+	// Scheduled plays fire in the background and notify the session's SSE
+	// clients; idle TVs are filled from their session's fallback playlist
+	autoDJ := services.NewAutoDJ(queries, providers, handlers.FallbackTrackFilter(queries, providers))
+	scheduler := services.NewScheduler(queries, providers, autoDJ, cfg.SchedulerInterval, eventBroker.Publish)
+	rt.run(ctx, scheduler.Run)
+
+	// Songs approved for a TV or playlist are delivered in the background,
+	// retrying if the target is unreachable
+	outbox := services.NewOutbox(queries, providers, cfg.OutboxInterval, eventBroker.Publish)
+	rt.run(ctx, outbox.Run)
 
 	// Handlers
 	adminHandler := handlers.NewAdminHandler(cfg)
 	configHandler := handlers.NewConfigHandler(cfg)
 	sentryTunnelHandler := handlers.NewSentryTunnelHandler(cfg)
-	sessionHandler := handlers.NewSessionHandler(queries, authService, friendKeyService, cfg)
-	requestHandler := handlers.NewRequestHandler(queries, eventBroker, loungeManager)
-	sseHandler := handlers.NewSSEHandler(eventBroker)
+	metricsHandler := handlers.NewMetricsHandler(searchCache, youtubeQuota)
+	sessionHandler := handlers.NewSessionHandler(queries, authService, friendKeyService, providers, cfg)
+	requestHandler := handlers.NewRequestHandler(sqlDB, queries, eventBroker, providers, cfg.UndoWindow)
+	sseHandler := handlers.NewSSEHandler(eventBroker, ctx.Done())
 	spotifyHandler := handlers.NewSpotifyHandler(spotifyService, queries)
 	youtubeHandler := handlers.NewYouTubeHandler(youtubeService, loungeManager, queries)
+	searchHandler := handlers.NewSearchHandler(providers, queries)
 
 	// Rate limiters
-	searchRateLimiter := middleware.NewRateLimiter(cfg.RateLimitPerMinute)
-	authRateLimiter := middleware.NewRateLimiter(cfg.AuthRateLimitPerMinute)
+	searchRateLimiter := middleware.NewKeyedRateLimiter(newLimiter("search", cfg.RateLimitPerMinute), middleware.ClientIPKey)
+	authRateLimiter := middleware.NewKeyedRateLimiter(newLimiter("auth", cfg.AuthRateLimitPerMinute), middleware.ClientIPKey)
+	sessionSearchRateLimiter := middleware.NewKeyedRateLimiter(newLimiter("session-search", cfg.SessionSearchRateLimitPerMinute), sessionSearchKey)
 
 	// Routes
 	r.Route("/api", func(r chi.Router) {
@@ -76,6 +133,12 @@ func New(cfg *config.Config, queries *db.Queries, eventBroker *broker.Broker) ht
 			w.Write([]byte(`{"status":"ok"}`))
 		})
 
+		// Search cache and YouTube quota metrics (any session admin)
+		r.With(
+			middleware.AuthMiddleware(authService),
+			middleware.AdminOnlyMiddleware,
+		).Get("/metrics", metricsHandler.Search)
+
 		// Public configuration (Spotify client ID, etc.)
 		r.Get("/config", configHandler.PublicConfig)
 
@@ -111,6 +174,15 @@ func New(cfg *config.Config, queries *db.Queries, eventBroker *broker.Broker) ht
 				r.Get("/", sessionHandler.Get)
 				r.Put("/spotify/playlist", spotifyHandler.UpdatePlaylist)
 
+				// Search the session's music service (rate limited per identity)
+				r.With(sessionSearchRateLimiter.Middleware).Get("/search", searchHandler.Search)
+
+				// Resolve a pasted share link into a song (rate limited per identity)
+				r.With(sessionSearchRateLimiter.Middleware).Post("/resolve-link", searchHandler.ResolveLink)
+
+				// Match a track from another music service (rate limited per identity)
+				r.With(sessionSearchRateLimiter.Middleware).Get("/match", searchHandler.Match)
+
 				// YouTube Lounge TV pairing (admin only)
 				r.Route("/youtube", func(r chi.Router) {
 					r.Use(middleware.AdminOnlyMiddleware)
@@ -118,12 +190,38 @@ func New(cfg *config.Config, queries *db.Queries, eventBroker *broker.Broker) ht
 					r.Delete("/pair", youtubeHandler.Disconnect)
 					r.Post("/reconnect", youtubeHandler.Reconnect)
 					r.Get("/status", youtubeHandler.LoungeStatus)
+					r.Put("/target", youtubeHandler.SetTarget)
+					r.Delete("/screens/{screenId}", youtubeHandler.DisconnectScreen)
+					r.Post("/screens/{screenId}/reconnect", youtubeHandler.ReconnectScreen)
+					r.Put("/screens/{screenId}/primary", youtubeHandler.SetPrimary)
 				})
 
 				// Admin-only settings routes
 				r.Route("/settings", func(r chi.Router) {
 					r.Use(middleware.AdminOnlyMiddleware)
 					r.Put("/duration-limit", sessionHandler.UpdateDurationLimit)
+					r.Put("/duplicate-window", sessionHandler.UpdateDuplicateWindow)
+					r.Put("/fallback-playlist", sessionHandler.UpdateFallbackPlaylist)
+					r.Put("/track-constraints", sessionHandler.UpdateTrackConstraints)
+				})
+
+				// Admin-only audit log
+				r.With(middleware.AdminOnlyMiddleware).Get("/audit", sessionHandler.ListAuditEvents)
+
+				// Admin-only auto-moderation rules
+				r.Route("/auto-moderation", func(r chi.Router) {
+					r.Use(middleware.AdminOnlyMiddleware)
+					r.Get("/", sessionHandler.GetAutoModerationRules)
+					r.Post("/", sessionHandler.CreateAutoModerationRule)
+					r.Put("/{ruleId}", sessionHandler.UpdateAutoModerationRule)
+					r.Delete("/{ruleId}", sessionHandler.DeleteAutoModerationRule)
+				})
+
+				// Admin-only scheduled plays
+				r.Route("/schedules", func(r chi.Router) {
+					r.Use(middleware.AdminOnlyMiddleware)
+					r.Get("/", requestHandler.ListSchedules)
+					r.Delete("/{scheduleId}", requestHandler.CancelSchedule)
 				})
 
 				// Admin-only patterns routes
@@ -142,23 +240,96 @@ func New(cfg *config.Config, queries *db.Queries, eventBroker *broker.Broker) ht
 					// Admin-only: archive all requests
 					r.With(middleware.AdminOnlyMiddleware).Delete("/", requestHandler.ArchiveAll)
 
-					// Admin-only actions
+					// Admin-only: approve or reject many requests at once
+					r.With(middleware.AdminOnlyMiddleware).Post("/batch/approve", requestHandler.BatchApprove)
+					r.With(middleware.AdminOnlyMiddleware).Post("/batch/reject", requestHandler.BatchReject)
+
 					r.Route("/{rid}", func(r chi.Router) {
-						r.Use(middleware.AdminOnlyMiddleware)
-						r.Put("/approve", requestHandler.Approve)
-						r.Put("/reject", requestHandler.Reject)
-						r.Put("/play-next", requestHandler.PlayNext)
+						// Requester: withdraw own pending request
+						r.Delete("/", requestHandler.Withdraw)
+
+						// Guests: vote on a pending request
+						r.Put("/vote", requestHandler.Vote)
+
+						// Admin-only actions
+						r.Group(func(r chi.Router) {
+							r.Use(middleware.AdminOnlyMiddleware)
+							r.Put("/approve", requestHandler.Approve)
+							r.Put("/reject", requestHandler.Reject)
+							r.Put("/play-next", requestHandler.PlayNext)
+							r.Put("/undo", requestHandler.Undo)
+							r.Post("/schedule", requestHandler.Schedule)
+							r.Put("/note", requestHandler.UpdateNoteVisibility)
+						})
 					})
 				})
 			})
 		})
 
-		// Spotify search (rate limited)
-		r.With(searchRateLimiter.Middleware).Get("/spotify/search", spotifyHandler.Search)
+		// Spotify search (rate limited; a session token enables excludeBlocked)
+		r.With(searchRateLimiter.Middleware, middleware.OptionalAuthMiddleware(authService)).Get("/spotify/search", spotifyHandler.Search)
 
-		// YouTube search (rate limited)
-		r.With(searchRateLimiter.Middleware).Get("/youtube/search", youtubeHandler.Search)
+		// YouTube search (rate limited; a session token enables excludeBlocked)
+		r.With(searchRateLimiter.Middleware, middleware.OptionalAuthMiddleware(authService)).Get("/youtube/search", youtubeHandler.Search)
 	})
 
-	return r
+	rt.Handler = r
+	return rt, nil
+}
+
+// run starts a background worker that Close waits for.
+func (rt *Router) run(ctx context.Context, worker func(context.Context)) {
+	rt.workers.Add(1)
+	go func() {
+		defer rt.workers.Done()
+		worker(ctx)
+	}()
+}
+
+// Close waits for the background workers to stop, which they do once the
+// context passed to New is cancelled, then disconnects from TVs and the rate
+// limit store. Call it after the HTTP server has shut down, as in-flight
+// requests may still need them.
+func (rt *Router) Close() error {
+	rt.workers.Wait()
+	rt.lounge.Close()
+	return rt.closeLimits()
+}
+
+// limiterFactory returns a constructor for the rate limit store selected by
+// RATE_LIMIT_STORE, and a function closing the store. Redis-backed limits are
+// shared by every instance; in-memory ones are cleaned up until ctx is
+// cancelled.
+func limiterFactory(ctx context.Context, cfg *config.Config) (func(name string, requestsPerMinute int) middleware.Limiter, func() error, error) {
+	switch cfg.RateLimitStore {
+	case "memory":
+		return func(_ string, requestsPerMinute int) middleware.Limiter {
+			return middleware.NewMemoryLimiter(ctx, requestsPerMinute)
+		}, func() error { return nil }, nil
+	case "redis":
+		opts, err := redis.ParseURL(cfg.RedisURL)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid REDIS_URL: %w", err)
+		}
+		client := redis.NewClient(opts)
+		return func(name string, requestsPerMinute int) middleware.Limiter {
+			return middleware.NewRedisLimiter(client, name, requestsPerMinute)
+		}, client.Close, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown rate limit store %q", cfg.RateLimitStore)
+	}
+}
+
+// rateLimitKey returns the key function named by a rate limit key setting.
+func rateLimitKey(name string) (middleware.KeyFunc, error) {
+	switch name {
+	case "ip":
+		return middleware.ClientIPKey, nil
+	case "identity":
+		return middleware.IdentityKey, nil
+	case "session":
+		return middleware.SessionKey, nil
+	default:
+		return nil, fmt.Errorf("unknown rate limit key %q", name)
+	}
 }
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/songify/backend/internal/db"
+)
+
+const (
+	// fallbackPlaylistTTL is how long a fallback playlist's tracks are reused
+	// before it is fetched again.
+	fallbackPlaylistTTL = 15 * time.Minute
+	// fallbackAttempts caps how many playlist entries are tried per pick, as
+	// matching across services spends search quota.
+	fallbackAttempts = 10
+	// FallbackIdleDelay is how long the player must stay idle before the
+	// auto-DJ steps in, so the gap between queued videos is not mistaken for
+	// an empty queue.
+	FallbackIdleDelay = 3 * time.Second
+	// fallbackRetryDelay is how long the auto-DJ waits for the player to
+	// report its pick before trying the next track.
+	fallbackRetryDelay = 30 * time.Second
+)
+
+// ErrNoFallbackTrack is returned when no entry of a fallback playlist can be
+// played in the session.
+var ErrNoFallbackTrack = errors.New("no playable track in fallback playlist")
+
+// TrackFilter reports whether a track may be played in a session, e.g.
+// under its duration limit and prohibited patterns.
+type TrackFilter func(ctx context.Context, session db.Session, track Track) (bool, error)
+
+// AutoDJ picks tracks from a session's fallback playlist when its queue runs
+// dry. Tracks from another service are matched to the session's service.
+type AutoDJ struct {
+	queries   db.Querier
+	providers *ProviderRegistry
+	allowed   TrackFilter
+	now       func() time.Time
+
+	mu        sync.Mutex
+	playlists map[string]fallbackPlaylist
+}
+
+// fallbackPlaylist is a cached copy of a playlist's tracks.
+type fallbackPlaylist struct {
+	tracks    []Track
+	fetchedAt time.Time
+}
+
+// NewAutoDJ creates an AutoDJ that only picks tracks allowed by the filter.
+func NewAutoDJ(queries db.Querier, providers *ProviderRegistry, allowed TrackFilter) *AutoDJ {
+	return &AutoDJ{
+		queries:   queries,
+		providers: providers,
+		allowed:   allowed,
+		now:       time.Now,
+		playlists: make(map[string]fallbackPlaylist),
+	}
+}
+
+// Next returns the next allowed track of the session's fallback playlist on
+// the session's music service and advances the playlist position. The
+// playlist wraps around when it reaches the end.
+func (d *AutoDJ) Next(ctx context.Context, session db.Session) (*Track, error) {
+	if !session.FallbackPlaylistID.Valid || !session.FallbackService.Valid {
+		return nil, errors.New("session has no fallback playlist")
+	}
+	tracks, err := d.playlist(ctx, session.FallbackService.String, session.FallbackPlaylistID.String)
+	if err != nil {
+		return nil, err
+	}
+	if len(tracks) == 0 {
+		return nil, ErrNoFallbackTrack
+	}
+	target, err := d.providers.Get(session.MusicService)
+	if err != nil {
+		return nil, err
+	}
+
+	start := int(session.FallbackPosition) % len(tracks)
+	attempts := min(len(tracks), fallbackAttempts)
+	for i := range attempts {
+		pos := (start + i) % len(tracks)
+		track := tracks[pos]
+		if session.FallbackService.String != session.MusicService {
+			match, err := MatchTrack(ctx, track, target)
+			if errors.Is(err, ErrNoMatch) {
+				continue
+			}
+			if err != nil {
+				d.advance(ctx, session.ID, pos, len(tracks))
+				return nil, fmt.Errorf("failed to match %q: %w", track.Name, err)
+			}
+			track = match.Track
+		}
+
+		ok, err := d.allowed(ctx, session, track)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		d.advance(ctx, session.ID, pos+1, len(tracks))
+		return &track, nil
+	}
+
+	d.advance(ctx, session.ID, start+attempts, len(tracks))
+	return nil, ErrNoFallbackTrack
+}
+
+// advance stores the next playlist position to try.
+func (d *AutoDJ) advance(ctx context.Context, sessionID string, pos, length int) {
+	err := d.queries.UpdateSessionFallbackPosition(ctx, db.UpdateSessionFallbackPositionParams{
+		FallbackPosition: int64(pos % length),
+		ID:               sessionID,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "autodj: failed to save playlist position",
+			slog.String("session_id", sessionID),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// playlist returns a fallback playlist's tracks, fetching them when the
+// cached copy is missing or stale.
+func (d *AutoDJ) playlist(ctx context.Context, service, playlistID string) ([]Track, error) {
+	key := service + ":" + playlistID
+	d.mu.Lock()
+	cached, ok := d.playlists[key]
+	d.mu.Unlock()
+	if ok && d.now().Sub(cached.fetchedAt) < fallbackPlaylistTTL {
+		return cached.tracks, nil
+	}
+
+	provider, err := d.providers.Get(service)
+	if err != nil {
+		return nil, err
+	}
+	source, ok := provider.(PlaylistSource)
+	if !ok {
+		return nil, fmt.Errorf("%s does not support fallback playlists", service)
+	}
+	tracks, err := source.PlaylistTracks(ctx, playlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	d.mu.Lock()
+	d.playlists[key] = fallbackPlaylist{tracks: tracks, fetchedAt: d.now()}
+	d.mu.Unlock()
+	return tracks, nil
+}
@@ -18,11 +18,11 @@ var wordlist = wordlists.English
 // FriendKeyService generates unique, human-readable keys for session sharing.
 // Keys follow the pattern "word-word-number" (e.g., "apple-river-42").
 type FriendKeyService struct {
-	queries *db.Queries
+	queries db.Querier
 }
 
 // NewFriendKeyService creates a FriendKeyService with its own random source.
-func NewFriendKeyService(queries *db.Queries) *FriendKeyService {
+func NewFriendKeyService(queries db.Querier) *FriendKeyService {
 	return &FriendKeyService{
 		queries: queries,
 	}
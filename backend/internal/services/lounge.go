@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
@@ -12,8 +13,10 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/songify/backend/internal/db"
 )
 
@@ -22,8 +25,19 @@ const (
 	loungeInactivityTimeout = 30 * time.Minute
 	loungeMaxRetries        = 3
 	loungeRetryBaseDelay    = 2 * time.Second
+	loungeCommandTimeout    = 15 * time.Second
+	loungeCommandQueueSize  = 32
 )
 
+// errLoungeSessionExpired is returned when the TV no longer recognises our SID
+// and the connection must be re-bound before further commands can be sent.
+var errLoungeSessionExpired = errors.New("lounge session expired")
+
+// errLoungeClosed is returned when a command is sent to a session whose
+// command loop has already stopped, or a screen is connected after the
+// manager has closed.
+var errLoungeClosed = errors.New("lounge session closed")
+
 // LoungeStatus represents the connection state of a Lounge session.
 type LoungeStatus string
 
@@ -34,18 +48,72 @@ const (
 	LoungeStatusError        LoungeStatus = "error"
 )
 
+// LoungeTarget selects which paired screens receive queued videos.
+type LoungeTarget string
+
+const (
+	LoungeTargetPrimary LoungeTarget = "primary" // Only the primary screen
+	LoungeTargetAll     LoungeTarget = "all"     // Every connected screen
+)
+
+// ErrLoungeScreenNotFound is returned when a screen is not paired with the session.
+var ErrLoungeScreenNotFound = errors.New("screen not paired with this session")
+
+// LoungeScreenStatus describes one paired screen and its connection state.
+type LoungeScreenStatus struct {
+	ScreenID   string
+	ScreenName string
+	Status     LoungeStatus
+	Error      string
+	IsPrimary  bool
+}
+
 // LoungeManager manages YouTube Lounge connections across sessions.
-// It maps sessionID -> loungeSession and is safe for concurrent use.
-// Credentials (screenID, loungeToken, screenName) are persisted to the database
-// so they survive backend restarts.
+// Each session may pair several screens; it maps sessionID -> loungeGroup and
+// is safe for concurrent use. Screen credentials (screenID, loungeToken,
+// screenName) are persisted to the database so they survive backend restarts.
+//
+// When several instances of the server share a database, each session's
+// connections are owned by the one instance holding its lease; the others
+// store commands for the owner to deliver. See lounge_lease.go.
 type LoungeManager struct {
-	mu       sync.Mutex
-	sessions map[string]*loungeSession
-	queries  *db.Queries
+	mu         sync.Mutex
+	leaseMu    sync.Mutex // Serializes claiming and renewing leases
+	sessions   map[string]*loungeGroup
+	queries    db.Querier
+	baseURL    string
+	instanceID string
+	ackTimeout time.Duration  // How long a forwarded command may wait to be picked up
+	closed     bool           // Set by Close; guarded by mu
+	loops      sync.WaitGroup // Running command and long-poll loops
+}
+
+// loungeGroup holds the live screen connections for one Songify session.
+// Guarded by LoungeManager.mu.
+type loungeGroup struct {
+	screens   map[string]*loungeSession // keyed by screenID
+	primary   string
+	target    LoungeTarget
+	leased    bool      // This instance holds the session's lease
+	claimedAt time.Time // When the lease was last claimed
 }
 
 // loungeSession holds per-connection state for a YouTube TV pairing.
+//
+// Commands are serialized through a single command loop goroutine so that
+// RID and ofs advance in the order the TV receives them. The long-poll loop
+// runs concurrently and only touches aid; all mutable fields are guarded by mu.
 type loungeSession struct {
+	baseURL    string
+	httpClient *http.Client
+	pollClient *http.Client
+
+	commands       chan loungeCommand
+	done           chan struct{}
+	cancel         context.CancelFunc
+	commandTimeout time.Duration // How long a command may wait to be sent
+
+	mu          sync.Mutex
 	screenID    string
 	loungeToken string
 	screenName  string
@@ -60,249 +128,616 @@ type loungeSession struct {
 	status       LoungeStatus
 	errorMsg     string
 	lastActivity time.Time
+	nowPlaying   NowPlaying
+}
 
-	cancel     context.CancelFunc
-	httpClient *http.Client
+// loungeCommand is a unit of work for the session's command loop.
+// An empty name requests a re-bind; staleSID lets the loop skip the re-bind
+// if another caller has already replaced that SID.
+type loungeCommand struct {
+	name     string
+	videoID  string
+	extra    map[string]string
+	staleSID string
+	result   chan error
+	state    *atomic.Int32 // commandQueued, commandRunning or commandAbandoned
 }
 
+// States of a queued command. The command loop and a caller that gives up
+// race to move a command out of commandQueued, so it is either run or
+// dropped, never both.
+const (
+	commandQueued int32 = iota
+	commandRunning
+	commandAbandoned
+)
+
 // NewLoungeManager creates a new LoungeManager.
-func NewLoungeManager(queries *db.Queries) *LoungeManager {
+func NewLoungeManager(queries db.Querier) *LoungeManager {
 	return &LoungeManager{
-		sessions: make(map[string]*loungeSession),
-		queries:  queries,
+		sessions:   make(map[string]*loungeGroup),
+		queries:    queries,
+		baseURL:    loungeBaseURL,
+		instanceID: uuid.NewString(),
+		ackTimeout: loungeCommandAckTimeout,
+	}
+}
+
+// newLoungeSession creates an unbound session talking to the given Lounge base URL.
+func newLoungeSession(baseURL string) *loungeSession {
+	return &loungeSession{
+		baseURL:        baseURL,
+		httpClient:     &http.Client{Timeout: 30 * time.Second},
+		pollClient:     &http.Client{Timeout: 3 * time.Minute},
+		commands:       make(chan loungeCommand, loungeCommandQueueSize),
+		done:           make(chan struct{}),
+		commandTimeout: loungeCommandTimeout,
+		status:         LoungeStatusConnecting,
+		lastActivity:   time.Now(),
 	}
 }
 
 // Pair validates a pairing code, binds to the TV, and starts a long-poll goroutine.
-func (m *LoungeManager) Pair(ctx context.Context, sessionID, pairingCode string) error {
+// The screen is added alongside any already paired; re-pairing a known screen
+// replaces its connection. The first screen paired becomes the primary.
+// If name is non-empty it overrides the name the TV reports. Pairing takes
+// over the session's connections if another instance owns them.
+func (m *LoungeManager) Pair(ctx context.Context, sessionID, pairingCode, name string) (LoungeScreenStatus, error) {
 	slog.Info("lounge: pairing started", slog.String("session_id", sessionID))
 
+	ls := newLoungeSession(m.baseURL)
+
+	// Step 1: Get screen info from pairing code
+	if err := ls.getScreen(ctx, pairingCode); err != nil {
+		slog.Error("lounge: getScreen failed", slog.String("session_id", sessionID), slog.String("error", err.Error()))
+		return LoungeScreenStatus{}, fmt.Errorf("pairing failed: %w", err)
+	}
+	if name = strings.TrimSpace(name); name != "" {
+		ls.screenName = name
+	}
+	screenID, loungeToken, screenName := ls.credentials()
+	slog.Info("lounge: getScreen succeeded", slog.String("session_id", sessionID), slog.String("screen_name", screenName), slog.String("screen_id", screenID))
+
+	// Persist credentials to DB so they survive restarts
+	screen, err := m.queries.UpsertLoungeScreen(ctx, db.UpsertLoungeScreenParams{
+		SessionID:   sessionID,
+		ScreenID:    screenID,
+		LoungeToken: loungeToken,
+		ScreenName:  sql.NullString{String: screenName, Valid: screenName != ""},
+	})
+	if err != nil {
+		return LoungeScreenStatus{}, fmt.Errorf("failed to persist screen: %w", err)
+	}
+
+	tookOver, err := m.claim(ctx, sessionID)
+	if err != nil {
+		return LoungeScreenStatus{}, err
+	}
+	group, err := m.group(ctx, sessionID)
+	if err != nil {
+		return LoungeScreenStatus{}, err
+	}
+
 	m.mu.Lock()
-	// Disconnect existing session if any
-	if existing, ok := m.sessions[sessionID]; ok {
-		slog.Info("lounge: disconnecting existing session before re-pair", slog.String("session_id", sessionID))
+	if existing, ok := group.screens[screenID]; ok {
+		slog.Info("lounge: disconnecting existing screen before re-pair", slog.String("session_id", sessionID), slog.String("screen_id", screenID))
 		existing.disconnect()
-		delete(m.sessions, sessionID)
+	}
+	group.screens[screenID] = ls
+	needsPrimary := group.primary == ""
+	if needsPrimary {
+		group.primary = screenID
+	}
+	m.mu.Unlock()
+
+	if needsPrimary && !screen.IsPrimary {
+		if err := m.queries.SetPrimaryLoungeScreen(ctx, db.SetPrimaryLoungeScreenParams{ScreenID: screenID, SessionID: sessionID}); err != nil {
+			slog.Error("lounge: failed to persist primary screen", slog.String("session_id", sessionID), slog.String("error", err.Error()))
+		}
 	}
 
-	ls := &loungeSession{
-		status:       LoungeStatusConnecting,
-		lastActivity: time.Now(),
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+	// Step 2: Bind and start the command and long-poll loops
+	if err := m.start(ctx, sessionID, ls); err != nil {
+		return m.screenStatus(sessionID, screenID), fmt.Errorf("bind failed: %w", err)
 	}
-	m.sessions[sessionID] = ls
+	if tookOver {
+		m.adopt(ctx, sessionID, screenID)
+	}
+
+	slog.Info("lounge: paired successfully", slog.String("session_id", sessionID), slog.String("screen_name", screenName))
+	return m.screenStatus(sessionID, screenID), nil
+}
+
+// group returns the in-memory group for a session, loading the primary screen
+// and target mode from the database the first time it is needed.
+func (m *LoungeManager) group(ctx context.Context, sessionID string) (*loungeGroup, error) {
+	m.mu.Lock()
+	g, ok := m.sessions[sessionID]
 	m.mu.Unlock()
+	if ok {
+		return g, nil
+	}
 
-	// Step 1: Get screen info from pairing code
-	if err := ls.getScreen(ctx, pairingCode); err != nil {
-		slog.Error("lounge: getScreen failed", slog.String("session_id", sessionID), slog.String("error", err.Error()))
-		m.mu.Lock()
-		ls.status = LoungeStatusError
-		ls.errorMsg = err.Error()
-		m.mu.Unlock()
-		return fmt.Errorf("pairing failed: %w", err)
+	session, err := m.queries.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load session: %w", err)
+	}
+	screens, err := m.queries.GetLoungeScreensBySessionID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load screens: %w", err)
 	}
-	slog.Info("lounge: getScreen succeeded", slog.String("session_id", sessionID), slog.String("screen_name", ls.screenName), slog.String("screen_id", ls.screenID))
 
-	// Persist credentials to DB so they survive restarts
-	if err := m.queries.SaveLoungeCredentials(ctx, db.SaveLoungeCredentialsParams{
-		LoungeScreenID:   sql.NullString{String: ls.screenID, Valid: true},
-		LoungeToken:      sql.NullString{String: ls.loungeToken, Valid: true},
-		LoungeScreenName: sql.NullString{String: ls.screenName, Valid: ls.screenName != ""},
-		ID:               sessionID,
-	}); err != nil {
-		slog.Error("lounge: failed to persist credentials", slog.String("session_id", sessionID), slog.String("error", err.Error()))
+	loaded := &loungeGroup{
+		screens: make(map[string]*loungeSession),
+		target:  LoungeTarget(session.LoungeTarget),
+	}
+	for _, s := range screens {
+		if s.IsPrimary {
+			loaded.primary = s.ScreenID
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if g, ok := m.sessions[sessionID]; ok {
+		return g, nil
 	}
+	m.sessions[sessionID] = loaded
+	return loaded, nil
+}
 
-	// Step 2: Bind to get SID/gsessionid
+// start binds an already-credentialed session and launches its command and
+// long-poll goroutines. The goroutines outlive ctx and stop on disconnect.
+func (m *LoungeManager) start(ctx context.Context, sessionID string, ls *loungeSession) error {
 	if err := ls.bind(ctx); err != nil {
 		slog.Error("lounge: bind failed", slog.String("session_id", sessionID), slog.String("error", err.Error()))
-		m.mu.Lock()
-		ls.status = LoungeStatusError
-		ls.errorMsg = err.Error()
-		m.mu.Unlock()
-		return fmt.Errorf("bind failed: %w", err)
+		ls.setError(err.Error())
+		return err
 	}
-	slog.Info("lounge: bind succeeded", slog.String("session_id", sessionID), slog.String("sid", ls.sid), slog.String("gsessionid", ls.gsessionID))
-
+	loopCtx, cancel := context.WithCancel(context.Background())
 	m.mu.Lock()
+	ls.mu.Lock()
+	if m.closed || ls.status == LoungeStatusDisconnected {
+		// Disconnected by another caller, or shutting down, while we were binding
+		ls.mu.Unlock()
+		m.mu.Unlock()
+		cancel()
+		return errLoungeClosed
+	}
 	ls.status = LoungeStatusConnected
 	ls.lastActivity = time.Now()
-	m.mu.Unlock()
-
-	// Step 3: Start long-poll goroutine
-	pollCtx, cancel := context.WithCancel(context.Background())
 	ls.cancel = cancel
-	go m.longPollLoop(pollCtx, sessionID, ls)
-
-	slog.Info("lounge: paired successfully", slog.String("session_id", sessionID), slog.String("screen_name", ls.screenName))
+	sid, gsessionID := ls.sid, ls.gsessionID
+	m.loops.Add(2)
+	ls.mu.Unlock()
+	m.mu.Unlock()
+	slog.Info("lounge: bind succeeded", slog.String("session_id", sessionID), slog.String("sid", sid), slog.String("gsessionid", gsessionID))
+
+	go func() {
+		defer m.loops.Done()
+		ls.commandLoop(loopCtx, sessionID)
+	}()
+	go func() {
+		defer m.loops.Done()
+		m.longPollLoop(loopCtx, sessionID, ls)
+	}()
 	return nil
 }
 
-// Disconnect explicitly disconnects from a TV and removes all session state,
-// including persisted credentials.
+// Disconnect explicitly disconnects every screen from the session and removes
+// all state, including persisted credentials.
 func (m *LoungeManager) Disconnect(sessionID string) {
 	m.mu.Lock()
-	if ls, ok := m.sessions[sessionID]; ok {
-		slog.Info("lounge: disconnecting", slog.String("session_id", sessionID), slog.String("screen_name", ls.screenName))
-		ls.disconnect()
+	if g, ok := m.sessions[sessionID]; ok {
+		for screenID, ls := range g.screens {
+			slog.Info("lounge: disconnecting", slog.String("session_id", sessionID), slog.String("screen_id", screenID))
+			ls.disconnect()
+		}
 		delete(m.sessions, sessionID)
 	} else {
 		slog.Info("lounge: disconnect called but no active session", slog.String("session_id", sessionID))
 	}
 	m.mu.Unlock()
 
-	// Clear persisted credentials
-	if err := m.queries.ClearLoungeCredentials(context.Background(), sessionID); err != nil {
-		slog.Error("lounge: failed to clear persisted credentials", slog.String("session_id", sessionID), slog.String("error", err.Error()))
+	// Clear persisted credentials. Another instance owning the connections
+	// notices at its next lease renewal and disconnects too.
+	if err := m.queries.DeleteLoungeScreensBySessionID(context.Background(), sessionID); err != nil {
+		slog.Error("lounge: failed to clear persisted screens", slog.String("session_id", sessionID), slog.String("error", err.Error()))
+	}
+	if err := m.queries.DeleteLoungeLease(context.Background(), sessionID); err != nil {
+		slog.Error("lounge: failed to release lease", slog.String("session_id", sessionID), slog.String("error", err.Error()))
 	}
 }
 
-// Reconnect re-binds to the TV using existing credentials (screenID/loungeToken)
-// and restarts the long-poll goroutine. Does not require a new pairing code.
-// If credentials aren't in memory, loads them from the database.
-func (m *LoungeManager) Reconnect(ctx context.Context, sessionID string) error {
+// Close disconnects every screen and releases this instance's leases, so
+// another instance can take over straight away. Persisted credentials are
+// kept for reconnecting later. It waits for the command and long-poll loops
+// to stop; no screen can be connected afterwards.
+func (m *LoungeManager) Close() {
+	m.leaseMu.Lock()
+	defer m.leaseMu.Unlock()
+
 	m.mu.Lock()
-	ls, ok := m.sessions[sessionID]
-	if !ok || ls.screenID == "" || ls.loungeToken == "" {
-		// Try loading from DB
-		m.mu.Unlock()
-		creds, err := m.queries.GetLoungeCredentials(ctx, sessionID)
-		if err != nil || !creds.LoungeScreenID.Valid || !creds.LoungeToken.Valid {
-			return fmt.Errorf("no existing credentials to reconnect with")
+	m.closed = true
+	var leased []string
+	for sessionID, g := range m.sessions {
+		for _, ls := range g.screens {
+			ls.disconnect()
 		}
+		if g.leased {
+			leased = append(leased, sessionID)
+		}
+	}
+	m.sessions = make(map[string]*loungeGroup)
+	m.mu.Unlock()
 
-		m.mu.Lock()
-		ls = &loungeSession{
-			screenID:     creds.LoungeScreenID.String,
-			loungeToken:  creds.LoungeToken.String,
-			screenName:   creds.LoungeScreenName.String,
-			lastActivity: time.Now(),
-			httpClient:   &http.Client{Timeout: 30 * time.Second},
+	for _, sessionID := range leased {
+		if err := m.queries.ReleaseLoungeLease(context.Background(), db.ReleaseLoungeLeaseParams{SessionID: sessionID, Owner: m.instanceID}); err != nil {
+			slog.Error("lounge: failed to release lease", slog.String("session_id", sessionID), slog.String("error", err.Error()))
 		}
-		m.sessions[sessionID] = ls
-		slog.Info("lounge: loaded credentials from DB", slog.String("session_id", sessionID), slog.String("screen_name", ls.screenName))
 	}
+	m.loops.Wait()
+}
 
-	// Cancel any existing poll goroutine
-	if ls.cancel != nil {
-		ls.cancel()
+// DisconnectScreen unpairs a single screen. If it was the primary, the
+// longest-paired remaining screen is promoted.
+func (m *LoungeManager) DisconnectScreen(ctx context.Context, sessionID, screenID string) error {
+	result, err := m.queries.DeleteLoungeScreen(ctx, db.DeleteLoungeScreenParams{SessionID: sessionID, ScreenID: screenID})
+	if err != nil {
+		return fmt.Errorf("failed to delete screen: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return ErrLoungeScreenNotFound
 	}
 
-	// Reset session state for re-bind
-	ls.status = LoungeStatusConnecting
-	ls.errorMsg = ""
-	ls.sid = ""
-	ls.gsessionID = ""
-	ls.aid = 0
-	ls.ofs = 0
-	ls.lastActivity = time.Now()
+	m.mu.Lock()
+	g, ok := m.sessions[sessionID]
+	wasPrimary := false
+	if ok {
+		if ls, ok := g.screens[screenID]; ok {
+			slog.Info("lounge: disconnecting screen", slog.String("session_id", sessionID), slog.String("screen_id", screenID))
+			ls.disconnect()
+			delete(g.screens, screenID)
+		}
+		wasPrimary = g.primary == screenID
+		if wasPrimary {
+			g.primary = ""
+		}
+	}
 	m.mu.Unlock()
 
-	slog.Info("lounge: reconnecting", slog.String("session_id", sessionID), slog.String("screen_name", ls.screenName))
+	if !ok || !wasPrimary {
+		return nil
+	}
 
-	if err := ls.bind(ctx); err != nil {
-		m.mu.Lock()
-		ls.status = LoungeStatusError
-		ls.errorMsg = err.Error()
-		m.mu.Unlock()
-		slog.Error("lounge: reconnect bind failed", slog.String("session_id", sessionID), slog.String("error", err.Error()))
-		return fmt.Errorf("reconnect failed: %w", err)
+	remaining, err := m.queries.GetLoungeScreensBySessionID(ctx, sessionID)
+	if err != nil || len(remaining) == 0 {
+		return nil
 	}
-	slog.Info("lounge: reconnect bind succeeded", slog.String("session_id", sessionID), slog.String("sid", ls.sid), slog.String("gsessionid", ls.gsessionID))
+	return m.SetPrimary(ctx, sessionID, remaining[0].ScreenID)
+}
 
+// SetPrimary marks screenID as the screen that receives videos in primary mode.
+func (m *LoungeManager) SetPrimary(ctx context.Context, sessionID, screenID string) error {
+	if _, err := m.queries.GetLoungeScreen(ctx, db.GetLoungeScreenParams{SessionID: sessionID, ScreenID: screenID}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrLoungeScreenNotFound
+		}
+		return fmt.Errorf("failed to load screen: %w", err)
+	}
+
+	if err := m.queries.SetPrimaryLoungeScreen(ctx, db.SetPrimaryLoungeScreenParams{ScreenID: screenID, SessionID: sessionID}); err != nil {
+		return fmt.Errorf("failed to set primary screen: %w", err)
+	}
+
+	g, err := m.group(ctx, sessionID)
+	if err != nil {
+		return err
+	}
 	m.mu.Lock()
-	ls.status = LoungeStatusConnected
-	ls.lastActivity = time.Now()
+	g.primary = screenID
 	m.mu.Unlock()
 
-	pollCtx, cancel := context.WithCancel(context.Background())
-	ls.cancel = cancel
-	go m.longPollLoop(pollCtx, sessionID, ls)
-
-	slog.Info("lounge: reconnected successfully", slog.String("session_id", sessionID))
+	slog.Info("lounge: primary screen changed", slog.String("session_id", sessionID), slog.String("screen_id", screenID))
 	return nil
 }
 
-// Status returns the current connection status for a session.
-// If no in-memory session exists but DB has saved credentials, returns "error"
-// status so the frontend can offer a reconnect option.
-func (m *LoungeManager) Status(sessionID string) (LoungeStatus, string, string) {
+// SetTarget chooses whether videos go to the primary screen or to every screen.
+func (m *LoungeManager) SetTarget(ctx context.Context, sessionID string, target LoungeTarget) error {
+	if err := m.queries.UpdateSessionLoungeTarget(ctx, db.UpdateSessionLoungeTargetParams{
+		LoungeTarget: string(target),
+		ID:           sessionID,
+	}); err != nil {
+		return fmt.Errorf("failed to update target: %w", err)
+	}
+
+	g, err := m.group(ctx, sessionID)
+	if err != nil {
+		return err
+	}
 	m.mu.Lock()
-	ls, ok := m.sessions[sessionID]
+	g.target = target
 	m.mu.Unlock()
+	return nil
+}
 
-	if ok {
-		return ls.status, ls.screenName, ls.errorMsg
+// Reconnect re-binds every paired screen using its stored credentials and
+// restarts the long-poll goroutines. Does not require new pairing codes.
+// Returns an error only if no screen could be reconnected.
+func (m *LoungeManager) Reconnect(ctx context.Context, sessionID string) error {
+	screens, err := m.queries.GetLoungeScreensBySessionID(ctx, sessionID)
+	if err != nil || len(screens) == 0 {
+		return fmt.Errorf("no existing credentials to reconnect with")
+	}
+	if _, err := m.claim(ctx, sessionID); err != nil {
+		return err
 	}
 
-	// Check DB for persisted credentials
-	creds, err := m.queries.GetLoungeCredentials(context.Background(), sessionID)
-	if err == nil && creds.LoungeScreenID.Valid && creds.LoungeToken.Valid {
-		return LoungeStatusError, creds.LoungeScreenName.String, "TV connection lost (server restarted)"
+	var errs []error
+	for _, s := range screens {
+		if err := m.reconnect(ctx, sessionID, s); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", s.ScreenName.String, err))
+		}
 	}
+	if len(errs) == len(screens) {
+		return errors.Join(errs...)
+	}
+	return nil
+}
 
-	return LoungeStatusDisconnected, "", ""
+// ReconnectScreen re-binds a single paired screen.
+func (m *LoungeManager) ReconnectScreen(ctx context.Context, sessionID, screenID string) error {
+	screen, err := m.queries.GetLoungeScreen(ctx, db.GetLoungeScreenParams{SessionID: sessionID, ScreenID: screenID})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrLoungeScreenNotFound
+		}
+		return fmt.Errorf("failed to load screen: %w", err)
+	}
+	tookOver, err := m.claim(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	if err := m.reconnect(ctx, sessionID, screen); err != nil {
+		return err
+	}
+	if tookOver {
+		m.adopt(ctx, sessionID, screenID)
+	}
+	return nil
 }
 
-// SendAddVideo sends an addVideo command to append a video to the TV queue.
-// Returns nil if no Lounge is connected for this session.
-func (m *LoungeManager) SendAddVideo(sessionID, videoID string) error {
+// reconnect replaces a screen's connection with a freshly bound one.
+func (m *LoungeManager) reconnect(ctx context.Context, sessionID string, screen db.LoungeScreen) error {
+	g, err := m.group(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+
+	// Replace the old connection wholesale so its goroutines cannot observe
+	// the fresh protocol state.
+	ls := newLoungeSession(m.baseURL)
+	ls.screenID = screen.ScreenID
+	ls.loungeToken = screen.LoungeToken
+	ls.screenName = screen.ScreenName.String
+
 	m.mu.Lock()
-	ls, ok := m.sessions[sessionID]
-	if !ok || ls.status != LoungeStatusConnected {
-		m.mu.Unlock()
-		slog.Info("lounge: SendAddVideo skipped, not connected", slog.String("session_id", sessionID), slog.String("video_id", videoID))
-		return nil
+	if existing, ok := g.screens[screen.ScreenID]; ok {
+		existing.disconnect()
 	}
+	g.screens[screen.ScreenID] = ls
 	m.mu.Unlock()
 
-	slog.Info("lounge: sending addVideo", slog.String("session_id", sessionID), slog.String("video_id", videoID))
-	if err := ls.sendCommand("addVideo", videoID, nil); err != nil {
-		slog.Error("lounge: addVideo failed", slog.String("session_id", sessionID), slog.String("video_id", videoID), slog.String("error", err.Error()))
-		return err
+	slog.Info("lounge: reconnecting", slog.String("session_id", sessionID), slog.String("screen_id", screen.ScreenID), slog.String("screen_name", ls.screenName))
+
+	if err := m.start(ctx, sessionID, ls); err != nil {
+		return fmt.Errorf("reconnect failed: %w", err)
 	}
-	slog.Info("lounge: addVideo succeeded", slog.String("session_id", sessionID), slog.String("video_id", videoID))
+
+	slog.Info("lounge: reconnected successfully", slog.String("session_id", sessionID), slog.String("screen_id", screen.ScreenID))
 	return nil
 }
 
-// SendSetVideo sends a setVideo command to play a video immediately on the TV.
-// Returns nil if no Lounge is connected for this session.
-func (m *LoungeManager) SendSetVideo(sessionID, videoID string) error {
-	m.mu.Lock()
-	ls, ok := m.sessions[sessionID]
-	if !ok || ls.status != LoungeStatusConnected {
+// Screens returns every screen paired with the session and the current target
+// mode. Screens with stored credentials but no live connection (for example
+// after a restart) are reported as "error" so the frontend can offer a reconnect.
+// Screens owned by another instance are reported as connected.
+func (m *LoungeManager) Screens(ctx context.Context, sessionID string) ([]LoungeScreenStatus, LoungeTarget, error) {
+	rows, err := m.queries.GetLoungeScreensBySessionID(ctx, sessionID)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load screens: %w", err)
+	}
+	g, err := m.group(ctx, sessionID)
+	if err != nil {
+		return nil, "", err
+	}
+	_, remote := m.remoteOwner(ctx, sessionID)
+
+	statuses := make([]LoungeScreenStatus, len(rows))
+	for i, row := range rows {
+		statuses[i] = LoungeScreenStatus{
+			ScreenID:   row.ScreenID,
+			ScreenName: row.ScreenName.String,
+			Status:     LoungeStatusError,
+			Error:      "TV connection lost (server restarted)",
+			IsPrimary:  row.IsPrimary,
+		}
+		if remote {
+			statuses[i].Status = LoungeStatusConnected
+			statuses[i].Error = ""
+		}
+		m.mu.Lock()
+		ls, ok := g.screens[row.ScreenID]
 		m.mu.Unlock()
-		slog.Info("lounge: SendSetVideo skipped, not connected", slog.String("session_id", sessionID), slog.String("video_id", videoID))
-		return nil
+		if ok {
+			ls.mu.Lock()
+			statuses[i].Status = ls.status
+			statuses[i].Error = ls.errorMsg
+			ls.mu.Unlock()
+		}
 	}
+
+	m.mu.Lock()
+	target := g.target
 	m.mu.Unlock()
+	return statuses, target, nil
+}
 
-	slog.Info("lounge: sending setVideo", slog.String("session_id", sessionID), slog.String("video_id", videoID))
-	if err := ls.sendCommand("setVideo", videoID, map[string]string{
-		"currentTime": "0",
-	}); err != nil {
-		slog.Error("lounge: setVideo failed", slog.String("session_id", sessionID), slog.String("video_id", videoID), slog.String("error", err.Error()))
-		return err
+// screenStatus returns the live status of a single in-memory screen.
+func (m *LoungeManager) screenStatus(sessionID, screenID string) LoungeScreenStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	status := LoungeScreenStatus{ScreenID: screenID, Status: LoungeStatusDisconnected}
+	g, ok := m.sessions[sessionID]
+	if !ok {
+		return status
+	}
+	status.IsPrimary = g.primary == screenID
+	if ls, ok := g.screens[screenID]; ok {
+		ls.mu.Lock()
+		status.ScreenName = ls.screenName
+		status.Status = ls.status
+		status.Error = ls.errorMsg
+		ls.mu.Unlock()
+	}
+	return status
+}
+
+// Enqueue implements PlaybackTarget by sending an addVideo command.
+func (m *LoungeManager) Enqueue(ctx context.Context, sessionID, videoID string) error {
+	return m.send(ctx, sessionID, newLoungeCommand("addVideo", videoID))
+}
+
+// PlayNow implements PlaybackTarget by sending a setVideo command.
+func (m *LoungeManager) PlayNow(ctx context.Context, sessionID, videoID string) error {
+	return m.send(ctx, sessionID, newLoungeCommand("setVideo", videoID))
+}
+
+// Remove implements PlaybackTarget by sending a removeVideo command.
+func (m *LoungeManager) Remove(ctx context.Context, sessionID, videoID string) error {
+	return m.send(ctx, sessionID, newLoungeCommand("removeVideo", videoID))
+}
+
+// newLoungeCommand builds a video command. setVideo starts from the beginning.
+func newLoungeCommand(name, videoID string) loungeCommand {
+	cmd := loungeCommand{name: name, videoID: videoID}
+	if name == "setVideo" {
+		cmd.extra = map[string]string{"currentTime": "0"}
+	}
+	return cmd
+}
+
+// send delivers a command to the targeted screens, or hands it to the
+// instance that owns the session's connections and waits for the outcome.
+func (m *LoungeManager) send(ctx context.Context, sessionID string, cmd loungeCommand) error {
+	targets := m.targets(sessionID)
+	if len(targets) == 0 {
+		return m.forward(ctx, sessionID, cmd)
+	}
+	return m.broadcast(sessionID, targets, cmd)
+}
+
+// broadcast delivers a command to the given screens in parallel. With a single
+// target its error is returned as-is; when broadcasting, an error is returned
+// only if every screen failed.
+func (m *LoungeManager) broadcast(sessionID string, targets []*loungeSession, cmd loungeCommand) error {
+	errs := make([]error, len(targets))
+	var wg sync.WaitGroup
+	for i, ls := range targets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			screenID, _, _ := ls.credentials()
+			slog.Info("lounge: sending "+cmd.name, slog.String("session_id", sessionID), slog.String("screen_id", screenID), slog.String("video_id", cmd.videoID))
+			if err := ls.enqueue(cmd); err != nil {
+				slog.Error("lounge: "+cmd.name+" failed", slog.String("session_id", sessionID), slog.String("screen_id", screenID), slog.String("video_id", cmd.videoID), slog.String("error", err.Error()))
+				errs[i] = err
+				return
+			}
+			slog.Info("lounge: "+cmd.name+" succeeded", slog.String("session_id", sessionID), slog.String("screen_id", screenID), slog.String("video_id", cmd.videoID))
+		}()
+	}
+	wg.Wait()
+
+	failed := 0
+	for _, err := range errs {
+		if err != nil {
+			failed++
+		}
+	}
+	if failed == len(targets) {
+		return errors.Join(errs...)
 	}
-	slog.Info("lounge: setVideo succeeded", slog.String("session_id", sessionID), slog.String("video_id", videoID))
 	return nil
 }
 
-// IsConnected returns whether a Lounge session is currently connected.
+// IsConnected returns whether any screen that would receive videos is
+// connected, here or on the instance that owns the session's connections.
 func (m *LoungeManager) IsConnected(sessionID string) bool {
+	if len(m.targets(sessionID)) > 0 {
+		return true
+	}
+	_, remote := m.remoteOwner(context.Background(), sessionID)
+	return remote
+}
+
+// targets returns the connected screens that should receive commands under
+// the session's target mode.
+func (m *LoungeManager) targets(sessionID string) []*loungeSession {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	ls, ok := m.sessions[sessionID]
-	return ok && ls.status == LoungeStatusConnected
+	g, ok := m.sessions[sessionID]
+	if !ok {
+		return nil
+	}
+
+	var targets []*loungeSession
+	for screenID, ls := range g.screens {
+		if g.target != LoungeTargetAll && screenID != g.primary {
+			continue
+		}
+		ls.mu.Lock()
+		connected := ls.status == LoungeStatusConnected
+		ls.mu.Unlock()
+		if connected {
+			targets = append(targets, ls)
+		}
+	}
+	return targets
+}
+
+// credentials returns the pairing credentials for the session.
+func (ls *loungeSession) credentials() (screenID, loungeToken, screenName string) {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	return ls.screenID, ls.loungeToken, ls.screenName
+}
+
+// setError marks the session as errored with the given message.
+func (ls *loungeSession) setError(msg string) {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	ls.status = LoungeStatusError
+	ls.errorMsg = msg
+}
+
+// bindParams returns the query parameters shared by every bind request.
+// Must be called with ls.mu held.
+func (ls *loungeSession) bindParams() url.Values {
+	return url.Values{
+		"device":        {"REMOTE_CONTROL"},
+		"name":          {"Songify"},
+		"id":            {ls.screenID},
+		"loungeIdToken": {ls.loungeToken},
+		"VER":           {"8"},
+	}
 }
 
 // getScreen calls the pairing endpoint to get screenID, loungeToken, and screenName.
 func (ls *loungeSession) getScreen(ctx context.Context, pairingCode string) error {
-	pairingURL := fmt.Sprintf("%s/pairing/get_screen?pairing_code=%s", loungeBaseURL, url.QueryEscape(pairingCode))
+	pairingURL := fmt.Sprintf("%s/pairing/get_screen?pairing_code=%s", ls.baseURL, url.QueryEscape(pairingCode))
 
 	req, err := http.NewRequestWithContext(ctx, "GET", pairingURL, nil)
 	if err != nil {
@@ -329,26 +764,24 @@ func (ls *loungeSession) getScreen(ctx context.Context, pairingCode string) erro
 		return err
 	}
 
+	ls.mu.Lock()
 	ls.screenID = screenID
 	ls.loungeToken = loungeToken
 	ls.screenName = screenName
+	ls.mu.Unlock()
 	return nil
 }
 
 // bind performs the initial bind request to get SID and gsessionid.
+// A successful bind starts a fresh protocol session, so aid and ofs are reset.
 func (ls *loungeSession) bind(ctx context.Context) error {
+	ls.mu.Lock()
 	ls.rid++
+	params := ls.bindParams()
+	params.Set("RID", strconv.Itoa(ls.rid))
+	ls.mu.Unlock()
 
-	params := url.Values{
-		"device":        {"REMOTE_CONTROL"},
-		"name":          {"Songify"},
-		"id":            {ls.screenID},
-		"loungeIdToken": {ls.loungeToken},
-		"VER":           {"8"},
-		"RID":           {strconv.Itoa(ls.rid)},
-	}
-
-	bindURL := fmt.Sprintf("%s/bc/bind?%s", loungeBaseURL, params.Encode())
+	bindURL := fmt.Sprintf("%s/bc/bind?%s", ls.baseURL, params.Encode())
 
 	req, err := http.NewRequestWithContext(ctx, "POST", bindURL, strings.NewReader("count=0"))
 	if err != nil {
@@ -376,43 +809,157 @@ func (ls *loungeSession) bind(ctx context.Context) error {
 		return err
 	}
 
+	ls.mu.Lock()
 	ls.sid = sid
 	ls.gsessionID = gsessionID
+	ls.aid = 0
+	ls.ofs = 0
+	ls.mu.Unlock()
 	return nil
 }
 
-// sendCommand sends a command (addVideo, setVideo) to the TV.
-func (ls *loungeSession) sendCommand(command, videoID string, extraParams map[string]string) error {
-	ls.rid++
+// enqueue hands a command to the command loop and waits for its result. A
+// command still waiting for the loop when the timeout passes is dropped, so a
+// caller's retry cannot send it twice; one the loop has started on is
+// bounded by its own request timeouts and waited for.
+func (ls *loungeSession) enqueue(cmd loungeCommand) error {
+	cmd.result = make(chan error, 1)
+	cmd.state = new(atomic.Int32)
+
+	timer := time.NewTimer(ls.commandTimeout)
+	defer timer.Stop()
+
+	select {
+	case ls.commands <- cmd:
+	case <-ls.done:
+		return errLoungeClosed
+	case <-timer.C:
+		return fmt.Errorf("timed out queueing %s command", commandLabel(cmd))
+	}
 
-	queryParams := url.Values{
-		"device":        {"REMOTE_CONTROL"},
-		"name":          {"Songify"},
-		"id":            {ls.screenID},
-		"loungeIdToken": {ls.loungeToken},
-		"VER":           {"8"},
-		"RID":           {strconv.Itoa(ls.rid)},
-		"SID":           {ls.sid},
-		"AID":           {strconv.Itoa(ls.aid)},
+	select {
+	case err := <-cmd.result:
+		return err
+	case <-ls.done:
+		return ls.closedResult(cmd)
+	case <-timer.C:
+		if cmd.state.CompareAndSwap(commandQueued, commandAbandoned) {
+			return fmt.Errorf("timed out waiting to send %s command", commandLabel(cmd))
+		}
+	}
+
+	select {
+	case err := <-cmd.result:
+		return err
+	case <-ls.done:
+		return ls.closedResult(cmd)
+	}
+}
+
+// closedResult is the outcome of a command once the command loop has exited.
+// The loop always answers a command it has taken before exiting.
+func (ls *loungeSession) closedResult(cmd loungeCommand) error {
+	select {
+	case err := <-cmd.result:
+		return err
+	default:
+		return errLoungeClosed
+	}
+}
+
+// commandLoop executes queued commands one at a time until ctx is cancelled,
+// skipping any whose caller gave up waiting.
+// Commands that fail because the TV dropped our SID are retried once after a
+// transparent re-bind.
+func (ls *loungeSession) commandLoop(ctx context.Context, sessionID string) {
+	defer close(ls.done)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case cmd := <-ls.commands:
+			if !cmd.state.CompareAndSwap(commandQueued, commandRunning) {
+				continue // The caller gave up waiting
+			}
+			cmd.result <- ls.execute(ctx, sessionID, cmd)
+		}
+	}
+}
+
+// execute runs a single command on the command loop goroutine.
+func (ls *loungeSession) execute(ctx context.Context, sessionID string, cmd loungeCommand) error {
+	if cmd.name == "" {
+		return ls.rebind(ctx, sessionID, cmd.staleSID)
+	}
+
+	err := ls.sendCommand(ctx, cmd)
+	if !errors.Is(err, errLoungeSessionExpired) {
+		return err
+	}
+
+	slog.Info("lounge: SID expired while sending command, re-binding", slog.String("session_id", sessionID), slog.String("command", cmd.name))
+	if err := ls.rebind(ctx, sessionID, ""); err != nil {
+		return err
+	}
+	return ls.sendCommand(ctx, cmd)
+}
+
+// rebind replaces the session's SID. If staleSID is set and no longer matches
+// the current SID, another caller already re-bound and nothing is done.
+func (ls *loungeSession) rebind(ctx context.Context, sessionID, staleSID string) error {
+	ls.mu.Lock()
+	current := ls.sid
+	ls.mu.Unlock()
+	if staleSID != "" && staleSID != current {
+		return nil
+	}
+
+	bindCtx, cancel := context.WithTimeout(ctx, loungeCommandTimeout)
+	defer cancel()
+	if err := ls.bind(bindCtx); err != nil {
+		slog.Error("lounge: re-bind failed", slog.String("session_id", sessionID), slog.String("error", err.Error()))
+		return fmt.Errorf("re-bind failed: %w", err)
 	}
+
+	ls.mu.Lock()
+	sid := ls.sid
+	ls.status = LoungeStatusConnected
+	ls.errorMsg = ""
+	ls.mu.Unlock()
+	slog.Info("lounge: re-bind succeeded", slog.String("session_id", sessionID), slog.String("sid", sid))
+	return nil
+}
+
+// sendCommand sends a command (addVideo, setVideo, removeVideo) to the TV.
+// Must only be called from the command loop goroutine.
+func (ls *loungeSession) sendCommand(ctx context.Context, cmd loungeCommand) error {
+	ls.mu.Lock()
+	ls.rid++
+	queryParams := ls.bindParams()
+	queryParams.Set("RID", strconv.Itoa(ls.rid))
+	queryParams.Set("SID", ls.sid)
+	queryParams.Set("AID", strconv.Itoa(ls.aid))
 	if ls.gsessionID != "" {
 		queryParams.Set("gsessionid", ls.gsessionID)
 	}
+	ofs := ls.ofs
+	ls.mu.Unlock()
 
 	formData := url.Values{
 		"count":        {"1"},
-		"ofs":          {strconv.Itoa(ls.ofs)},
-		"req0__sc":     {command},
-		"req0_videoId": {videoID},
+		"ofs":          {strconv.Itoa(ofs)},
+		"req0__sc":     {cmd.name},
+		"req0_videoId": {cmd.videoID},
 	}
 
-	for k, v := range extraParams {
+	for k, v := range cmd.extra {
 		formData.Set("req0_"+k, v)
 	}
 
-	cmdURL := fmt.Sprintf("%s/bc/bind?%s", loungeBaseURL, queryParams.Encode())
+	cmdURL := fmt.Sprintf("%s/bc/bind?%s", ls.baseURL, queryParams.Encode())
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, "POST", cmdURL, strings.NewReader(formData.Encode()))
@@ -429,16 +976,23 @@ func (ls *loungeSession) sendCommand(command, videoID string, extraParams map[st
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
+		if isExpiredSessionResponse(resp.StatusCode, body) {
+			return errLoungeSessionExpired
+		}
 		return fmt.Errorf("command failed with status %d: %s", resp.StatusCode, string(body))
 	}
 
+	ls.mu.Lock()
 	ls.ofs++
 	ls.lastActivity = time.Now()
+	ls.mu.Unlock()
 	return nil
 }
 
 // longPollLoop runs in a goroutine, long-polling the TV for events.
 // Stops on context cancel, 30-min inactivity, or 3 consecutive errors.
+// An expired SID is handed to the command loop for a re-bind rather than
+// counted as an error.
 func (m *LoungeManager) longPollLoop(ctx context.Context, sessionID string, ls *loungeSession) {
 	slog.Info("lounge: long-poll loop started", slog.String("session_id", sessionID))
 	consecutiveErrors := 0
@@ -452,17 +1006,26 @@ func (m *LoungeManager) longPollLoop(ctx context.Context, sessionID string, ls *
 		}
 
 		// Check inactivity
-		m.mu.Lock()
+		ls.mu.Lock()
 		if time.Since(ls.lastActivity) > loungeInactivityTimeout {
 			ls.status = LoungeStatusError
 			ls.errorMsg = "disconnected due to inactivity"
-			m.mu.Unlock()
+			ls.mu.Unlock()
 			slog.Info("lounge: disconnected due to inactivity", slog.String("session_id", sessionID))
+			ls.stop()
 			return
 		}
-		m.mu.Unlock()
-
-		err := ls.longPoll(ctx)
+		ls.mu.Unlock()
+
+		sid, err := ls.longPoll(ctx)
+		if errors.Is(err, errLoungeSessionExpired) {
+			slog.Info("lounge: SID expired during poll, re-binding", slog.String("session_id", sessionID))
+			err = ls.enqueue(loungeCommand{staleSID: sid})
+			if err == nil {
+				consecutiveErrors = 0
+				continue
+			}
+		}
 		if err != nil {
 			if ctx.Err() != nil {
 				slog.Info("lounge: long-poll loop stopped (context cancelled)", slog.String("session_id", sessionID))
@@ -473,11 +1036,9 @@ func (m *LoungeManager) longPollLoop(ctx context.Context, sessionID string, ls *
 			slog.Error("lounge: poll error", slog.String("session_id", sessionID), slog.Int("consecutive_errors", consecutiveErrors), slog.String("error", err.Error()))
 
 			if consecutiveErrors >= loungeMaxRetries {
-				m.mu.Lock()
-				ls.status = LoungeStatusError
-				ls.errorMsg = fmt.Sprintf("disconnected after %d consecutive poll errors: %v", loungeMaxRetries, err)
-				m.mu.Unlock()
+				ls.setError(fmt.Sprintf("disconnected after %d consecutive poll errors: %v", loungeMaxRetries, err))
 				slog.Error("lounge: disconnected after max poll retries", slog.String("session_id", sessionID))
+				ls.stop()
 				return
 			}
 
@@ -497,62 +1058,102 @@ func (m *LoungeManager) longPollLoop(ctx context.Context, sessionID string, ls *
 }
 
 // longPoll performs a single long-poll request to the TV.
-func (ls *loungeSession) longPoll(ctx context.Context) error {
-	params := url.Values{
-		"device":        {"REMOTE_CONTROL"},
-		"name":          {"Songify"},
-		"id":            {ls.screenID},
-		"loungeIdToken": {ls.loungeToken},
-		"VER":           {"8"},
-		"SID":           {ls.sid},
-		"AID":           {strconv.Itoa(ls.aid)},
-		"CI":            {"0"},
-		"TYPE":          {"xmlhttp"},
-		"RID":           {"rpc"},
-	}
+// It returns the SID the poll was made with so an expiry can be attributed.
+func (ls *loungeSession) longPoll(ctx context.Context) (string, error) {
+	ls.mu.Lock()
+	sid := ls.sid
+	params := ls.bindParams()
+	params.Set("SID", sid)
+	params.Set("AID", strconv.Itoa(ls.aid))
+	params.Set("CI", "0")
+	params.Set("TYPE", "xmlhttp")
+	params.Set("RID", "rpc")
 	if ls.gsessionID != "" {
 		params.Set("gsessionid", ls.gsessionID)
 	}
+	ls.mu.Unlock()
 
-	pollURL := fmt.Sprintf("%s/bc/bind?%s", loungeBaseURL, params.Encode())
-
-	// Long poll with extended timeout
-	pollClient := &http.Client{Timeout: 3 * time.Minute}
+	pollURL := fmt.Sprintf("%s/bc/bind?%s", ls.baseURL, params.Encode())
 
 	req, err := http.NewRequestWithContext(ctx, "GET", pollURL, nil)
 	if err != nil {
-		return fmt.Errorf("failed to create poll request: %w", err)
+		return sid, fmt.Errorf("failed to create poll request: %w", err)
 	}
 
-	resp, err := pollClient.Do(req)
+	// Long poll with extended timeout
+	resp, err := ls.pollClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("poll request failed: %w", err)
+		return sid, fmt.Errorf("poll request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("failed to read poll response: %w", err)
+		return sid, fmt.Errorf("failed to read poll response: %w", err)
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("poll failed with status %d: %s", resp.StatusCode, string(body))
+		if isExpiredSessionResponse(resp.StatusCode, body) {
+			return sid, errLoungeSessionExpired
+		}
+		return sid, fmt.Errorf("poll failed with status %d: %s", resp.StatusCode, string(body))
 	}
 
-	// Extract latest AID from response
+	// Extract latest AID from response, ignoring events from a replaced SID
 	if newAID, err := parseLongPollAID(body); err == nil {
-		ls.aid = newAID
+		ls.mu.Lock()
+		if ls.sid == sid {
+			ls.aid = newAID
+		}
+		ls.mu.Unlock()
 	}
 
-	return nil
+	// Track what the TV is playing for schedules and the fallback DJ
+	events, err := parseLongPollEvents(body)
+	if err != nil {
+		slog.Debug("lounge: could not parse all poll events", slog.String("error", err.Error()))
+	}
+	ls.applyEvents(events, time.Now())
+
+	return sid, nil
 }
 
-// disconnect cancels the long-poll goroutine and marks the session as disconnected.
-func (ls *loungeSession) disconnect() {
-	if ls.cancel != nil {
-		ls.cancel()
+// stop cancels the command and long-poll goroutines without changing status.
+func (ls *loungeSession) stop() {
+	ls.mu.Lock()
+	cancel := ls.cancel
+	ls.mu.Unlock()
+	if cancel != nil {
+		cancel()
 	}
+}
+
+// disconnect cancels the session's goroutines and marks it as disconnected.
+func (ls *loungeSession) disconnect() {
+	ls.stop()
+	ls.mu.Lock()
 	ls.status = LoungeStatusDisconnected
+	ls.mu.Unlock()
+}
+
+// isExpiredSessionResponse reports whether a bind response indicates that the
+// TV has forgotten our SID (it answers 400 "Unknown SID", or 404/410).
+func isExpiredSessionResponse(status int, body []byte) bool {
+	switch status {
+	case http.StatusNotFound, http.StatusGone:
+		return true
+	case http.StatusBadRequest:
+		return strings.Contains(strings.ToLower(string(body)), "unknown sid")
+	}
+	return false
+}
+
+// commandLabel names a command for error messages.
+func commandLabel(cmd loungeCommand) string {
+	if cmd.name == "" {
+		return "re-bind"
+	}
+	return cmd.name
 }
 
 // parsePairingResponse parses the JSON response from the pairing endpoint.
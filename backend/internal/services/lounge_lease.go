@@ -0,0 +1,370 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/songify/backend/internal/db"
+)
+
+// Only one instance may hold a TV's long-poll connection, so each session's
+// Lounge connections are owned by the instance holding its lease in the
+// database. Pairing or reconnecting claims the lease, taking over from any
+// other owner. The owner renews the lease while it has screens connected;
+// an instance that finds its lease gone disconnects its screens.
+//
+// Instances without the lease store commands in lounge_commands, which the
+// owner polls and delivers in order. The owner claims a command before
+// sending it and marks it sent or failed afterwards; the sender waits for
+// that outcome, and cancels a command still unclaimed after its ack timeout
+// so that a retry cannot play the song twice. Lease expiry uses each
+// instance's clock, so clocks are assumed to agree to well within
+// loungeLeaseTTL.
+const (
+	loungeLeaseTTL            = 15 * time.Second
+	loungeLeaseRenewInterval  = 5 * time.Second
+	loungeCommandPollInterval = time.Second
+	loungeCommandMaxAge       = time.Minute
+	loungeCommandAckTimeout   = 10 * time.Second
+	loungeCommandAckPoll      = 100 * time.Millisecond
+)
+
+// Run renews this instance's leases and delivers commands stored by other
+// instances until ctx is cancelled.
+func (m *LoungeManager) Run(ctx context.Context) {
+	leases := time.NewTicker(loungeLeaseRenewInterval)
+	defer leases.Stop()
+	commands := time.NewTicker(loungeCommandPollInterval)
+	defer commands.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-leases.C:
+			m.renewLeases(ctx)
+		case <-commands.C:
+			m.dispatchCommands(ctx)
+		}
+	}
+}
+
+// claim takes the session's lease for this instance. It reports whether the
+// lease was taken over from another live owner, whose screens are then
+// disconnected at its next renewal.
+func (m *LoungeManager) claim(ctx context.Context, sessionID string) (bool, error) {
+	m.leaseMu.Lock()
+	defer m.leaseMu.Unlock()
+
+	now := time.Now()
+	lease, err := m.queries.GetLoungeLease(ctx, sessionID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return false, fmt.Errorf("failed to load lounge lease: %w", err)
+	}
+	tookOver := err == nil && lease.Owner != m.instanceID && lease.ExpiresAt > now.UnixMilli()
+
+	if err := m.queries.ClaimLoungeLease(ctx, db.ClaimLoungeLeaseParams{
+		SessionID: sessionID,
+		Owner:     m.instanceID,
+		ExpiresAt: now.Add(loungeLeaseTTL).UnixMilli(),
+	}); err != nil {
+		return false, fmt.Errorf("failed to claim lounge lease: %w", err)
+	}
+
+	// A group cached while another instance owned the session may hold a
+	// stale primary screen or target
+	m.mu.Lock()
+	if g, ok := m.sessions[sessionID]; ok && !g.leased && len(g.screens) == 0 {
+		delete(m.sessions, sessionID)
+	}
+	m.mu.Unlock()
+
+	g, err := m.group(ctx, sessionID)
+	if err != nil {
+		return false, err
+	}
+	m.mu.Lock()
+	g.leased = true
+	g.claimedAt = now
+	m.mu.Unlock()
+
+	if tookOver {
+		slog.Info("lounge: took over connections from another instance", slog.String("session_id", sessionID), slog.String("previous_owner", lease.Owner))
+	}
+	return tookOver, nil
+}
+
+// adopt reconnects the session's other paired screens after a takeover, as
+// the previous owner drops them.
+func (m *LoungeManager) adopt(ctx context.Context, sessionID, except string) {
+	screens, err := m.queries.GetLoungeScreensBySessionID(ctx, sessionID)
+	if err != nil {
+		slog.Error("lounge: failed to load screens to adopt", slog.String("session_id", sessionID), slog.String("error", err.Error()))
+		return
+	}
+	for _, s := range screens {
+		if s.ScreenID == except {
+			continue
+		}
+		if err := m.reconnect(ctx, sessionID, s); err != nil {
+			slog.Warn("lounge: failed to adopt screen", slog.String("session_id", sessionID), slog.String("screen_id", s.ScreenID), slog.String("error", err.Error()))
+		}
+	}
+}
+
+// remoteOwner returns the instance holding the session's lease if it is live
+// and not this one.
+func (m *LoungeManager) remoteOwner(ctx context.Context, sessionID string) (string, bool) {
+	lease, err := m.queries.GetLoungeLease(ctx, sessionID)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			slog.Error("lounge: failed to load lease", slog.String("session_id", sessionID), slog.String("error", err.Error()))
+		}
+		return "", false
+	}
+	if lease.Owner == m.instanceID || lease.ExpiresAt <= time.Now().UnixMilli() {
+		return "", false
+	}
+	return lease.Owner, true
+}
+
+// forward stores a command for the instance that owns the session's
+// connections and waits until the owner has sent it, returning the owner's
+// error if sending failed. Returns nil, skipping the command, if no instance
+// owns the connections.
+func (m *LoungeManager) forward(ctx context.Context, sessionID string, cmd loungeCommand) error {
+	owner, ok := m.remoteOwner(ctx, sessionID)
+	if !ok {
+		slog.Info("lounge: "+cmd.name+" skipped, not connected", slog.String("session_id", sessionID), slog.String("video_id", cmd.videoID))
+		return nil
+	}
+
+	stored, err := m.queries.CreateLoungeCommand(ctx, db.CreateLoungeCommandParams{
+		SessionID: sessionID,
+		Command:   cmd.name,
+		VideoID:   cmd.videoID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to forward %s: %w", commandLabel(cmd), err)
+	}
+	slog.Info("lounge: "+cmd.name+" forwarded", slog.String("session_id", sessionID), slog.String("video_id", cmd.videoID), slog.String("owner", owner), slog.Int64("command_id", stored.ID))
+	return m.awaitCommand(ctx, stored.ID)
+}
+
+// awaitCommand polls a forwarded command until the owner has sent or failed
+// it. A command the owner has not claimed within m.ackTimeout is cancelled;
+// one it has claimed is waited for until ctx ends or loungeCommandMaxAge
+// passes.
+func (m *LoungeManager) awaitCommand(ctx context.Context, id int64) error {
+	ctx, cancel := context.WithTimeout(ctx, loungeCommandMaxAge)
+	defer cancel()
+	ackDeadline := time.Now().Add(m.ackTimeout)
+	ticker := time.NewTicker(loungeCommandAckPoll)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			m.cancelCommand(id)
+			return fmt.Errorf("forwarded command %d: %w", id, ctx.Err())
+		case <-ticker.C:
+		}
+
+		c, err := m.queries.GetLoungeCommand(ctx, id)
+		if err != nil {
+			if ctx.Err() != nil {
+				continue
+			}
+			return fmt.Errorf("failed to check forwarded command %d: %w", id, err)
+		}
+		switch {
+		case c.Status == "sent":
+			return nil
+		case c.Status == "failed":
+			return fmt.Errorf("forwarded %s failed: %s", c.Command, c.Error.String)
+		case !c.ProcessedAt.Valid && time.Now().After(ackDeadline):
+			if m.cancelCommand(id) {
+				return fmt.Errorf("forwarded %s was not picked up by the owning instance", c.Command)
+			}
+			// Claimed just now; wait for the outcome
+		}
+	}
+}
+
+// cancelCommand fails a forwarded command the owner has not claimed yet, so
+// it is never sent. It reports whether the command was cancelled.
+func (m *LoungeManager) cancelCommand(id int64) bool {
+	n, err := m.queries.CancelLoungeCommand(context.Background(), db.CancelLoungeCommandParams{
+		Error: sql.NullString{String: "not picked up in time", Valid: true},
+		ID:    id,
+	})
+	if err != nil {
+		slog.Error("lounge: failed to cancel forwarded command", slog.Int64("command_id", id), slog.String("error", err.Error()))
+		return false
+	}
+	return n > 0
+}
+
+// renewLeases extends the leases of sessions with live screens and releases
+// the rest. Sessions whose lease was taken over or deleted are disconnected;
+// the others pick up screen and target changes made on other instances.
+func (m *LoungeManager) renewLeases(ctx context.Context) {
+	m.leaseMu.Lock()
+	defer m.leaseMu.Unlock()
+
+	now := time.Now()
+	var renew, release []string
+	m.mu.Lock()
+	for sessionID, g := range m.sessions {
+		if !g.leased {
+			continue
+		}
+		// A fresh claim is kept while its screens bind
+		if g.live() || now.Sub(g.claimedAt) < loungeLeaseTTL {
+			renew = append(renew, sessionID)
+			continue
+		}
+		g.leased = false
+		release = append(release, sessionID)
+	}
+	m.mu.Unlock()
+
+	for _, sessionID := range release {
+		if err := m.queries.ReleaseLoungeLease(ctx, db.ReleaseLoungeLeaseParams{SessionID: sessionID, Owner: m.instanceID}); err != nil {
+			slog.Error("lounge: failed to release lease", slog.String("session_id", sessionID), slog.String("error", err.Error()))
+		}
+	}
+
+	for _, sessionID := range renew {
+		n, err := m.queries.RenewLoungeLease(ctx, db.RenewLoungeLeaseParams{
+			ExpiresAt: now.Add(loungeLeaseTTL).UnixMilli(),
+			SessionID: sessionID,
+			Owner:     m.instanceID,
+		})
+		if err != nil {
+			slog.Error("lounge: failed to renew lease", slog.String("session_id", sessionID), slog.String("error", err.Error()))
+			continue
+		}
+		if n == 0 {
+			m.drop(sessionID)
+			continue
+		}
+		if err := m.refresh(ctx, sessionID); err != nil {
+			slog.Error("lounge: failed to refresh screens", slog.String("session_id", sessionID), slog.String("error", err.Error()))
+		}
+	}
+}
+
+// drop disconnects a session whose lease this instance has lost.
+func (m *LoungeManager) drop(sessionID string) {
+	m.mu.Lock()
+	g, ok := m.sessions[sessionID]
+	delete(m.sessions, sessionID)
+	m.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	slog.Info("lounge: lease lost, disconnecting", slog.String("session_id", sessionID))
+	for _, ls := range g.screens {
+		ls.disconnect()
+	}
+}
+
+// refresh applies the persisted primary screen and target mode to an owned
+// session and disconnects screens that have been unpaired.
+func (m *LoungeManager) refresh(ctx context.Context, sessionID string) error {
+	session, err := m.queries.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to load session: %w", err)
+	}
+	screens, err := m.queries.GetLoungeScreensBySessionID(ctx, sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to load screens: %w", err)
+	}
+
+	paired := make(map[string]bool, len(screens))
+	primary := ""
+	for _, s := range screens {
+		paired[s.ScreenID] = true
+		if s.IsPrimary {
+			primary = s.ScreenID
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	g, ok := m.sessions[sessionID]
+	if !ok {
+		return nil
+	}
+	g.primary = primary
+	g.target = LoungeTarget(session.LoungeTarget)
+	for screenID, ls := range g.screens {
+		if !paired[screenID] {
+			slog.Info("lounge: screen unpaired on another instance, disconnecting", slog.String("session_id", sessionID), slog.String("screen_id", screenID))
+			ls.disconnect()
+			delete(g.screens, screenID)
+		}
+	}
+	return nil
+}
+
+// dispatchCommands delivers commands stored by other instances for sessions
+// this instance owns, oldest first, claiming each one first so its sender
+// cannot cancel it mid-delivery. Commands left undelivered for longer than
+// loungeCommandMaxAge are failed rather than played late.
+func (m *LoungeManager) dispatchCommands(ctx context.Context) {
+	commands, err := m.queries.GetPendingLoungeCommands(ctx, m.instanceID)
+	if err != nil {
+		slog.Error("lounge: failed to load forwarded commands", slog.String("error", err.Error()))
+		return
+	}
+
+	for _, c := range commands {
+		var sendErr error
+		if c.CreatedAt.Valid && time.Since(c.CreatedAt.Time) > loungeCommandMaxAge {
+			sendErr = errors.New("expired before it could be delivered")
+		} else if claimed, err := m.queries.ClaimLoungeCommand(ctx, c.ID); err != nil || claimed == 0 {
+			if err != nil {
+				slog.Error("lounge: failed to claim forwarded command", slog.Int64("command_id", c.ID), slog.String("error", err.Error()))
+			}
+			continue // Cancelled by its sender
+		} else if targets := m.targets(c.SessionID); len(targets) == 0 {
+			sendErr = errors.New("no targeted screen is connected")
+		} else {
+			sendErr = m.broadcast(c.SessionID, targets, newLoungeCommand(c.Command, c.VideoID))
+		}
+
+		if sendErr == nil {
+			err = m.queries.MarkLoungeCommandSent(ctx, c.ID)
+		} else {
+			slog.Warn("lounge: forwarded "+c.Command+" failed", slog.String("session_id", c.SessionID), slog.Int64("command_id", c.ID), slog.String("error", sendErr.Error()))
+			err = m.queries.MarkLoungeCommandFailed(ctx, db.MarkLoungeCommandFailedParams{
+				Error: sql.NullString{String: sendErr.Error(), Valid: true},
+				ID:    c.ID,
+			})
+		}
+		if err != nil {
+			slog.Error("lounge: failed to update forwarded command", slog.Int64("command_id", c.ID), slog.String("error", err.Error()))
+		}
+	}
+}
+
+// live reports whether any of the group's screens is connected or binding.
+// Must be called with LoungeManager.mu held.
+func (g *loungeGroup) live() bool {
+	for _, ls := range g.screens {
+		ls.mu.Lock()
+		status := ls.status
+		ls.mu.Unlock()
+		if status == LoungeStatusConnected || status == LoungeStatusConnecting {
+			return true
+		}
+	}
+	return false
+}
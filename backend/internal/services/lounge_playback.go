@@ -0,0 +1,169 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"time"
+)
+
+// PlayerState is the TV player's state as reported by Lounge events.
+type PlayerState int
+
+const (
+	PlayerIdle      PlayerState = -1 // Nothing loaded
+	PlayerEnded     PlayerState = 0
+	PlayerPlaying   PlayerState = 1
+	PlayerPaused    PlayerState = 2
+	PlayerBuffering PlayerState = 3
+)
+
+// NowPlaying is the last known playback position of a session's player.
+type NowPlaying struct {
+	VideoID     string
+	State       PlayerState
+	CurrentTime time.Duration
+	Duration    time.Duration
+	UpdatedAt   time.Time
+}
+
+// Idle reports whether nothing is playing or about to play.
+func (p NowPlaying) Idle() bool {
+	return p.VideoID == "" || p.State == PlayerIdle || p.State == PlayerEnded
+}
+
+// Remaining estimates how much of the current video is left at now,
+// advancing the last reported position while it is playing.
+func (p NowPlaying) Remaining(now time.Time) time.Duration {
+	remaining := p.Duration - p.CurrentTime
+	if p.State == PlayerPlaying {
+		remaining -= now.Sub(p.UpdatedAt)
+	}
+	return max(remaining, 0)
+}
+
+// PlaybackMonitor is implemented by playback targets that can report what a
+// session's player is doing.
+type PlaybackMonitor interface {
+	// NowPlaying returns the last known playback state, or false if the
+	// target has not reported one.
+	NowPlaying(sessionID string) (NowPlaying, bool)
+}
+
+// NowPlaying implements PlaybackMonitor using the primary screen's events,
+// or any connected screen's when broadcasting.
+func (m *LoungeManager) NowPlaying(sessionID string) (NowPlaying, bool) {
+	var latest NowPlaying
+	found := false
+	for _, ls := range m.targets(sessionID) {
+		ls.mu.Lock()
+		p := ls.nowPlaying
+		ls.mu.Unlock()
+		if !p.UpdatedAt.IsZero() && (!found || p.UpdatedAt.After(latest.UpdatedAt)) {
+			latest, found = p, true
+		}
+	}
+	return latest, found
+}
+
+// loungeEvent is one event from a long-poll response.
+type loungeEvent struct {
+	AID  int
+	Name string
+	Args []json.RawMessage
+}
+
+// loungePlaybackEvent is the payload of nowPlaying and onStateChange events.
+// The TV sends every value as a string.
+type loungePlaybackEvent struct {
+	VideoID     *string `json:"videoId"`
+	State       string  `json:"state"`
+	CurrentTime string  `json:"currentTime"`
+	Duration    string  `json:"duration"`
+}
+
+// parseLongPollEvents decodes a long-poll response: a stream of chunk
+// lengths, each followed by a JSON array of [AID, [name, args...]] events.
+func parseLongPollEvents(body []byte) ([]loungeEvent, error) {
+	var events []loungeEvent
+	dec := json.NewDecoder(bytes.NewReader(body))
+	for {
+		var chunk json.RawMessage
+		if err := dec.Decode(&chunk); errors.Is(err, io.EOF) {
+			return events, nil
+		} else if err != nil {
+			return events, err
+		}
+
+		var raw [][]json.RawMessage
+		if json.Unmarshal(chunk, &raw) != nil {
+			continue // A chunk length
+		}
+		for _, entry := range raw {
+			if len(entry) != 2 {
+				continue
+			}
+			var event loungeEvent
+			var payload []json.RawMessage
+			if json.Unmarshal(entry[0], &event.AID) != nil || json.Unmarshal(entry[1], &payload) != nil || len(payload) == 0 {
+				continue
+			}
+			if json.Unmarshal(payload[0], &event.Name) != nil {
+				continue
+			}
+			event.Args = payload[1:]
+			events = append(events, event)
+		}
+	}
+}
+
+// applyEvents updates the session's playback state from long-poll events.
+func (ls *loungeSession) applyEvents(events []loungeEvent, now time.Time) {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	for _, event := range events {
+		if event.Name != "nowPlaying" && event.Name != "onStateChange" {
+			continue
+		}
+		if len(event.Args) == 0 {
+			continue
+		}
+		var payload loungePlaybackEvent
+		if err := json.Unmarshal(event.Args[0], &payload); err != nil {
+			continue
+		}
+
+		p := ls.nowPlaying
+		if payload.VideoID != nil {
+			p.VideoID = *payload.VideoID
+		}
+		if state, err := strconv.Atoi(payload.State); err == nil {
+			p.State = PlayerState(state)
+		} else if event.Name == "nowPlaying" && p.VideoID == "" {
+			p.State = PlayerIdle
+		}
+		if t, ok := parseLoungeSeconds(payload.CurrentTime); ok {
+			p.CurrentTime = t
+		}
+		if d, ok := parseLoungeSeconds(payload.Duration); ok {
+			p.Duration = d
+		}
+		p.UpdatedAt = now
+		ls.nowPlaying = p
+	}
+}
+
+// parseLoungeSeconds parses a fractional seconds string.
+func parseLoungeSeconds(s string) (time.Duration, bool) {
+	if s == "" {
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, false
+	}
+	return time.Duration(f * float64(time.Second)), true
+}
@@ -0,0 +1,547 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/songify/backend/internal/database"
+	"github.com/songify/backend/internal/database/dbtest"
+)
+
+// fakeLounge is a minimal stand-in for the YouTube Lounge bind endpoint.
+// It records every command and checks that RID strictly increases and that
+// ofs is contiguous within each SID, which is what the real TV enforces.
+type fakeLounge struct {
+	t *testing.T
+
+	mu        sync.Mutex
+	sidSeq    int
+	sid       string
+	lastRID   int
+	nextOfs   int
+	aid       int
+	binds     int
+	videos    []string
+	violation string
+
+	// holdVideo's command is answered only once release is closed; held is
+	// signalled when it arrives.
+	holdVideo string
+	held      chan struct{}
+	release   chan struct{}
+}
+
+func newFakeLounge(t *testing.T) (*fakeLounge, *httptest.Server) {
+	f := &fakeLounge{t: t}
+	srv := httptest.NewServer(http.HandlerFunc(f.serve))
+	t.Cleanup(srv.Close)
+	return f, srv
+}
+
+func (f *fakeLounge) serve(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/pairing/get_screen":
+		fmt.Fprint(w, `{"screen":{"screenId":"screen-1","loungeToken":"token-1","screenName":"Living Room TV"}}`)
+	case "/bc/bind":
+		if r.Method == http.MethodGet {
+			f.poll(w, r)
+			return
+		}
+		f.post(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (f *fakeLounge) poll(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	if r.URL.Query().Get("SID") != f.sid {
+		f.mu.Unlock()
+		http.Error(w, "Unknown SID", http.StatusBadRequest)
+		return
+	}
+	f.aid++
+	aid := f.aid
+	f.mu.Unlock()
+
+	select {
+	case <-r.Context().Done():
+	case <-time.After(5 * time.Millisecond):
+	}
+	fmt.Fprintf(w, "[[%d,[\"noop\"]]]", aid)
+}
+
+func (f *fakeLounge) post(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	form, _ := url.ParseQuery(string(body))
+	query := r.URL.Query()
+
+	if video := form.Get("req0_videoId"); video != "" && video == f.holdVideo {
+		f.held <- struct{}{}
+		<-f.release
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	rid, _ := strconv.Atoi(query.Get("RID"))
+	if rid <= f.lastRID && f.violation == "" {
+		f.violation = fmt.Sprintf("RID went from %d to %d", f.lastRID, rid)
+	}
+	f.lastRID = rid
+
+	if query.Get("SID") == "" {
+		f.sidSeq++
+		f.binds++
+		f.sid = fmt.Sprintf("sid-%d", f.sidSeq)
+		f.nextOfs = 0
+		fmt.Fprintf(w, "[[0,[\"c\",\"%s\",\"\",8]],[1,[\"S\",\"gs-%d\"]]]", f.sid, f.sidSeq)
+		return
+	}
+
+	if query.Get("SID") != f.sid {
+		http.Error(w, "Unknown SID", http.StatusBadRequest)
+		return
+	}
+
+	ofs, _ := strconv.Atoi(form.Get("ofs"))
+	if ofs != f.nextOfs && f.violation == "" {
+		f.violation = fmt.Sprintf("ofs %d, want %d", ofs, f.nextOfs)
+	}
+	f.nextOfs++
+	f.videos = append(f.videos, form.Get("req0_videoId"))
+	fmt.Fprint(w, "ok")
+}
+
+// expire makes the fake forget the current SID, as a TV does after a timeout.
+func (f *fakeLounge) expire() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sid = "expired"
+}
+
+func (f *fakeLounge) snapshot() (videos []string, binds int, violation string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.videos...), f.binds, f.violation
+}
+
+func newTestLoungeManager(t *testing.T, baseURL string) *LoungeManager {
+	m := NewLoungeManager(database.NewQueries(dbtest.New(t)))
+	m.baseURL = baseURL
+	m.sessions["session-1"] = &loungeGroup{
+		screens: make(map[string]*loungeSession),
+		target:  LoungeTargetPrimary,
+	}
+	addTestScreen(t, m, "session-1", baseURL)
+	return m
+}
+
+// addTestScreen pairs a screen served by baseURL into an existing group.
+// The first screen added becomes the primary.
+func addTestScreen(t *testing.T, m *LoungeManager, sessionID, baseURL string) *loungeSession {
+	ls := newLoungeSession(baseURL)
+	if err := ls.getScreen(context.Background(), "123456"); err != nil {
+		t.Fatalf("getScreen: %v", err)
+	}
+
+	m.mu.Lock()
+	g := m.sessions[sessionID]
+	g.screens[baseURL] = ls
+	if g.primary == "" {
+		g.primary = baseURL
+	}
+	m.mu.Unlock()
+
+	if err := m.start(context.Background(), sessionID, ls); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	t.Cleanup(ls.disconnect)
+	return ls
+}
+
+func TestLoungeConcurrentCommands(t *testing.T) {
+	fake, srv := newFakeLounge(t)
+	m := newTestLoungeManager(t, srv.URL)
+
+	const n = 50
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if i%10 == 0 {
+				errs <- m.PlayNow(context.Background(), "session-1", fmt.Sprintf("video-%d", i))
+				return
+			}
+			errs <- m.Enqueue(context.Background(), "session-1", fmt.Sprintf("video-%d", i))
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("send failed: %v", err)
+		}
+	}
+
+	videos, _, violation := fake.snapshot()
+	if violation != "" {
+		t.Errorf("protocol violation: %s", violation)
+	}
+	if len(videos) != n {
+		t.Errorf("TV received %d videos, want %d", len(videos), n)
+	}
+	if !m.IsConnected("session-1") {
+		t.Error("expected session to remain connected")
+	}
+}
+
+func TestLoungeRebindsOnExpiredSID(t *testing.T) {
+	fake, srv := newFakeLounge(t)
+	m := newTestLoungeManager(t, srv.URL)
+
+	if err := m.Enqueue(context.Background(), "session-1", "before"); err != nil {
+		t.Fatalf("Enqueue before expiry: %v", err)
+	}
+
+	fake.expire()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := m.Enqueue(context.Background(), "session-1", fmt.Sprintf("after-%d", i)); err != nil {
+				t.Errorf("Enqueue after expiry: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	videos, binds, violation := fake.snapshot()
+	if violation != "" {
+		t.Errorf("protocol violation: %s", violation)
+	}
+	if len(videos) != 21 {
+		t.Errorf("TV received %d videos, want 21", len(videos))
+	}
+	if binds < 2 {
+		t.Errorf("binds = %d, want a re-bind after expiry", binds)
+	}
+	status := m.screenStatus("session-1", srv.URL)
+	if status.Status != LoungeStatusConnected {
+		t.Errorf("status = %s (%s), want connected", status.Status, status.Error)
+	}
+}
+
+func TestLoungeDisconnectUnblocksSenders(t *testing.T) {
+	_, srv := newFakeLounge(t)
+	m := newTestLoungeManager(t, srv.URL)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// Either delivered, closed, or skipped; the point is not to hang.
+			_ = m.Enqueue(context.Background(), "session-1", fmt.Sprintf("video-%d", i))
+		}(i)
+	}
+
+	m.mu.Lock()
+	ls := m.sessions["session-1"].screens[srv.URL]
+	delete(m.sessions, "session-1")
+	m.mu.Unlock()
+	ls.disconnect()
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("senders still blocked after disconnect")
+	}
+
+	if m.IsConnected("session-1") {
+		t.Error("expected session to be disconnected")
+	}
+}
+
+func TestLoungeDropsAbandonedCommands(t *testing.T) {
+	f, srv := newFakeLounge(t)
+	f.holdVideo, f.held, f.release = "slow", make(chan struct{}), make(chan struct{})
+	m := newTestLoungeManager(t, srv.URL)
+	m.mu.Lock()
+	m.sessions["session-1"].screens[srv.URL].commandTimeout = 50 * time.Millisecond
+	m.mu.Unlock()
+
+	ctx := context.Background()
+	slow := make(chan error, 1)
+	go func() { slow <- m.Enqueue(ctx, "session-1", "slow") }()
+	<-f.held
+
+	// Stuck behind the slow command, this one times out before it is sent
+	if err := m.Enqueue(ctx, "session-1", "abandoned"); err == nil {
+		t.Fatal("Enqueue behind a stuck command = nil, want a timeout")
+	}
+	close(f.release)
+	// The slow command was already with the TV, so its caller gets the outcome
+	if err := <-slow; err != nil {
+		t.Errorf("slow command: %v", err)
+	}
+	if err := m.Enqueue(ctx, "session-1", "next"); err != nil {
+		t.Fatalf("Enqueue after the timeout: %v", err)
+	}
+
+	videos, _, violation := f.snapshot()
+	if violation != "" {
+		t.Error(violation)
+	}
+	if fmt.Sprint(videos) != "[slow next]" {
+		t.Errorf("videos = %v, want the abandoned command never sent", videos)
+	}
+}
+
+func TestLoungeTargets(t *testing.T) {
+	primaryFake, primarySrv := newFakeLounge(t)
+	secondFake, secondSrv := newFakeLounge(t)
+	m := newTestLoungeManager(t, primarySrv.URL)
+	addTestScreen(t, m, "session-1", secondSrv.URL)
+
+	if err := m.Enqueue(context.Background(), "session-1", "primary-only"); err != nil {
+		t.Fatalf("Enqueue in primary mode: %v", err)
+	}
+
+	m.mu.Lock()
+	m.sessions["session-1"].target = LoungeTargetAll
+	m.mu.Unlock()
+
+	if err := m.Enqueue(context.Background(), "session-1", "everyone"); err != nil {
+		t.Fatalf("Enqueue in all mode: %v", err)
+	}
+
+	// A broadcast still succeeds while at least one screen is reachable
+	primarySrv.Close()
+	if err := m.Enqueue(context.Background(), "session-1", "survivor"); err != nil {
+		t.Errorf("Enqueue with one screen down: %v", err)
+	}
+
+	primaryVideos, _, _ := primaryFake.snapshot()
+	secondVideos, _, violation := secondFake.snapshot()
+	if violation != "" {
+		t.Errorf("protocol violation: %s", violation)
+	}
+	if want := []string{"primary-only", "everyone"}; fmt.Sprint(primaryVideos) != fmt.Sprint(want) {
+		t.Errorf("primary received %v, want %v", primaryVideos, want)
+	}
+	if want := []string{"everyone", "survivor"}; fmt.Sprint(secondVideos) != fmt.Sprint(want) {
+		t.Errorf("second screen received %v, want %v", secondVideos, want)
+	}
+}
+
+func TestIsExpiredSessionResponse(t *testing.T) {
+	tests := []struct {
+		status int
+		body   string
+		want   bool
+	}{
+		{http.StatusBadRequest, "Unknown SID", true},
+		{http.StatusBadRequest, "bad request", false},
+		{http.StatusNotFound, "", true},
+		{http.StatusGone, "", true},
+		{http.StatusInternalServerError, "Unknown SID", false},
+	}
+
+	for _, tt := range tests {
+		if got := isExpiredSessionResponse(tt.status, []byte(tt.body)); got != tt.want {
+			t.Errorf("isExpiredSessionResponse(%d, %q) = %v, want %v", tt.status, tt.body, got, tt.want)
+		}
+	}
+}
+
+func TestLoungeNowPlayingEvents(t *testing.T) {
+	body := `52
+[[4,["nowPlaying",{"videoId":"abc","state":"1","currentTime":"30.5","duration":"200"}]]]
+44
+[[5,["onStateChange",{"state":"2","currentTime":"31"}]],[6,["noop"]]]
+`
+	events, err := parseLongPollEvents([]byte(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 3 || events[0].Name != "nowPlaying" || events[2].AID != 6 {
+		t.Fatalf("events = %+v, want nowPlaying, onStateChange and noop", events)
+	}
+
+	now := time.Now()
+	ls := &loungeSession{}
+	ls.applyEvents(events, now)
+	p := ls.nowPlaying
+	if p.VideoID != "abc" || p.State != PlayerPaused || p.CurrentTime != 31*time.Second || p.Duration != 200*time.Second {
+		t.Errorf("now playing = %+v, want abc paused at 31s of 200s", p)
+	}
+	if got := p.Remaining(now.Add(time.Minute)); got != 169*time.Second {
+		t.Errorf("paused remaining = %v, want 169s", got)
+	}
+
+	p.State = PlayerPlaying
+	if got := p.Remaining(now.Add(time.Minute)); got != 109*time.Second {
+		t.Errorf("playing remaining = %v, want 109s", got)
+	}
+	if p.Idle() {
+		t.Error("Idle() = true while a video is loaded")
+	}
+}
+
+func TestLoungeLeaseForwarding(t *testing.T) {
+	fake, srv := newFakeLounge(t)
+	ctx := context.Background()
+	queries := database.NewQueries(dbtest.New(t))
+	dbtest.Session(t, queries, "s1")
+
+	// Two instances of the server sharing one database
+	owner := NewLoungeManager(queries)
+	owner.baseURL = srv.URL
+	other := NewLoungeManager(queries)
+	other.baseURL = srv.URL
+	t.Cleanup(func() { owner.drop("s1"); other.drop("s1") })
+
+	if err := other.Enqueue(ctx, "s1", "unpaired"); err != nil {
+		t.Fatalf("Enqueue before pairing: %v", err)
+	}
+	if other.IsConnected("s1") {
+		t.Error("IsConnected before pairing = true")
+	}
+
+	if _, err := owner.Pair(ctx, "s1", "123456", ""); err != nil {
+		t.Fatalf("Pair: %v", err)
+	}
+	if !other.IsConnected("s1") {
+		t.Fatal("IsConnected on the other instance = false, want true while the owner is connected")
+	}
+	screens, _, err := other.Screens(ctx, "s1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(screens) != 1 || screens[0].Status != LoungeStatusConnected {
+		t.Errorf("screens on the other instance = %+v, want one connected", screens)
+	}
+
+	// Commands from the other instance wait for the owner to deliver them
+	forwarded := func(videoID string) <-chan error {
+		t.Helper()
+		errc := make(chan error, 1)
+		go func() { errc <- other.Enqueue(ctx, "s1", videoID) }()
+		for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+			if pending, _ := queries.GetPendingLoungeCommands(ctx, owner.instanceID); len(pending) > 0 {
+				return errc
+			}
+			if time.Now().After(deadline) {
+				t.Fatal("command was not forwarded")
+			}
+		}
+	}
+	errc := forwarded("forwarded")
+	if videos, _, _ := fake.snapshot(); len(videos) != 0 {
+		t.Fatalf("TV received %v before the owner dispatched", videos)
+	}
+	other.dispatchCommands(ctx)
+	if videos, _, _ := fake.snapshot(); len(videos) != 0 {
+		t.Fatalf("non-owner delivered %v", videos)
+	}
+	owner.dispatchCommands(ctx)
+	if err := <-errc; err != nil {
+		t.Fatalf("Enqueue on the other instance: %v", err)
+	}
+	if videos, _, _ := fake.snapshot(); fmt.Sprint(videos) != "[forwarded]" {
+		t.Errorf("TV received %v, want [forwarded]", videos)
+	}
+	if pending, _ := queries.GetPendingLoungeCommands(ctx, owner.instanceID); len(pending) != 0 {
+		t.Errorf("%d commands still pending after dispatch", len(pending))
+	}
+
+	// A command the owner does not pick up in time fails, so the caller can
+	// retry, and is never delivered late
+	other.ackTimeout = 50 * time.Millisecond
+	if err := <-forwarded("late"); err == nil {
+		t.Error("Enqueue without an acknowledgment = nil, want an error")
+	}
+	owner.dispatchCommands(ctx)
+	if videos, _, _ := fake.snapshot(); fmt.Sprint(videos) != "[forwarded]" {
+		t.Errorf("TV received %v, want the cancelled command left out", videos)
+	}
+
+	// Pairing on the other instance takes over; the old owner lets go at
+	// its next renewal and forwards from then on
+	if _, err := other.Pair(ctx, "s1", "123456", ""); err != nil {
+		t.Fatalf("Pair on the other instance: %v", err)
+	}
+	owner.renewLeases(ctx)
+	if len(owner.targets("s1")) != 0 {
+		t.Error("old owner kept its connection after losing the lease")
+	}
+	if !owner.IsConnected("s1") {
+		t.Error("IsConnected on the old owner = false, want true while the new owner is connected")
+	}
+
+	// Disconnecting anywhere releases the lease
+	owner.Disconnect("s1")
+	other.renewLeases(ctx)
+	if other.IsConnected("s1") || owner.IsConnected("s1") {
+		t.Error("session still connected after Disconnect")
+	}
+}
+
+func TestLoungeClose(t *testing.T) {
+	_, srv := newFakeLounge(t)
+	ctx := context.Background()
+	queries := database.NewQueries(dbtest.New(t))
+	dbtest.Session(t, queries, "s1")
+
+	m := NewLoungeManager(queries)
+	m.baseURL = srv.URL
+	other := NewLoungeManager(queries)
+	other.baseURL = srv.URL
+	t.Cleanup(func() { other.drop("s1") })
+
+	if _, err := m.Pair(ctx, "s1", "123456", ""); err != nil {
+		t.Fatalf("Pair: %v", err)
+	}
+
+	// Close returns once the loops have stopped, leaving the TV free for
+	// another instance without forgetting it
+	m.Close()
+	if len(m.targets("s1")) != 0 {
+		t.Error("screen still connected after Close")
+	}
+	if other.IsConnected("s1") {
+		t.Error("lease still held after Close")
+	}
+	if screens, _ := queries.GetLoungeScreensBySessionID(ctx, "s1"); len(screens) != 1 {
+		t.Errorf("%d screens persisted after Close, want 1", len(screens))
+	}
+
+	if err := m.Reconnect(ctx, "s1"); err == nil {
+		t.Error("Reconnect after Close succeeded")
+	}
+	if err := other.Reconnect(ctx, "s1"); err != nil {
+		t.Fatalf("Reconnect on another instance: %v", err)
+	}
+	if !other.IsConnected("s1") {
+		t.Error("IsConnected after reconnecting on another instance = false")
+	}
+}
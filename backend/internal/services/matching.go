@@ -0,0 +1,239 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// MinMatchConfidence is the lowest score a metadata match needs to be used.
+const MinMatchConfidence = 0.65
+
+// How a cross-service match was found.
+const (
+	MatchMethodISRC     = "isrc"
+	MatchMethodMetadata = "metadata"
+)
+
+// ErrNoMatch is returned when no track on the target service matches
+// confidently enough.
+var ErrNoMatch = errors.New("no confident match found")
+
+// ISRCSearcher is implemented by providers that can look tracks up by ISRC.
+type ISRCSearcher interface {
+	SearchISRC(ctx context.Context, isrc string) ([]Track, error)
+}
+
+// TrackMatch is the best counterpart of a track on another service.
+// Confidence ranges from 0 to 1; ISRC matches are always 1.
+type TrackMatch struct {
+	Track      Track
+	Confidence float64
+	Method     string
+}
+
+// matchCandidates is how many search results are scored per match.
+const matchCandidates = 10
+
+var (
+	// bracketedRe matches "(Official Video)", "[HD]", "(feat. X)" and similar.
+	bracketedRe = regexp.MustCompile(`\([^)]*\)|\[[^\]]*\]|【[^】]*】`)
+	// featuringRe matches an unbracketed featured-artist suffix.
+	featuringRe = regexp.MustCompile(`(?i)\s(feat\.?|ft\.?|featuring)\s.*$`)
+	// versionSuffixRe matches " - Remastered 2011", " - Radio Edit" and similar.
+	versionSuffixRe = regexp.MustCompile(`(?i)\s-\s[^-]*(remaster|version|edit|mix|live|mono|stereo)[^-]*$`)
+	// channelSuffixRe matches the decorations YouTube adds to artist channels.
+	channelSuffixRe = regexp.MustCompile(`(?i)(\s-\stopic|vevo|official)$`)
+)
+
+// titleNoise are words YouTube uploads add to titles that never belong to a song name.
+var titleNoise = map[string]bool{
+	"official": true, "video": true, "audio": true, "lyrics": true, "lyric": true,
+	"music": true, "hd": true, "hq": true, "4k": true, "mv": true, "visualizer": true,
+}
+
+// MatchTrack finds the track on target that best corresponds to source.
+// When source has an ISRC and target can search by it, an exact ISRC match
+// wins; otherwise candidates from a title/artist search are scored on
+// normalized title, artist and duration similarity. Returns ErrNoMatch when
+// the best candidate scores below MinMatchConfidence.
+func MatchTrack(ctx context.Context, source Track, target MusicProvider) (*TrackMatch, error) {
+	if searcher, ok := target.(ISRCSearcher); ok && source.ISRC != "" {
+		tracks, err := searcher.SearchISRC(ctx, source.ISRC)
+		if err != nil {
+			return nil, fmt.Errorf("ISRC search failed: %w", err)
+		}
+		for _, t := range tracks {
+			if strings.EqualFold(t.ISRC, source.ISRC) {
+				return &TrackMatch{Track: t, Confidence: 1, Method: MatchMethodISRC}, nil
+			}
+		}
+	}
+
+	opts := SearchOptions{
+		Query: strings.TrimSpace(primaryArtist(source.ArtistNames) + " " + cleanTitle(source.Name)),
+		Limit: matchCandidates,
+	}
+	if target.Name() == "youtube" {
+		opts.Category = YouTubeCategoryMusic
+	}
+	page, err := target.Search(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("match search failed: %w", err)
+	}
+
+	var best *TrackMatch
+	for _, candidate := range page.Tracks {
+		score := matchScore(source, candidate)
+		if best == nil || score > best.Confidence {
+			best = &TrackMatch{Track: candidate, Confidence: score, Method: MatchMethodMetadata}
+		}
+	}
+	if best == nil || best.Confidence < MinMatchConfidence {
+		return nil, ErrNoMatch
+	}
+	return best, nil
+}
+
+// matchScore rates how likely candidate is the same recording as source,
+// weighting title 45%, artist 40% and duration 15%. Artist carries nearly as
+// much weight as title so covers of the same song fall below the threshold.
+func matchScore(source, candidate Track) float64 {
+	artistTokens := tokens(normalizeArtist(primaryArtist(source.ArtistNames)))
+	sourceTitle := tokens(cleanTitle(source.Name))
+
+	// YouTube titles often read "Artist - Title"; drop artist words the
+	// source title does not itself contain before comparing.
+	var candidateTitle []string
+	for _, t := range tokens(cleanTitle(candidate.Name)) {
+		if !titleNoise[t] && (!slices.Contains(artistTokens, t) || slices.Contains(sourceTitle, t)) {
+			candidateTitle = append(candidateTitle, t)
+		}
+	}
+	title := dice(sourceTitle, candidateTitle)
+
+	artist := dice(artistTokens, tokens(normalizeArtist(candidate.ArtistNames)))
+	if len(artistTokens) > 0 && containsAll(tokens(candidate.Name), artistTokens) {
+		artist = 1
+	}
+
+	score := 0.45*title + 0.4*artist + 0.15*durationSimilarity(source.DurationMS, candidate.DurationMS)
+	return math.Round(score*100) / 100
+}
+
+// SameSong reports whether two tracks are the same song despite differing
+// uploads or releases: titles must match once bracketed annotations
+// ("(Official Video)", "[Lyrics]"), version suffixes ("- Remastered 2011"),
+// featured artists and artist names are stripped, and the primary artist of
+// one must appear in the other's artist or title.
+func SameSong(a, b Track) bool {
+	artistA := tokens(normalizeArtist(primaryArtist(a.ArtistNames)))
+	artistB := tokens(normalizeArtist(primaryArtist(b.ArtistNames)))
+
+	titleA := songTitleTokens(a.Name, artistA, artistB)
+	titleB := songTitleTokens(b.Name, artistA, artistB)
+	if len(titleA) == 0 || !slices.Equal(titleA, titleB) {
+		return false
+	}
+
+	if len(artistA) == 0 || len(artistB) == 0 {
+		return false
+	}
+	// "DaftPunkVEVO" and "Daft Punk" are the same artist
+	if strings.Join(artistA, "") == strings.Join(artistB, "") {
+		return true
+	}
+	return containsAll(append(tokens(b.ArtistNames), tokens(b.Name)...), artistA) ||
+		containsAll(append(tokens(a.ArtistNames), tokens(a.Name)...), artistB)
+}
+
+// songTitleTokens returns the sorted words of a cleaned title, without
+// upload noise or words from either artist's name.
+func songTitleTokens(title string, artistA, artistB []string) []string {
+	var words []string
+	for _, t := range tokens(cleanTitle(title)) {
+		if !titleNoise[t] && !slices.Contains(artistA, t) && !slices.Contains(artistB, t) {
+			words = append(words, t)
+		}
+	}
+	slices.Sort(words)
+	return words
+}
+
+// durationSimilarity is 1 for durations within 3 seconds, falling to 0 at
+// 30 seconds apart. An unknown duration scores a neutral 0.5.
+func durationSimilarity(a, b int64) float64 {
+	if a <= 0 || b <= 0 {
+		return 0.5
+	}
+	diff := math.Abs(float64(a-b)) / 1000
+	switch {
+	case diff <= 3:
+		return 1
+	case diff >= 30:
+		return 0
+	default:
+		return 1 - (diff-3)/27
+	}
+}
+
+// cleanTitle strips bracketed annotations, featured artists and version
+// suffixes so only the song name remains.
+func cleanTitle(title string) string {
+	title = bracketedRe.ReplaceAllString(title, " ")
+	title = versionSuffixRe.ReplaceAllString(title, "")
+	title = featuringRe.ReplaceAllString(title, "")
+	return strings.TrimSpace(title)
+}
+
+// normalizeArtist strips YouTube channel decorations such as " - Topic" and "VEVO".
+func normalizeArtist(artist string) string {
+	return channelSuffixRe.ReplaceAllString(strings.TrimSpace(artist), "")
+}
+
+// primaryArtist returns the first of a comma-separated artist list.
+func primaryArtist(artistNames string) string {
+	first, _, _ := strings.Cut(artistNames, ",")
+	return strings.TrimSpace(first)
+}
+
+// tokens lowercases s and splits it into letter/digit words.
+func tokens(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// dice is the Sørensen–Dice coefficient of two token lists.
+func dice(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	common := 0
+	seen := make(map[string]int, len(b))
+	for _, t := range b {
+		seen[t]++
+	}
+	for _, t := range a {
+		if seen[t] > 0 {
+			seen[t]--
+			common++
+		}
+	}
+	return 2 * float64(common) / float64(len(a)+len(b))
+}
+
+// containsAll reports whether every token in want appears in list.
+func containsAll(list, want []string) bool {
+	for _, w := range want {
+		if !slices.Contains(list, w) {
+			return false
+		}
+	}
+	return true
+}
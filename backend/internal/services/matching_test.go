@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// fakeProvider is a MusicProvider returning fixed search results.
+type fakeProvider struct {
+	name     string
+	results  []Track
+	isrc     []Track
+	searches []SearchOptions
+}
+
+func (p *fakeProvider) Name() string { return p.name }
+func (p *fakeProvider) Search(_ context.Context, opts SearchOptions) (*SearchPage, error) {
+	p.searches = append(p.searches, opts)
+	return &SearchPage{Tracks: p.results}, nil
+}
+func (p *fakeProvider) GetTrack(context.Context, string) (*Track, error) {
+	return nil, ErrTrackNotFound
+}
+func (p *fakeProvider) ResolveURL(string) (string, error) { return "", ErrUnrecognizedLink }
+func (p *fakeProvider) PlaybackTarget() PlaybackTarget    { return nil }
+
+// fakeISRCProvider additionally supports ISRC lookups.
+type fakeISRCProvider struct{ fakeProvider }
+
+func (p *fakeISRCProvider) SearchISRC(context.Context, string) ([]Track, error) { return p.isrc, nil }
+
+var getLucky = Track{
+	ID:          "spotify-id",
+	Name:        "Get Lucky (feat. Pharrell Williams & Nile Rodgers) - Radio Edit",
+	ArtistNames: "Daft Punk, Pharrell Williams, Nile Rodgers",
+	DurationMS:  248000,
+	ISRC:        "USQX91300108",
+}
+
+func TestMatchScore(t *testing.T) {
+	tests := []struct {
+		name      string
+		candidate Track
+		wantMatch bool
+	}{
+		{"official video with artist in title", Track{Name: "Daft Punk - Get Lucky (Official Audio) ft. Pharrell Williams, Nile Rodgers", ArtistNames: "DaftPunkVEVO", DurationMS: 369000}, true},
+		{"topic channel", Track{Name: "Get Lucky (Radio Edit)", ArtistNames: "Daft Punk - Topic", DurationMS: 249000}, true},
+		{"cover", Track{Name: "Get Lucky", ArtistNames: "Acoustic Covers Band", DurationMS: 248000}, false},
+		{"other song by artist", Track{Name: "Daft Punk - One More Time", ArtistNames: "Daft Punk", DurationMS: 320000}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score := matchScore(getLucky, tt.candidate)
+			if (score >= MinMatchConfidence) != tt.wantMatch {
+				t.Errorf("matchScore = %.2f, want match %v", score, tt.wantMatch)
+			}
+		})
+	}
+}
+
+func TestMatchTrackPrefersISRC(t *testing.T) {
+	target := &fakeISRCProvider{fakeProvider{
+		name: "spotify",
+		isrc: []Track{{ID: "other", ISRC: "GBAYE0000001"}, {ID: "exact", ISRC: "usqx91300108"}},
+	}}
+
+	match, err := MatchTrack(context.Background(), getLucky, target)
+	if err != nil {
+		t.Fatalf("MatchTrack: %v", err)
+	}
+	if match.Track.ID != "exact" || match.Method != MatchMethodISRC || match.Confidence != 1 {
+		t.Errorf("match = %+v, want exact ISRC match", match)
+	}
+	if len(target.searches) != 0 {
+		t.Errorf("metadata searches = %d, want none after an ISRC hit", len(target.searches))
+	}
+}
+
+func TestMatchTrackByMetadata(t *testing.T) {
+	target := &fakeProvider{
+		name: "youtube",
+		results: []Track{
+			{ID: "cover", Name: "Get Lucky (Cover)", ArtistNames: "Someone Else", DurationMS: 250000},
+			{ID: "official", Name: "Daft Punk - Get Lucky (Official Video)", ArtistNames: "Daft Punk", DurationMS: 250000},
+		},
+	}
+
+	match, err := MatchTrack(context.Background(), getLucky, target)
+	if err != nil {
+		t.Fatalf("MatchTrack: %v", err)
+	}
+	if match.Track.ID != "official" || match.Method != MatchMethodMetadata {
+		t.Errorf("match = %+v, want the official video", match)
+	}
+	if got := target.searches[0]; got.Query != "Daft Punk Get Lucky" || got.Category != YouTubeCategoryMusic {
+		t.Errorf("search = %+v, want cleaned music-category query", got)
+	}
+
+	target.results = target.results[:1]
+	if _, err := MatchTrack(context.Background(), getLucky, target); !errors.Is(err, ErrNoMatch) {
+		t.Errorf("MatchTrack with only a cover = %v, want ErrNoMatch", err)
+	}
+}
+
+func TestSameSong(t *testing.T) {
+	song := Track{Name: "Bohemian Rhapsody", ArtistNames: "Queen"}
+	tests := []struct {
+		name  string
+		other Track
+		want  bool
+	}{
+		{"remaster", Track{Name: "Bohemian Rhapsody - Remastered 2011", ArtistNames: "Queen"}, true},
+		{"official video upload", Track{Name: "Queen – Bohemian Rhapsody (Official Video Remastered)", ArtistNames: "Queen Official"}, true},
+		{"lyrics video on another channel", Track{Name: "Queen - Bohemian Rhapsody Lyrics", ArtistNames: "Lyric Vault"}, true},
+		{"vevo channel", Track{Name: "Bohemian Rhapsody [HD]", ArtistNames: "QueenVEVO"}, true},
+		{"featured credit", Track{Name: "Bohemian Rhapsody feat. Someone", ArtistNames: "Queen, Someone"}, true},
+		{"cover", Track{Name: "Bohemian Rhapsody", ArtistNames: "Panic! At The Disco"}, false},
+		{"different song", Track{Name: "Don't Stop Me Now", ArtistNames: "Queen"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SameSong(song, tt.other); got != tt.want {
+				t.Errorf("SameSong(%q by %q) = %v, want %v", tt.other.Name, tt.other.ArtistNames, got, tt.want)
+			}
+		})
+	}
+}
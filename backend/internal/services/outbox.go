@@ -0,0 +1,315 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/songify/backend/internal/db"
+)
+
+// Playback effects stored in playback_outbox.effect.
+const (
+	EffectEnqueue = "enqueue"  // PlaybackTarget.Enqueue
+	EffectPlayNow = "play_now" // PlaybackTarget.PlayNow
+	EffectRemove  = "remove"   // PlaybackTarget.Remove
+)
+
+// Delivery statuses stored in song_requests.delivery_status.
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryFailed    = "failed"
+)
+
+const (
+	// OutboxMaxAttempts is how many times an effect is tried before it is
+	// marked failed.
+	OutboxMaxAttempts = 5
+	// outboxRetryBaseDelay is the wait after the first failed attempt,
+	// doubling with each further one.
+	outboxRetryBaseDelay = 2 * time.Second
+	// outboxClaimTimeout is how long an attempt may run before another
+	// instance retries it, e.g. after a crash mid-delivery.
+	outboxClaimTimeout = time.Minute
+	// outboxDeliveryTimeout bounds a single attempt.
+	outboxDeliveryTimeout = 30 * time.Second
+	// outboxWorkers bounds how many sessions are delivered to at once, so a
+	// slow TV holds up only its own session.
+	outboxWorkers = 8
+)
+
+// errNotConnected means the session's playback target cannot take songs
+// right now; the session's effects wait until it reconnects.
+var errNotConnected = errors.New("playback target not connected")
+
+// QueueEffect records a playback effect for a song request, to be delivered
+// by an Outbox. Pass queries bound to the transaction that changes the
+// request so both commit together. Enqueued and played songs are marked as
+// pending delivery on the request.
+func QueueEffect(ctx context.Context, q db.Querier, songRequest db.SongRequest, effect string) error {
+	if _, err := q.CreateOutboxEntry(ctx, db.CreateOutboxEntryParams{
+		SessionID: songRequest.SessionID,
+		RequestID: sql.NullInt64{Int64: songRequest.ID, Valid: true},
+		Effect:    effect,
+		TrackID:   songRequest.ExternalTrackID,
+	}); err != nil {
+		return fmt.Errorf("failed to queue %s: %w", effect, err)
+	}
+	if effect == EffectRemove {
+		return nil
+	}
+	if err := q.SetSongRequestDelivery(ctx, db.SetSongRequestDeliveryParams{
+		DeliveryStatus: sql.NullString{String: DeliveryPending, Valid: true},
+		ID:             songRequest.ID,
+	}); err != nil {
+		return fmt.Errorf("failed to update delivery status: %w", err)
+	}
+	return nil
+}
+
+// Outbox delivers queued playback effects to their session's playback
+// target. Each session's effects are delivered in the order they were
+// queued; a failing one is retried with backoff and holds back the ones
+// behind it until it succeeds or runs out of attempts. While the target is
+// disconnected the session's effects are held without using up attempts. Entries are claimed
+// in the database, so several instances can run an Outbox safely.
+type Outbox struct {
+	queries   db.Querier
+	providers *ProviderRegistry
+	notify    func(sessionID string)
+	interval  time.Duration
+	now       func() time.Time
+}
+
+// NewOutbox creates an Outbox that checks for due effects every interval and
+// calls notify with the session ID whenever a request's delivery status
+// changes.
+func NewOutbox(queries db.Querier, providers *ProviderRegistry, interval time.Duration, notify func(sessionID string)) *Outbox {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	return &Outbox{
+		queries:   queries,
+		providers: providers,
+		notify:    notify,
+		interval:  interval,
+		now:       time.Now,
+	}
+}
+
+// Run delivers due effects until ctx is cancelled.
+func (o *Outbox) Run(ctx context.Context) {
+	ticker := time.NewTicker(o.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			o.Tick(ctx)
+		}
+	}
+}
+
+// Tick attempts every due effect that is not held back by an earlier one
+// for the same session. Sessions are delivered to in parallel, up to
+// outboxWorkers at a time, each by a single worker so its effects stay in
+// order. Tick returns once every session has been handled.
+func (o *Outbox) Tick(ctx context.Context) {
+	entries, err := o.queries.GetPendingOutboxEntries(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "outbox: failed to load pending effects", slog.String("error", err.Error()))
+		return
+	}
+
+	var sessionIDs []string
+	bySession := make(map[string][]db.PlaybackOutbox)
+	for _, entry := range entries {
+		if _, ok := bySession[entry.SessionID]; !ok {
+			sessionIDs = append(sessionIDs, entry.SessionID)
+		}
+		bySession[entry.SessionID] = append(bySession[entry.SessionID], entry)
+	}
+
+	work := make(chan []db.PlaybackOutbox)
+	var wg sync.WaitGroup
+	for range min(outboxWorkers, len(sessionIDs)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for sessionEntries := range work {
+				o.deliverSession(ctx, sessionEntries)
+			}
+		}()
+	}
+	for _, id := range sessionIDs {
+		work <- bySession[id]
+	}
+	close(work)
+	wg.Wait()
+}
+
+// deliverSession attempts one session's due effects in queue order, stopping
+// at the first one that is held back.
+func (o *Outbox) deliverSession(ctx context.Context, entries []db.PlaybackOutbox) {
+	for _, entry := range entries {
+		if !o.attempt(ctx, entry) {
+			return
+		}
+	}
+}
+
+// attempt claims and delivers an entry if it is due. It reports whether the
+// entry is out of the way of later ones for its session.
+func (o *Outbox) attempt(ctx context.Context, entry db.PlaybackOutbox) bool {
+	now := o.now()
+	if entry.NextAttemptAt.Valid && entry.NextAttemptAt.Time.After(now) {
+		return false
+	}
+	target, targetErr := o.target(ctx, entry.SessionID)
+	if errors.Is(targetErr, errNotConnected) {
+		return false // Held until the target reconnects
+	}
+	claimed, err := o.queries.ClaimOutboxEntry(ctx, db.ClaimOutboxEntryParams{
+		NextAttemptAt: sql.NullTime{Time: now.Add(outboxClaimTimeout), Valid: true},
+		ID:            entry.ID,
+		Attempts:      entry.Attempts,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "outbox: failed to claim effect", slog.Int64("outbox_id", entry.ID), slog.String("error", err.Error()))
+		return false
+	}
+	if claimed == 0 {
+		return false // Another instance is delivering it
+	}
+	entry.Attempts++
+
+	deliverCtx, cancel := context.WithTimeout(ctx, outboxDeliveryTimeout)
+	defer cancel()
+	err = targetErr
+	if err == nil {
+		err = deliver(deliverCtx, target, entry)
+	}
+	if err != nil {
+		return o.fail(ctx, entry, err)
+	}
+	o.delivered(ctx, target, entry)
+	return true
+}
+
+// target returns the connected playback target for a session.
+func (o *Outbox) target(ctx context.Context, sessionID string) (PlaybackTarget, error) {
+	session, err := o.queries.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("session not found: %w", err)
+	}
+	provider, err := o.providers.Get(session.MusicService)
+	if err != nil {
+		return nil, err
+	}
+	target := provider.PlaybackTarget()
+	if target == nil {
+		return nil, fmt.Errorf("%s sessions have no playback target", session.MusicService)
+	}
+	if !target.IsConnected(sessionID) {
+		return nil, errNotConnected
+	}
+	return target, nil
+}
+
+// deliver applies an effect to the playback target.
+func deliver(ctx context.Context, target PlaybackTarget, entry db.PlaybackOutbox) error {
+	switch entry.Effect {
+	case EffectEnqueue:
+		return target.Enqueue(ctx, entry.SessionID, entry.TrackID)
+	case EffectPlayNow:
+		return target.PlayNow(ctx, entry.SessionID, entry.TrackID)
+	case EffectRemove:
+		return target.Remove(ctx, entry.SessionID, entry.TrackID)
+	}
+	return fmt.Errorf("unknown effect %q", entry.Effect)
+}
+
+// delivered records a successful delivery. If the entry was cancelled while
+// it was being delivered, an enqueued song is taken back out again.
+func (o *Outbox) delivered(ctx context.Context, target PlaybackTarget, entry db.PlaybackOutbox) {
+	n, err := o.queries.MarkOutboxEntryDelivered(ctx, entry.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "outbox: failed to mark effect delivered", slog.Int64("outbox_id", entry.ID), slog.String("error", err.Error()))
+		return
+	}
+	if n == 0 {
+		slog.InfoContext(ctx, "outbox: effect cancelled during delivery, reverting",
+			slog.String("session_id", entry.SessionID),
+			slog.Int64("outbox_id", entry.ID),
+		)
+		if entry.Effect == EffectEnqueue {
+			if err := target.Remove(ctx, entry.SessionID, entry.TrackID); err != nil {
+				slog.WarnContext(ctx, "outbox: failed to remove cancelled song", slog.Int64("outbox_id", entry.ID), slog.String("error", err.Error()))
+			}
+		}
+		return
+	}
+	o.setDelivery(ctx, entry, DeliveryDelivered, nil)
+}
+
+// fail schedules a retry of a failed attempt, or marks the entry failed once
+// it is out of attempts. It reports whether the entry was marked failed.
+func (o *Outbox) fail(ctx context.Context, entry db.PlaybackOutbox, deliverErr error) bool {
+	msg := sql.NullString{String: deliverErr.Error(), Valid: true}
+	logger := slog.With(
+		slog.String("session_id", entry.SessionID),
+		slog.Int64("outbox_id", entry.ID),
+		slog.String("effect", entry.Effect),
+		slog.Int64("attempt", entry.Attempts),
+		slog.String("error", deliverErr.Error()),
+	)
+
+	if entry.Attempts < OutboxMaxAttempts {
+		logger.WarnContext(ctx, "outbox: delivery failed, will retry")
+		if err := o.queries.RetryOutboxEntry(ctx, db.RetryOutboxEntryParams{
+			Error:         msg,
+			NextAttemptAt: sql.NullTime{Time: o.now().Add(outboxRetryBaseDelay << (entry.Attempts - 1)), Valid: true},
+			ID:            entry.ID,
+		}); err != nil {
+			slog.ErrorContext(ctx, "outbox: failed to schedule retry", slog.Int64("outbox_id", entry.ID), slog.String("error", err.Error()))
+		}
+		o.setDelivery(ctx, entry, DeliveryPending, deliverErr)
+		return false
+	}
+
+	logger.ErrorContext(ctx, "outbox: delivery failed, giving up")
+	if err := o.queries.MarkOutboxEntryFailed(ctx, db.MarkOutboxEntryFailedParams{Error: msg, ID: entry.ID}); err != nil {
+		slog.ErrorContext(ctx, "outbox: failed to mark effect failed", slog.Int64("outbox_id", entry.ID), slog.String("error", err.Error()))
+	}
+	o.setDelivery(ctx, entry, DeliveryFailed, deliverErr)
+	return true
+}
+
+// setDelivery records the progress of an enqueue or play on its request,
+// with the last error if any, and notifies the session. Removals leave the
+// request alone.
+func (o *Outbox) setDelivery(ctx context.Context, entry db.PlaybackOutbox, status string, deliverErr error) {
+	if entry.Effect == EffectRemove || !entry.RequestID.Valid {
+		return
+	}
+	var msg sql.NullString
+	if deliverErr != nil {
+		msg = sql.NullString{String: deliverErr.Error(), Valid: true}
+	}
+	if err := o.queries.SetSongRequestDelivery(ctx, db.SetSongRequestDeliveryParams{
+		DeliveryStatus: sql.NullString{String: status, Valid: true},
+		DeliveryError:  msg,
+		ID:             entry.RequestID.Int64,
+	}); err != nil {
+		slog.ErrorContext(ctx, "outbox: failed to update delivery status", slog.Int64("request_id", entry.RequestID.Int64), slog.String("error", err.Error()))
+	}
+	if o.notify != nil {
+		o.notify(entry.SessionID)
+	}
+}
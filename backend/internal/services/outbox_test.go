@@ -0,0 +1,192 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/songify/backend/internal/database"
+	"github.com/songify/backend/internal/database/dbtest"
+	"github.com/songify/backend/internal/db"
+)
+
+func TestOutboxRetries(t *testing.T) {
+	ctx := context.Background()
+	queries := database.NewQueries(dbtest.New(t))
+
+	dbtest.Session(t, queries, "s1")
+	queue := func(track string) db.SongRequest {
+		t.Helper()
+		sr := dbtest.SongRequests(t, queries, "s1", track)[0]
+		if err := QueueEffect(ctx, queries, sr, EffectEnqueue); err != nil {
+			t.Fatal(err)
+		}
+		return sr
+	}
+	delivery := func(sr db.SongRequest) string {
+		t.Helper()
+		got, err := queries.GetSongRequestByID(ctx, sr.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return got.DeliveryStatus.String
+	}
+
+	screen := &fakeScreen{failures: 1}
+	var notified int
+	o := NewOutbox(queries, NewProviderRegistry(fakeScreenProvider{target: screen}), time.Second, func(string) { notified++ })
+	now := time.Date(2026, 6, 1, 20, 0, 0, 0, time.UTC)
+	o.now = func() time.Time { return now }
+
+	first, second := queue("first"), queue("second")
+
+	// The first song fails and holds back the second until its retry
+	o.Tick(ctx)
+	if len(screen.queued) != 0 || delivery(first) != DeliveryPending || delivery(second) != DeliveryPending {
+		t.Fatalf("after failure: queued %v, deliveries %s/%s", screen.queued, delivery(first), delivery(second))
+	}
+	o.Tick(ctx)
+	if len(screen.queued) != 0 {
+		t.Fatalf("retried before the backoff: queued %v", screen.queued)
+	}
+
+	now = now.Add(outboxRetryBaseDelay)
+	o.Tick(ctx)
+	if fmt.Sprint(screen.queued) != "[first second]" {
+		t.Errorf("queued = %v, want [first second]", screen.queued)
+	}
+	if delivery(first) != DeliveryDelivered || delivery(second) != DeliveryDelivered {
+		t.Errorf("deliveries = %s/%s, want delivered", delivery(first), delivery(second))
+	}
+	if notified != 3 {
+		t.Errorf("notified %d times, want once for the retry and once per delivery", notified)
+	}
+
+	// A song that never gets through is marked failed and stops blocking
+	screen.failures = OutboxMaxAttempts
+	stuck, next := queue("stuck"), queue("next")
+	for range OutboxMaxAttempts {
+		o.Tick(ctx)
+		now = now.Add(time.Hour)
+	}
+	if delivery(stuck) != DeliveryFailed {
+		t.Errorf("stuck delivery = %s, want failed", delivery(stuck))
+	}
+	o.Tick(ctx)
+	if delivery(next) != DeliveryDelivered {
+		t.Errorf("next delivery = %s, want delivered", delivery(next))
+	}
+
+	// Cancelled effects are never delivered
+	cancelled := queue("cancelled")
+	if _, err := queries.CancelOutboxEntries(ctx, sql.NullInt64{Int64: cancelled.ID, Valid: true}); err != nil {
+		t.Fatal(err)
+	}
+	o.Tick(ctx)
+	if fmt.Sprint(screen.queued) != "[first second next]" {
+		t.Errorf("queued = %v, want the cancelled song left out", screen.queued)
+	}
+}
+
+func TestOutboxWaitsForDisconnectedTarget(t *testing.T) {
+	ctx := context.Background()
+	queries := database.NewQueries(dbtest.New(t))
+
+	dbtest.Session(t, queries, "s1")
+	sr := dbtest.SongRequests(t, queries, "s1", "a")[0]
+	if err := QueueEffect(ctx, queries, sr, EffectEnqueue); err != nil {
+		t.Fatal(err)
+	}
+
+	screen := &fakeScreen{offline: true}
+	o := NewOutbox(queries, NewProviderRegistry(fakeScreenProvider{target: screen}), time.Second, nil)
+	now := time.Date(2026, 6, 1, 20, 0, 0, 0, time.UTC)
+	o.now = func() time.Time { return now }
+
+	// Far more ticks than attempts, spread over longer than the backoff
+	for range 2 * OutboxMaxAttempts {
+		o.Tick(ctx)
+		now = now.Add(time.Hour)
+	}
+	got, err := queries.GetSongRequestByID(ctx, sr.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.DeliveryStatus.String != DeliveryPending {
+		t.Fatalf("delivery while offline = %s, want pending", got.DeliveryStatus.String)
+	}
+
+	screen.offline = false
+	o.Tick(ctx)
+	if fmt.Sprint(screen.queued) != "[a]" {
+		t.Errorf("queued = %v after reconnecting, want [a]", screen.queued)
+	}
+	if got, _ := queries.GetSongRequestByID(ctx, sr.ID); got.DeliveryStatus.String != DeliveryDelivered {
+		t.Errorf("delivery after reconnecting = %s, want delivered", got.DeliveryStatus.String)
+	}
+}
+
+// blockingScreen holds deliveries to one session until released.
+type blockingScreen struct {
+	*fakeScreen
+	session string
+	release chan struct{}
+}
+
+func (b blockingScreen) Enqueue(ctx context.Context, sessionID, trackID string) error {
+	if sessionID == b.session {
+		<-b.release
+	}
+	return b.fakeScreen.Enqueue(ctx, sessionID, trackID)
+}
+
+type blockingScreenProvider struct {
+	MusicProvider
+	target blockingScreen
+}
+
+func (p blockingScreenProvider) Name() string                   { return "youtube" }
+func (p blockingScreenProvider) PlaybackTarget() PlaybackTarget { return p.target }
+
+func TestOutboxSessionsDoNotBlockEachOther(t *testing.T) {
+	ctx := context.Background()
+	queries := database.NewQueries(dbtest.New(t))
+
+	for _, id := range []string{"slow", "fast"} {
+		dbtest.Session(t, queries, id)
+		sr := dbtest.SongRequests(t, queries, id, id)[0]
+		if err := QueueEffect(ctx, queries, sr, EffectEnqueue); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	screen := blockingScreen{fakeScreen: &fakeScreen{}, session: "slow", release: make(chan struct{})}
+	o := NewOutbox(queries, NewProviderRegistry(blockingScreenProvider{target: screen}), time.Second, nil)
+
+	done := make(chan struct{})
+	go func() {
+		o.Tick(ctx)
+		close(done)
+	}()
+
+	// The fast session's song gets through while the slow TV is stuck
+	queued := func() string {
+		screen.mu.Lock()
+		defer screen.mu.Unlock()
+		return fmt.Sprint(screen.queued)
+	}
+	for deadline := time.Now().Add(5 * time.Second); queued() != "[fast]"; time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			close(screen.release)
+			t.Fatalf("queued = %s while the slow session was blocked, want [fast]", queued())
+		}
+	}
+
+	close(screen.release)
+	<-done
+	if got := queued(); got != "[fast slow]" {
+		t.Errorf("queued = %s, want [fast slow]", got)
+	}
+}
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrInvalidSearch is returned when search options are not valid for a provider.
+	ErrInvalidSearch = errors.New("invalid search")
+	// ErrTrackNotFound is returned when a provider has no track with the given ID.
+	ErrTrackNotFound = errors.New("track not found")
+	// ErrUnrecognizedLink is returned when a URL does not belong to a provider.
+	ErrUnrecognizedLink = errors.New("link not recognized")
+	// ErrLinkNotTrack is returned when a URL points to a playlist, album or
+	// other collection instead of a single track.
+	ErrLinkNotTrack = errors.New("link points to a playlist or album, not a single song")
+)
+
+// Track is a provider-neutral song in the shape stored on a song request.
+type Track struct {
+	ID          string
+	Name        string
+	ArtistNames string // Comma-separated
+	AlbumName   string
+	AlbumArtURL string
+	DurationMS  int64
+	URI         string // Spotify URI, YouTube URL, etc.
+	ISRC        string // Empty when the service does not expose one
+}
+
+// SearchOptions is a provider-neutral search. Providers ignore filters they
+// do not support: Spotify pages by Offset and filters by Artist/Album/Year;
+// YouTube pages by PageToken and filters by Category/Duration.
+type SearchOptions struct {
+	Query     string
+	Limit     int
+	Offset    int
+	PageToken string
+	Artist    string
+	Album     string
+	Year      string
+	Category  string
+	Duration  string
+}
+
+// SearchPage is one page of provider-neutral search results.
+// NextOffset or NextPageToken is set when more results are available.
+type SearchPage struct {
+	Tracks        []Track
+	NextOffset    *int
+	NextPageToken string
+}
+
+// PlaybackTarget receives approved songs on the server side, such as a TV
+// paired through the YouTube Lounge API.
+type PlaybackTarget interface {
+	// IsConnected reports whether songs for the session can be delivered.
+	IsConnected(sessionID string) bool
+	// Enqueue appends a track to the end of the session's play queue.
+	Enqueue(ctx context.Context, sessionID, trackID string) error
+	// PlayNow interrupts playback to play a track immediately.
+	PlayNow(ctx context.Context, sessionID, trackID string) error
+	// Remove takes a track back out of the session's play queue. Removing a
+	// track that is not queued is not an error.
+	Remove(ctx context.Context, sessionID, trackID string) error
+}
+
+// TrackMetadata describes a song beyond its name and artists. ReleaseYear is
+// 0 when unknown; Popularity ranges from 0 to 100.
+type TrackMetadata struct {
+	Genres      []string // Genres of all the track's artists
+	ReleaseYear int
+	Popularity  int
+}
+
+// MetadataSource is implemented by providers that can describe a track's
+// genres, release year and popularity.
+type MetadataSource interface {
+	// TrackMetadata looks up a track's metadata by its provider ID. Returns
+	// ErrTrackNotFound when the track does not exist.
+	TrackMetadata(ctx context.Context, trackID string) (*TrackMetadata, error)
+}
+
+// MaxPlaylistTracks caps how many entries are read from a playlist.
+const MaxPlaylistTracks = 200
+
+// PlaylistSource is implemented by providers that can list a playlist's
+// tracks, such as a session's fallback playlist.
+type PlaylistSource interface {
+	// PlaylistTracks returns up to MaxPlaylistTracks tracks in playlist
+	// order. Returns ErrTrackNotFound when the playlist does not exist.
+	PlaylistTracks(ctx context.Context, playlistID string) ([]Track, error)
+}
+
+// MusicProvider is a music service a session can request songs from.
+type MusicProvider interface {
+	// Name is the value stored in sessions.music_service.
+	Name() string
+	// Search returns a page of tracks matching opts.
+	Search(ctx context.Context, opts SearchOptions) (*SearchPage, error)
+	// GetTrack looks up a single track by its provider ID.
+	GetTrack(ctx context.Context, id string) (*Track, error)
+	// ResolveURL extracts a track ID from a share link or URI. Returns
+	// ErrUnrecognizedLink for other services' links and ErrLinkNotTrack for
+	// collections.
+	ResolveURL(rawURL string) (string, error)
+	// PlaybackTarget returns where approved songs are sent, or nil when
+	// playback is handled by the client (e.g. a Spotify playlist).
+	PlaybackTarget() PlaybackTarget
+}
+
+// ProviderRegistry maps music_service names to providers.
+type ProviderRegistry struct {
+	providers map[string]MusicProvider
+	names     []string
+}
+
+// NewProviderRegistry creates a registry of the given providers. The first
+// provider is the default for new sessions.
+func NewProviderRegistry(providers ...MusicProvider) *ProviderRegistry {
+	r := &ProviderRegistry{providers: make(map[string]MusicProvider, len(providers))}
+	for _, p := range providers {
+		r.providers[p.Name()] = p
+		r.names = append(r.names, p.Name())
+	}
+	return r
+}
+
+// Get returns the provider for a music_service name.
+func (r *ProviderRegistry) Get(name string) (MusicProvider, error) {
+	p, ok := r.providers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown music service %q", name)
+	}
+	return p, nil
+}
+
+// Default returns the name of the provider used when a session does not choose one.
+func (r *ProviderRegistry) Default() string {
+	if len(r.names) == 0 {
+		return ""
+	}
+	return r.names[0]
+}
+
+// Names returns the registered provider names in registration order.
+func (r *ProviderRegistry) Names() []string {
+	return append([]string(nil), r.names...)
+}
+
+// Providers returns the registered providers in registration order.
+func (r *ProviderRegistry) Providers() []MusicProvider {
+	providers := make([]MusicProvider, len(r.names))
+	for i, name := range r.names {
+		providers[i] = r.providers[name]
+	}
+	return providers
+}
+
+// joinArtists formats artist names the way song requests store them.
+func joinArtists(names []string) string {
+	return strings.Join(names, ", ")
+}
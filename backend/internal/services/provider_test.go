@@ -0,0 +1,86 @@
+package services
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSpotifyProviderResolveURL(t *testing.T) {
+	p := NewSpotifyProvider(nil)
+	tests := []struct {
+		name    string
+		url     string
+		want    string
+		wantErr error
+	}{
+		{"share link", "https://open.spotify.com/track/4uLU6hMCjMI75M1A2tKUQC?si=abc", "4uLU6hMCjMI75M1A2tKUQC", nil},
+		{"localized link", "https://open.spotify.com/intl-de/track/4uLU6hMCjMI75M1A2tKUQC", "4uLU6hMCjMI75M1A2tKUQC", nil},
+		{"no scheme", "open.spotify.com/track/4uLU6hMCjMI75M1A2tKUQC", "4uLU6hMCjMI75M1A2tKUQC", nil},
+		{"uri", "spotify:track:4uLU6hMCjMI75M1A2tKUQC", "4uLU6hMCjMI75M1A2tKUQC", nil},
+		{"playlist", "https://open.spotify.com/playlist/37i9dQZF1DXcBWIGoYBM5M", "", ErrLinkNotTrack},
+		{"album uri", "spotify:album:4aawyAB9vmqN3uQ7FjRGTy", "", ErrLinkNotTrack},
+		{"youtube link", "https://youtu.be/dQw4w9WgXcQ", "", ErrUnrecognizedLink},
+		{"bad id", "https://open.spotify.com/track/nope", "", ErrUnrecognizedLink},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := p.ResolveURL(tt.url)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ResolveURL(%q) error = %v, want %v", tt.url, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveURL(%q) = %q, want %q", tt.url, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestYouTubeProviderResolveURL(t *testing.T) {
+	p := NewYouTubeProvider(nil, nil)
+	tests := []struct {
+		name    string
+		url     string
+		want    string
+		wantErr error
+	}{
+		{"watch", "https://www.youtube.com/watch?v=dQw4w9WgXcQ&t=42", "dQw4w9WgXcQ", nil},
+		{"watch in playlist", "https://www.youtube.com/watch?v=dQw4w9WgXcQ&list=PL123", "dQw4w9WgXcQ", nil},
+		{"short link", "https://youtu.be/dQw4w9WgXcQ?si=xyz", "dQw4w9WgXcQ", nil},
+		{"music", "https://music.youtube.com/watch?v=dQw4w9WgXcQ", "dQw4w9WgXcQ", nil},
+		{"mobile shorts", "m.youtube.com/shorts/dQw4w9WgXcQ", "dQw4w9WgXcQ", nil},
+		{"playlist", "https://www.youtube.com/playlist?list=PL123", "", ErrLinkNotTrack},
+		{"album", "https://music.youtube.com/browse/MPREb_abc", "", ErrLinkNotTrack},
+		{"spotify link", "https://open.spotify.com/track/4uLU6hMCjMI75M1A2tKUQC", "", ErrUnrecognizedLink},
+		{"bad id", "https://youtu.be/short", "", ErrUnrecognizedLink},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := p.ResolveURL(tt.url)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ResolveURL(%q) error = %v, want %v", tt.url, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveURL(%q) = %q, want %q", tt.url, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProviderRegistry(t *testing.T) {
+	r := NewProviderRegistry(NewSpotifyProvider(nil), NewYouTubeProvider(nil, nil))
+
+	if got := r.Default(); got != "spotify" {
+		t.Errorf("Default() = %q, want spotify", got)
+	}
+	if p, err := r.Get("youtube"); err != nil || p.Name() != "youtube" {
+		t.Errorf("Get(youtube) = %v, %v", p, err)
+	}
+	if _, err := r.Get("tidal"); err == nil {
+		t.Error("Get(tidal) should fail for an unregistered provider")
+	}
+	if names := r.Names(); len(names) != 2 || names[1] != "youtube" {
+		t.Errorf("Names() = %v, want [spotify youtube]", names)
+	}
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	// youtubeSearchCost is the quota cost of a search.list call.
+	youtubeSearchCost = 100
+	// youtubeVideosCost is the quota cost of a videos.list call.
+	youtubeVideosCost = 1
+	// youtubePlaylistItemsCost is the quota cost of a playlistItems.list call.
+	youtubePlaylistItemsCost = 1
+)
+
+// ErrQuotaExhausted is returned when a call would exceed the daily API budget.
+var ErrQuotaExhausted = errors.New("daily YouTube search quota exhausted, try again after midnight Pacific time")
+
+// QuotaMeter tracks API quota units spent per day against a fixed budget.
+// Past a soft limit below the budget, callers should stop optional spending
+// (such as new searches) so the rest is left for calls that matter more.
+// YouTube resets quotas at midnight Pacific time, so the day boundary is
+// computed in that zone. QuotaMeter is safe for concurrent use.
+type QuotaMeter struct {
+	mu        sync.Mutex
+	budget    int
+	softLimit int
+	used      int
+	day       time.Time // start of the current quota day
+	loc       *time.Location
+	now       func() time.Time
+}
+
+// QuotaUsage is a snapshot of a QuotaMeter.
+type QuotaUsage struct {
+	Used      int
+	Budget    int
+	SoftLimit int
+	ResetsAt  time.Time
+}
+
+// NewQuotaMeter creates a QuotaMeter with the given daily budget and soft
+// limit in units. A soft limit at or above the budget disables it.
+func NewQuotaMeter(budget, softLimit int) *QuotaMeter {
+	loc, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		loc = time.FixedZone("PST", -8*60*60)
+	}
+	return &QuotaMeter{budget: budget, softLimit: min(softLimit, budget), loc: loc, now: time.Now}
+}
+
+// Reserve records units as spent if they fit in today's remaining budget.
+// Returns ErrQuotaExhausted without spending anything otherwise.
+func (q *QuotaMeter) Reserve(units int) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.rollover()
+	if q.used+units > q.budget {
+		return ErrQuotaExhausted
+	}
+	q.used += units
+	return nil
+}
+
+// Low reports whether today's spend has reached the soft limit.
+func (q *QuotaMeter) Low() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.rollover()
+	return q.used >= q.softLimit
+}
+
+// Exhaust marks today's budget as fully spent. Used when the API itself
+// reports the quota as exceeded, so we stop calling it until the reset.
+func (q *QuotaMeter) Exhaust() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.rollover()
+	q.used = q.budget
+}
+
+// Usage returns today's spend and when the budget next resets.
+func (q *QuotaMeter) Usage() QuotaUsage {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.rollover()
+	return QuotaUsage{Used: q.used, Budget: q.budget, SoftLimit: q.softLimit, ResetsAt: q.day.AddDate(0, 0, 1)}
+}
+
+// rollover resets usage when the quota day has changed. Lock must be held.
+func (q *QuotaMeter) rollover() {
+	now := q.now().In(q.loc)
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, q.loc)
+	if !day.Equal(q.day) {
+		q.day = day
+		q.used = 0
+	}
+}
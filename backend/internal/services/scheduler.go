@@ -0,0 +1,275 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/songify/backend/internal/db"
+)
+
+// Scheduled play modes stored in scheduled_plays.mode.
+const (
+	ScheduleAt           = "at"            // Play at a wall-clock time
+	ScheduleAfterCurrent = "after_current" // Play when the current track ends
+)
+
+// Scheduled play statuses stored in scheduled_plays.status.
+const (
+	ScheduleStatusScheduled = "scheduled"
+	ScheduleStatusFired     = "fired"
+	ScheduleStatusFailed    = "failed"
+	ScheduleStatusCancelled = "cancelled"
+)
+
+// ScheduleGracePeriod is how late a timed play may still fire, e.g. after a
+// restart. Later ones are marked failed instead of interrupting the party.
+const ScheduleGracePeriod = 5 * time.Minute
+
+// errScheduleWaiting means a scheduled play is not ready to fire yet.
+var errScheduleWaiting = errors.New("waiting")
+
+// Scheduler plays scheduled requests on their session's playback target and,
+// with an AutoDJ, keeps idle players going from their fallback playlist.
+// Schedules live in the database, so pending ones resume after a restart.
+type Scheduler struct {
+	queries   db.Querier
+	providers *ProviderRegistry
+	autoDJ    *AutoDJ
+	notify    func(sessionID string)
+	interval  time.Duration
+	now       func() time.Time
+
+	mu sync.Mutex
+	// firedAt is when a play last fired per session, so after_current plays
+	// wait for the player to report the new track.
+	firedAt map[string]time.Time
+}
+
+// NewScheduler creates a Scheduler that checks for due plays every interval
+// and calls notify with the session ID whenever a play fires or fails.
+// autoDJ may be nil to disable fallback playlists.
+func NewScheduler(queries db.Querier, providers *ProviderRegistry, autoDJ *AutoDJ, interval time.Duration, notify func(sessionID string)) *Scheduler {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	return &Scheduler{
+		queries:   queries,
+		providers: providers,
+		autoDJ:    autoDJ,
+		notify:    notify,
+		interval:  interval,
+		now:       time.Now,
+		firedAt:   make(map[string]time.Time),
+	}
+}
+
+// Run checks for due plays until ctx is cancelled.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.Tick(ctx)
+		}
+	}
+}
+
+// Tick fires every scheduled play that is due, then fills idle players from
+// their fallback playlist.
+func (s *Scheduler) Tick(ctx context.Context) {
+	plays, err := s.queries.GetScheduledPlaysByStatus(ctx, ScheduleStatusScheduled)
+	if err != nil {
+		slog.ErrorContext(ctx, "scheduler: failed to load scheduled plays", slog.String("error", err.Error()))
+		return
+	}
+
+	// Timed plays go first so an after_current play never cuts off one
+	// that fires in the same tick
+	for _, mode := range []string{ScheduleAt, ScheduleAfterCurrent} {
+		for _, play := range plays {
+			if play.Mode != mode {
+				continue
+			}
+			err := s.fire(ctx, play)
+			if errors.Is(err, errScheduleWaiting) {
+				continue
+			}
+			if err == nil {
+				s.mu.Lock()
+				s.firedAt[play.SessionID] = s.now()
+				s.mu.Unlock()
+			}
+			s.finish(ctx, play, err)
+		}
+	}
+
+	if s.autoDJ != nil {
+		s.fillIdle(ctx)
+	}
+}
+
+// fillIdle plays the next fallback track on every session whose player has
+// run out of songs.
+func (s *Scheduler) fillIdle(ctx context.Context) {
+	sessions, err := s.queries.ListSessionsWithFallback(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "scheduler: failed to load fallback playlists", slog.String("error", err.Error()))
+		return
+	}
+
+	for _, session := range sessions {
+		err := s.playFallback(ctx, session)
+		if errors.Is(err, errScheduleWaiting) {
+			continue
+		}
+		// Successful or not, give the player time before the next pick
+		s.mu.Lock()
+		s.firedAt[session.ID] = s.now()
+		s.mu.Unlock()
+		if err != nil {
+			slog.WarnContext(ctx, "scheduler: fallback playlist failed",
+				slog.String("session_id", session.ID),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+}
+
+// playFallback plays the session's next fallback track if its player has been
+// idle for FallbackIdleDelay. Players that cannot report playback are never
+// filled, as an empty queue cannot be told apart from a playing one.
+func (s *Scheduler) playFallback(ctx context.Context, session db.Session) error {
+	provider, err := s.providers.Get(session.MusicService)
+	if err != nil {
+		return errScheduleWaiting
+	}
+	target := provider.PlaybackTarget()
+	monitor, ok := target.(PlaybackMonitor)
+	if !ok || !target.IsConnected(session.ID) {
+		return errScheduleWaiting
+	}
+	current, known := monitor.NowPlaying(session.ID)
+	now := s.now()
+	if !known || !current.Idle() || now.Sub(current.UpdatedAt) < FallbackIdleDelay {
+		return errScheduleWaiting
+	}
+
+	// Give the last play time to start before adding another
+	s.mu.Lock()
+	firedAt, fired := s.firedAt[session.ID]
+	s.mu.Unlock()
+	if fired && !current.UpdatedAt.After(firedAt) && now.Sub(firedAt) < fallbackRetryDelay {
+		return errScheduleWaiting
+	}
+
+	track, err := s.autoDJ.Next(ctx, session)
+	if err != nil {
+		return err
+	}
+	slog.InfoContext(ctx, "scheduler: playing from fallback playlist",
+		slog.String("session_id", session.ID),
+		slog.String("track_id", track.ID),
+	)
+	return target.PlayNow(ctx, session.ID, track.ID)
+}
+
+// fire plays a scheduled request if it is due. It returns errScheduleWaiting
+// when the play should stay scheduled.
+func (s *Scheduler) fire(ctx context.Context, play db.ScheduledPlay) error {
+	now := s.now()
+	if play.Mode == ScheduleAt {
+		if !play.PlayAt.Valid || now.Before(play.PlayAt.Time) {
+			return errScheduleWaiting
+		}
+		if now.Sub(play.PlayAt.Time) > ScheduleGracePeriod {
+			return fmt.Errorf("missed scheduled time %s", play.PlayAt.Time.UTC().Format(time.RFC3339))
+		}
+	}
+
+	songRequest, err := s.queries.GetSongRequestByID(ctx, play.RequestID)
+	if err != nil {
+		return fmt.Errorf("request not found: %w", err)
+	}
+	if songRequest.Status != "approved" {
+		return fmt.Errorf("request is %s, not approved", songRequest.Status)
+	}
+	session, err := s.queries.GetSessionByID(ctx, play.SessionID)
+	if err != nil {
+		return fmt.Errorf("session not found: %w", err)
+	}
+	provider, err := s.providers.Get(session.MusicService)
+	if err != nil {
+		return err
+	}
+	target := provider.PlaybackTarget()
+	if target == nil {
+		return fmt.Errorf("%s sessions have no playback target", session.MusicService)
+	}
+	if !target.IsConnected(play.SessionID) {
+		if play.Mode == ScheduleAt {
+			return errors.New("playback target not connected")
+		}
+		return errScheduleWaiting
+	}
+
+	trackID := songRequest.ExternalTrackID
+	if play.Mode == ScheduleAt {
+		return target.PlayNow(ctx, play.SessionID, trackID)
+	}
+
+	// Without a reported playback position, queue the song behind the
+	// current one instead
+	var current NowPlaying
+	known := false
+	if monitor, ok := target.(PlaybackMonitor); ok {
+		current, known = monitor.NowPlaying(play.SessionID)
+	}
+	if !known {
+		return target.Enqueue(ctx, play.SessionID, trackID)
+	}
+	s.mu.Lock()
+	firedAt, fired := s.firedAt[play.SessionID]
+	s.mu.Unlock()
+	if fired && !current.UpdatedAt.After(firedAt) {
+		return errScheduleWaiting // The previous play has not started yet
+	}
+	if !current.Idle() && current.Remaining(now) > s.interval {
+		return errScheduleWaiting
+	}
+	return target.PlayNow(ctx, play.SessionID, trackID)
+}
+
+// finish records the outcome of a play and notifies the session.
+func (s *Scheduler) finish(ctx context.Context, play db.ScheduledPlay, playErr error) {
+	var err error
+	if playErr == nil {
+		_, err = s.queries.MarkScheduledPlayFired(ctx, play.ID)
+	} else {
+		slog.WarnContext(ctx, "scheduler: scheduled play failed",
+			slog.String("session_id", play.SessionID),
+			slog.Int64("schedule_id", play.ID),
+			slog.String("error", playErr.Error()),
+		)
+		_, err = s.queries.MarkScheduledPlayFailed(ctx, db.MarkScheduledPlayFailedParams{
+			Error: sql.NullString{String: playErr.Error(), Valid: true},
+			ID:    play.ID,
+		})
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "scheduler: failed to update scheduled play",
+			slog.Int64("schedule_id", play.ID),
+			slog.String("error", err.Error()),
+		)
+	}
+	if s.notify != nil {
+		s.notify(play.SessionID)
+	}
+}
@@ -0,0 +1,231 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/songify/backend/internal/database"
+	"github.com/songify/backend/internal/database/dbtest"
+	"github.com/songify/backend/internal/db"
+)
+
+// fakeScreen is a playback target that records commands and reports a
+// configurable playback state.
+type fakeScreen struct {
+	mu         sync.Mutex
+	played     []string
+	queued     []string
+	nowPlaying *NowPlaying
+	failures   int  // Enqueue calls to fail before succeeding
+	offline    bool // Reported as disconnected
+}
+
+func (f *fakeScreen) IsConnected(string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return !f.offline
+}
+
+func (f *fakeScreen) Enqueue(_ context.Context, _, trackID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.failures > 0 {
+		f.failures--
+		return errors.New("screen unreachable")
+	}
+	f.queued = append(f.queued, trackID)
+	return nil
+}
+
+func (f *fakeScreen) PlayNow(_ context.Context, _, trackID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.played = append(f.played, trackID)
+	return nil
+}
+
+func (f *fakeScreen) Remove(context.Context, string, string) error { return nil }
+
+func (f *fakeScreen) NowPlaying(string) (NowPlaying, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.nowPlaying == nil {
+		return NowPlaying{}, false
+	}
+	return *f.nowPlaying, true
+}
+
+type fakeScreenProvider struct {
+	MusicProvider
+	target   *fakeScreen
+	playlist []Track
+}
+
+func (p fakeScreenProvider) Name() string                   { return "youtube" }
+func (p fakeScreenProvider) PlaybackTarget() PlaybackTarget { return p.target }
+func (p fakeScreenProvider) PlaylistTracks(context.Context, string) ([]Track, error) {
+	return p.playlist, nil
+}
+
+func TestSchedulerTick(t *testing.T) {
+	ctx := context.Background()
+	sqlDB := dbtest.New(t)
+	queries := database.NewQueries(sqlDB)
+
+	dbtest.Session(t, queries, "s1")
+	now := time.Date(2026, 6, 1, 20, 0, 0, 0, time.UTC)
+	schedule := func(track, status, mode string, playAt time.Time) db.ScheduledPlay {
+		t.Helper()
+		sr := dbtest.SongRequests(t, queries, "s1", track)[0]
+		if status == "approved" {
+			if _, err := queries.ApproveSongRequest(ctx, db.ApproveSongRequestParams{ID: sr.ID}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		play, err := queries.CreateScheduledPlay(ctx, db.CreateScheduledPlayParams{
+			SessionID: "s1", RequestID: sr.ID, Mode: mode, PlayAt: sql.NullTime{Time: playAt, Valid: !playAt.IsZero()},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return play
+	}
+
+	dance := schedule("dance", "approved", ScheduleAt, now.Add(time.Minute))
+	missed := schedule("missed", "approved", ScheduleAt, now.Add(-time.Hour))
+	pending := schedule("pending", "pending", ScheduleAt, now.Add(-time.Second))
+	next := schedule("next", "approved", ScheduleAfterCurrent, time.Time{})
+	later := schedule("later", "approved", ScheduleAfterCurrent, time.Time{})
+
+	screen := &fakeScreen{nowPlaying: &NowPlaying{
+		VideoID: "current", State: PlayerPlaying, CurrentTime: 10 * time.Second, Duration: 200 * time.Second, UpdatedAt: now,
+	}}
+	var notified []string
+	s := NewScheduler(queries, NewProviderRegistry(fakeScreenProvider{target: screen}), nil, time.Second, func(sessionID string) {
+		notified = append(notified, sessionID)
+	})
+	s.now = func() time.Time { return now }
+
+	status := func(play db.ScheduledPlay) (string, string) {
+		t.Helper()
+		got, err := queries.GetScheduledPlayByID(ctx, db.GetScheduledPlayByIDParams{ID: play.ID, SessionID: "s1"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return got.Status, got.Error.String
+	}
+
+	s.Tick(ctx)
+	if got, reason := status(missed); got != ScheduleStatusFailed || reason == "" {
+		t.Errorf("missed play = %s (%q), want failed with a reason", got, reason)
+	}
+	if got, _ := status(pending); got != ScheduleStatusFailed {
+		t.Errorf("unapproved play = %s, want failed", got)
+	}
+	if got, _ := status(dance); got != ScheduleStatusScheduled {
+		t.Errorf("future play = %s, want scheduled", got)
+	}
+	if got, _ := status(next); got != ScheduleStatusScheduled {
+		t.Errorf("after-current play = %s while a track is playing, want scheduled", got)
+	}
+	if len(notified) != 2 || len(screen.played) != 0 {
+		t.Errorf("notified %v and played %v, want two failures and nothing played", notified, screen.played)
+	}
+
+	// The first dance comes due near the end of the current track
+	now = now.Add(time.Minute + 189*time.Second)
+	s.Tick(ctx)
+	if got, _ := status(dance); got != ScheduleStatusFired {
+		t.Errorf("due play = %s, want fired", got)
+	}
+	if got, _ := status(next); got != ScheduleStatusScheduled {
+		t.Errorf("after-current play = %s, want it held until the first dance ends", got)
+	}
+
+	// The TV reports the first dance, which then ends
+	screen.mu.Lock()
+	screen.nowPlaying = &NowPlaying{VideoID: "dance", State: PlayerPlaying, Duration: 180 * time.Second, UpdatedAt: now.Add(time.Second)}
+	screen.mu.Unlock()
+	now = now.Add(180 * time.Second)
+	s.Tick(ctx)
+	if got, _ := status(next); got != ScheduleStatusFired {
+		t.Errorf("after-current play = %s at the end of the track, want fired", got)
+	}
+	if got, _ := status(later); got != ScheduleStatusScheduled {
+		t.Errorf("second after-current play = %s, want it held for the next track", got)
+	}
+	if want := []string{"dance", "next"}; len(screen.played) != 2 || screen.played[0] != want[0] || screen.played[1] != want[1] {
+		t.Errorf("played = %v, want %v", screen.played, want)
+	}
+	if len(notified) != 4 {
+		t.Errorf("notified %d times, want once per fired or failed play", len(notified))
+	}
+}
+
+func TestSchedulerFallbackPlaylist(t *testing.T) {
+	ctx := context.Background()
+	sqlDB := dbtest.New(t)
+	queries := database.NewQueries(sqlDB)
+
+	dbtest.Session(t, queries, "s1")
+	if err := queries.UpdateSessionFallback(ctx, db.UpdateSessionFallbackParams{
+		FallbackService:    sql.NullString{String: "youtube", Valid: true},
+		FallbackPlaylistID: sql.NullString{String: "PL1", Valid: true},
+		ID:                 "s1",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	screen := &fakeScreen{nowPlaying: &NowPlaying{VideoID: "last", State: PlayerPlaying, Duration: time.Minute, UpdatedAt: now}}
+	provider := fakeScreenProvider{target: screen, playlist: []Track{
+		{ID: "blocked", Name: "Too Long", DurationMS: 600000},
+		{ID: "one", Name: "One", DurationMS: 180000},
+		{ID: "two", Name: "Two", DurationMS: 180000},
+	}}
+	providers := NewProviderRegistry(provider)
+	allowed := func(_ context.Context, _ db.Session, track Track) (bool, error) {
+		return track.DurationMS < 300000, nil
+	}
+	s := NewScheduler(queries, providers, NewAutoDJ(queries, providers, allowed), time.Second, nil)
+	s.now = func() time.Time { return now }
+
+	s.Tick(ctx)
+	if len(screen.played) != 0 {
+		t.Fatalf("played = %v while a song is playing, want nothing", screen.played)
+	}
+
+	// The queue runs dry
+	screen.nowPlaying = &NowPlaying{VideoID: "last", State: PlayerEnded, UpdatedAt: now}
+	now = now.Add(FallbackIdleDelay)
+	s.Tick(ctx)
+	if len(screen.played) != 1 || screen.played[0] != "one" {
+		t.Fatalf("played = %v, want the first allowed playlist track", screen.played)
+	}
+
+	// Nothing more is added until the TV reports the pick
+	now = now.Add(5 * time.Second)
+	s.Tick(ctx)
+	if len(screen.played) != 1 {
+		t.Errorf("played = %v before the TV reported the pick, want one track", screen.played)
+	}
+
+	screen.nowPlaying = &NowPlaying{VideoID: "one", State: PlayerEnded, UpdatedAt: now.Add(time.Second)}
+	now = now.Add(FallbackIdleDelay + time.Second)
+	s.Tick(ctx)
+	if len(screen.played) != 2 || screen.played[1] != "two" {
+		t.Errorf("played = %v, want the playlist to continue", screen.played)
+	}
+
+	session, err := queries.GetSessionByID(ctx, "s1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if session.FallbackPosition != 0 {
+		t.Errorf("fallback position = %d, want the playlist to wrap around", session.FallbackPosition)
+	}
+}
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"container/list"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SearchCache is a shared, size-bounded LRU cache for search results.
+// Entries are keyed by provider namespace and SearchKey, and
+// are considered fresh for the configured TTL. Expired entries are kept until
+// evicted so callers can fall back to them when an upstream API is unavailable.
+// SearchCache is safe for concurrent use.
+type SearchCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	maxSize int
+	order   *list.List // front = most recently used
+	entries map[string]*list.Element
+	stats   map[string]*SearchCacheStats
+	now     func() time.Time
+}
+
+type searchCacheEntry struct {
+	key       string
+	value     any
+	expiresAt time.Time
+}
+
+// SearchCacheStats counts lookups for one provider namespace.
+type SearchCacheStats struct {
+	Hits      int64
+	Misses    int64
+	StaleHits int64
+}
+
+// HitRate returns the fraction of lookups served fresh from the cache.
+func (s SearchCacheStats) HitRate() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+// NewSearchCache creates a SearchCache holding at most maxSize entries for ttl each.
+// A non-positive maxSize or ttl disables caching.
+func NewSearchCache(maxSize int, ttl time.Duration) *SearchCache {
+	return &SearchCache{
+		ttl:     ttl,
+		maxSize: maxSize,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+		stats:   make(map[string]*SearchCacheStats),
+		now:     time.Now,
+	}
+}
+
+// SearchKey identifies a cached search.
+type SearchKey struct {
+	Query  string // Free-text query; normalized so "Daft  Punk" and "daft punk" match
+	Limit  int
+	Params string // Page and filter parameters; compared verbatim
+}
+
+// cacheKey builds the map key for a search within namespace.
+func (k SearchKey) cacheKey(namespace string) string {
+	normalized := strings.Join(strings.Fields(strings.ToLower(k.Query)), " ")
+	return namespace + "\x00" + strconv.Itoa(k.Limit) + "\x00" + k.Params + "\x00" + normalized
+}
+
+// Get returns a fresh cached value and records a hit or miss for namespace.
+func (c *SearchCache) Get(namespace string, key SearchKey) (any, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.statsFor(namespace)
+	el, ok := c.entries[key.cacheKey(namespace)]
+	if !ok || c.now().After(el.Value.(*searchCacheEntry).expiresAt) {
+		stats.Misses++
+		return nil, false
+	}
+	stats.Hits++
+	c.order.MoveToFront(el)
+	return el.Value.(*searchCacheEntry).value, true
+}
+
+// GetStale returns a cached value even if it has expired. It is intended as a
+// fallback when the upstream API cannot be called.
+func (c *SearchCache) GetStale(namespace string, key SearchKey) (any, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key.cacheKey(namespace)]
+	if !ok {
+		return nil, false
+	}
+	c.statsFor(namespace).StaleHits++
+	c.order.MoveToFront(el)
+	return el.Value.(*searchCacheEntry).value, true
+}
+
+// Set stores a value, evicting the least recently used entry when full.
+func (c *SearchCache) Set(namespace string, key SearchKey, value any) {
+	if c == nil || c.maxSize <= 0 || c.ttl <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	k := key.cacheKey(namespace)
+	expiresAt := c.now().Add(c.ttl)
+	if el, ok := c.entries[k]; ok {
+		entry := el.Value.(*searchCacheEntry)
+		entry.value = value
+		entry.expiresAt = expiresAt
+		c.order.MoveToFront(el)
+		return
+	}
+
+	c.entries[k] = c.order.PushFront(&searchCacheEntry{key: k, value: value, expiresAt: expiresAt})
+	for c.order.Len() > c.maxSize {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*searchCacheEntry).key)
+	}
+}
+
+// Stats returns a snapshot of lookup counters per namespace and the current entry count.
+func (c *SearchCache) Stats() (map[string]SearchCacheStats, int) {
+	if c == nil {
+		return map[string]SearchCacheStats{}, 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	snapshot := make(map[string]SearchCacheStats, len(c.stats))
+	for ns, s := range c.stats {
+		snapshot[ns] = *s
+	}
+	return snapshot, c.order.Len()
+}
+
+// statsFor returns the counters for namespace. Lock must be held.
+func (c *SearchCache) statsFor(namespace string) *SearchCacheStats {
+	s, ok := c.stats[namespace]
+	if !ok {
+		s = &SearchCacheStats{}
+		c.stats[namespace] = s
+	}
+	return s
+}
@@ -0,0 +1,194 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSearchCacheNormalizesQueries(t *testing.T) {
+	c := NewSearchCache(10, time.Minute)
+	c.Set("spotify", SearchKey{Query: "Daft  Punk ", Limit: 20}, "results")
+
+	if v, ok := c.Get("spotify", SearchKey{Query: "daft punk", Limit: 20}); !ok || v != "results" {
+		t.Errorf("Get(normalized) = %v, %v; want hit", v, ok)
+	}
+	if _, ok := c.Get("spotify", SearchKey{Query: "daft punk", Limit: 10}); ok {
+		t.Error("different limit should miss")
+	}
+	if _, ok := c.Get("spotify", SearchKey{Query: "daft punk", Limit: 20, Params: "offset=20"}); ok {
+		t.Error("different page should miss")
+	}
+	if _, ok := c.Get("youtube", SearchKey{Query: "daft punk", Limit: 20}); ok {
+		t.Error("different namespace should miss")
+	}
+
+	stats, entries := c.Stats()
+	if entries != 1 {
+		t.Errorf("entries = %d, want 1", entries)
+	}
+	if got := stats["spotify"]; got.Hits != 1 || got.Misses != 2 {
+		t.Errorf("spotify stats = %+v, want 1 hit and 2 misses", got)
+	}
+}
+
+func TestSearchCacheExpiryAndEviction(t *testing.T) {
+	now := time.Now()
+	c := NewSearchCache(2, time.Minute)
+	c.now = func() time.Time { return now }
+
+	c.Set("youtube", SearchKey{Query: "a", Limit: 20}, "a")
+	c.Set("youtube", SearchKey{Query: "b", Limit: 20}, "b")
+	c.Get("youtube", SearchKey{Query: "a", Limit: 20}) // a is now most recently used
+	c.Set("youtube", SearchKey{Query: "c", Limit: 20}, "c")
+
+	if _, ok := c.GetStale("youtube", SearchKey{Query: "b", Limit: 20}); ok {
+		t.Error("least recently used entry should have been evicted")
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, ok := c.Get("youtube", SearchKey{Query: "a", Limit: 20}); ok {
+		t.Error("expired entry should miss")
+	}
+	if v, ok := c.GetStale("youtube", SearchKey{Query: "a", Limit: 20}); !ok || v != "a" {
+		t.Errorf("GetStale(expired) = %v, %v; want stale hit", v, ok)
+	}
+}
+
+func TestQuotaMeterResetsDaily(t *testing.T) {
+	q := NewQuotaMeter(250, 250)
+	now := time.Date(2024, 3, 1, 23, 0, 0, 0, q.loc)
+	q.now = func() time.Time { return now }
+
+	if err := q.Reserve(200); err != nil {
+		t.Fatalf("Reserve(200): %v", err)
+	}
+	if err := q.Reserve(100); !errors.Is(err, ErrQuotaExhausted) {
+		t.Errorf("Reserve over budget = %v, want ErrQuotaExhausted", err)
+	}
+	if used := q.Usage().Used; used != 200 {
+		t.Errorf("used = %d, want 200 (rejected reservation must not count)", used)
+	}
+
+	now = now.Add(2 * time.Hour)
+	usage := q.Usage()
+	if usage.Used != 0 {
+		t.Errorf("used after midnight = %d, want 0", usage.Used)
+	}
+	if want := time.Date(2024, 3, 3, 0, 0, 0, 0, q.loc); !usage.ResetsAt.Equal(want) {
+		t.Errorf("ResetsAt = %v, want %v", usage.ResetsAt, want)
+	}
+}
+
+func TestYouTubeSearchDegradesWhenQuotaExhausted(t *testing.T) {
+	var searches atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/search":
+			searches.Add(1)
+			fmt.Fprintf(w, `{"items":[{"id":{"videoId":"vid-%s"},"snippet":{"title":"Song"}}]}`, r.URL.Query().Get("q"))
+		case "/videos":
+			fmt.Fprint(w, `{"items":[]}`)
+		}
+	}))
+	defer srv.Close()
+
+	cache := NewSearchCache(10, time.Minute)
+	quota := NewQuotaMeter(2*(youtubeSearchCost+youtubeVideosCost), 2*(youtubeSearchCost+youtubeVideosCost))
+	s := NewYouTubeService("key", cache, quota)
+	s.baseURL = srv.URL
+	ctx := context.Background()
+
+	for _, q := range []string{"one", "ONE", "two"} {
+		if _, err := s.Search(ctx, YouTubeSearchOptions{Query: q}); err != nil {
+			t.Fatalf("Search(%q): %v", q, err)
+		}
+	}
+	if n := searches.Load(); n != 2 {
+		t.Errorf("API searches = %d, want 2 (second query cached)", n)
+	}
+	if used := quota.Usage().Used; used != 2*(youtubeSearchCost+youtubeVideosCost) {
+		t.Errorf("quota used = %d, want %d", used, 2*(youtubeSearchCost+youtubeVideosCost))
+	}
+
+	// Budget spent: cached queries still work (even once expired), new ones fail clearly
+	cache.now = func() time.Time { return time.Now().Add(time.Hour) }
+	result, err := s.Search(ctx, YouTubeSearchOptions{Query: "one"})
+	if err != nil || len(result.Videos) != 1 || result.Videos[0].ID != "vid-one" {
+		t.Errorf("Search(cached) = %+v, %v; want stale result", result, err)
+	}
+	if _, err := s.Search(ctx, YouTubeSearchOptions{Query: "three"}); !errors.Is(err, ErrQuotaExhausted) {
+		t.Errorf("Search(uncached) error = %v, want ErrQuotaExhausted", err)
+	}
+	if n := searches.Load(); n != 2 {
+		t.Errorf("API searches = %d, want no calls once quota is spent", n)
+	}
+}
+
+func TestYouTubeSearchHonoursUpstreamQuotaError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"error":{"errors":[{"reason":"quotaExceeded"}]}}`)
+	}))
+	defer srv.Close()
+
+	quota := NewQuotaMeter(10000, 10000)
+	s := NewYouTubeService("key", NewSearchCache(10, time.Minute), quota)
+	s.baseURL = srv.URL
+
+	if _, err := s.Search(context.Background(), YouTubeSearchOptions{Query: "anything"}); !errors.Is(err, ErrQuotaExhausted) {
+		t.Errorf("Search error = %v, want ErrQuotaExhausted", err)
+	}
+	if usage := quota.Usage(); usage.Used != usage.Budget {
+		t.Errorf("quota used = %d, want budget %d after upstream rejection", usage.Used, usage.Budget)
+	}
+}
+
+func TestYouTubeSearchCacheOnlyPastSoftLimit(t *testing.T) {
+	var searches atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/search":
+			searches.Add(1)
+			fmt.Fprintf(w, `{"items":[{"id":{"videoId":"vid-%s"},"snippet":{"title":"Song"}}]}`, r.URL.Query().Get("q"))
+		case "/videos":
+			fmt.Fprint(w, `{"items":[]}`)
+		}
+	}))
+	defer srv.Close()
+
+	cache := NewSearchCache(10, time.Minute)
+	quota := NewQuotaMeter(1000, youtubeSearchCost+youtubeVideosCost)
+	s := NewYouTubeService("key", cache, quota)
+	s.baseURL = srv.URL
+	ctx := context.Background()
+
+	if _, err := s.Search(ctx, YouTubeSearchOptions{Query: "one"}); err != nil {
+		t.Fatalf("Search(one): %v", err)
+	}
+	if !quota.Low() {
+		t.Fatal("quota should be past its soft limit")
+	}
+
+	// Past the soft limit: expired entries are still served, new searches are not sent
+	cache.now = func() time.Time { return time.Now().Add(time.Hour) }
+	if result, err := s.Search(ctx, YouTubeSearchOptions{Query: "one"}); err != nil || result.Videos[0].ID != "vid-one" {
+		t.Errorf("Search(cached) = %+v, %v; want stale result", result, err)
+	}
+	if _, err := s.Search(ctx, YouTubeSearchOptions{Query: "two"}); !errors.Is(err, ErrQuotaExhausted) {
+		t.Errorf("Search(uncached) error = %v, want ErrQuotaExhausted", err)
+	}
+	if n := searches.Load(); n != 1 {
+		t.Errorf("API searches = %d, want 1", n)
+	}
+
+	// The rest of the budget is left for lookups
+	if err := quota.Reserve(youtubeVideosCost); err != nil {
+		t.Errorf("Reserve past soft limit: %v", err)
+	}
+}
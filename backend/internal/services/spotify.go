@@ -8,6 +8,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -22,8 +23,18 @@ type SpotifyService struct {
 	token        string
 	tokenExpiry  time.Time
 	mu           sync.RWMutex
+	cache        *SearchCache
+	metadata     *SearchCache // Artist genres and track metadata
 }
 
+// Artist genres and release details rarely change and are only needed for
+// sessions with track constraints, so they are kept in a small cache of their
+// own rather than competing with searches for room in the shared one.
+const (
+	spotifyMetadataCacheSize = 1000
+	spotifyMetadataCacheTTL  = 24 * time.Hour
+)
+
 // spotifyTokenResponse is the OAuth2 token response from Spotify.
 type spotifyTokenResponse struct {
 	AccessToken string `json:"access_token"`
@@ -39,14 +50,23 @@ type SpotifyTrack struct {
 	DurationMS  int    `json:"duration_ms"`
 	Album       Album  `json:"album"`
 	Artists     []Artist `json:"artists"`
+	ExternalIDs ExternalIDs `json:"external_ids"`
+	Popularity  int    `json:"popularity"` // 0-100
+}
+
+// ExternalIDs holds industry identifiers for a track.
+type ExternalIDs struct {
+	ISRC string `json:"isrc"`
 }
 
 type Album struct {
-	Name   string  `json:"name"`
-	Images []Image `json:"images"`
+	Name        string  `json:"name"`
+	Images      []Image `json:"images"`
+	ReleaseDate string  `json:"release_date"` // "1984", "1984-06" or "1984-06-04"
 }
 
 type Artist struct {
+	ID   string `json:"id"`
 	Name string `json:"name"`
 }
 
@@ -58,18 +78,24 @@ type Image struct {
 
 type SpotifySearchResponse struct {
 	Tracks struct {
-		Items []SpotifyTrack `json:"items"`
+		Items  []SpotifyTrack `json:"items"`
+		Total  int            `json:"total"`
+		Offset int            `json:"offset"`
+		Next   string         `json:"next"`
 	} `json:"tracks"`
 }
 
-// NewSpotifyService creates a SpotifyService with the given client credentials.
-func NewSpotifyService(clientID, clientSecret string) *SpotifyService {
+// NewSpotifyService creates a SpotifyService with the given client credentials
+// and shared search cache.
+func NewSpotifyService(clientID, clientSecret string, cache *SearchCache) *SpotifyService {
 	return &SpotifyService{
 		clientID:     clientID,
 		clientSecret: clientSecret,
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second,
 		},
+		cache:    cache,
+		metadata: NewSearchCache(spotifyMetadataCacheSize, spotifyMetadataCacheTTL),
 	}
 }
 
@@ -126,20 +152,71 @@ func (s *SpotifyService) getAccessToken(ctx context.Context) (string, error) {
 	return s.token, nil
 }
 
-// Search queries Spotify for tracks matching the given search string.
+// SpotifySearchOptions controls a Spotify track search.
+// Artist, Album and Year become Spotify field filters; Year may be a single
+// year ("1999") or a range ("1990-1999").
+type SpotifySearchOptions struct {
+	Query  string
+	Artist string
+	Album  string
+	Year   string
+	Limit  int // 1-50, defaults to 20
+	Offset int // 0-1000
+}
+
+// SpotifySearchResult is one page of Spotify search results.
+// NextOffset is nil when there are no further pages.
+type SpotifySearchResult struct {
+	Tracks     []SpotifyTrack
+	Total      int
+	Offset     int
+	NextOffset *int
+}
+
+// q builds the Spotify search string, appending field filters to the free text.
+func (o SpotifySearchOptions) q() string {
+	var parts []string
+	if q := strings.TrimSpace(o.Query); q != "" {
+		parts = append(parts, q)
+	}
+	if artist := spotifyFilterValue(o.Artist); artist != "" {
+		parts = append(parts, `artist:"`+artist+`"`)
+	}
+	if album := spotifyFilterValue(o.Album); album != "" {
+		parts = append(parts, `album:"`+album+`"`)
+	}
+	if o.Year != "" {
+		parts = append(parts, "year:"+o.Year)
+	}
+	return strings.Join(parts, " ")
+}
+
+// spotifyFilterValue strips quotes so a filter value cannot break out of its field.
+func spotifyFilterValue(v string) string {
+	return strings.TrimSpace(strings.ReplaceAll(v, `"`, ""))
+}
+
+// Search queries Spotify for tracks matching the given options.
 // Limit defaults to 20 if not specified or out of range (1-50).
-func (s *SpotifyService) Search(ctx context.Context, query string, limit int) ([]SpotifyTrack, error) {
+// Identical searches are served from the shared search cache.
+func (s *SpotifyService) Search(ctx context.Context, opts SpotifySearchOptions) (*SpotifySearchResult, error) {
+	if opts.Limit <= 0 || opts.Limit > 50 {
+		opts.Limit = 20
+	}
+	q := opts.q()
+	key := SearchKey{Query: q, Limit: opts.Limit, Params: strconv.Itoa(opts.Offset)}
+
+	if cached, ok := s.cache.Get("spotify", key); ok {
+		return cached.(*SpotifySearchResult), nil
+	}
+
 	token, err := s.getAccessToken(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	if limit <= 0 || limit > 50 {
-		limit = 20
-	}
-
-	searchURL := fmt.Sprintf("https://api.spotify.com/v1/search?q=%s&type=track&limit=%d",
-		url.QueryEscape(query), limit)
+	searchURL := fmt.Sprintf("https://api.spotify.com/v1/search?q=%s&type=track&limit=%d&offset=%d",
+		url.QueryEscape(q), opts.Limit, opts.Offset)
 
 	req, err := http.NewRequestWithContext(ctx, "GET", searchURL, nil)
 	if err != nil {
@@ -164,7 +241,147 @@ func (s *SpotifyService) Search(ctx context.Context, query string, limit int) ([
 		return nil, fmt.Errorf("failed to decode search response: %w", err)
 	}
 
-	return searchResp.Tracks.Items, nil
+	result := &SpotifySearchResult{
+		Tracks: searchResp.Tracks.Items,
+		Total:  searchResp.Tracks.Total,
+		Offset: searchResp.Tracks.Offset,
+	}
+	if searchResp.Tracks.Next != "" {
+		next := result.Offset + len(result.Tracks)
+		result.NextOffset = &next
+	}
+
+	s.cache.Set("spotify", key, result)
+	return result, nil
+}
+
+// spotifyArtistsResponse is the response of the several-artists endpoint.
+type spotifyArtistsResponse struct {
+	Artists []struct {
+		ID     string   `json:"id"`
+		Genres []string `json:"genres"`
+	} `json:"artists"`
+}
+
+// GetArtistGenres returns the genres of each artist by ID. Artists are looked
+// up 50 at a time and each one's genres are cached.
+func (s *SpotifyService) GetArtistGenres(ctx context.Context, artistIDs []string) (map[string][]string, error) {
+	genres := make(map[string][]string, len(artistIDs))
+	var missing []string
+	for _, id := range artistIDs {
+		if cached, ok := s.metadata.Get("artists", SearchKey{Params: id}); ok {
+			genres[id] = cached.([]string)
+		} else {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) == 0 {
+		return genres, nil
+	}
+
+	token, err := s.getAccessToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for start := 0; start < len(missing); start += 50 {
+		batch := missing[start:min(start+50, len(missing))]
+		artistsURL := "https://api.spotify.com/v1/artists?ids=" + url.QueryEscape(strings.Join(batch, ","))
+
+		req, err := http.NewRequestWithContext(ctx, "GET", artistsURL, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create artists request: %w", err)
+		}
+
+		req.Header.Set("Authorization", "Bearer "+token)
+
+		resp, err := s.httpClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("artists request failed: %w", err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read artists response: %w", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("artists request failed with status %d: %s", resp.StatusCode, string(body))
+		}
+
+		var artistsResp spotifyArtistsResponse
+		if err := json.Unmarshal(body, &artistsResp); err != nil {
+			return nil, fmt.Errorf("failed to decode artists response: %w", err)
+		}
+		for _, artist := range artistsResp.Artists {
+			if artist.ID == "" {
+				continue // Unknown IDs come back as null
+			}
+			genres[artist.ID] = artist.Genres
+			s.metadata.Set("artists", SearchKey{Params: artist.ID}, artist.Genres)
+		}
+	}
+
+	return genres, nil
+}
+
+// spotifyPlaylistTracksResponse is one page of a playlist's entries.
+// Track is nil for entries Spotify can no longer play.
+type spotifyPlaylistTracksResponse struct {
+	Items []struct {
+		IsLocal bool          `json:"is_local"`
+		Track   *SpotifyTrack `json:"track"`
+	} `json:"items"`
+	Next string `json:"next"`
+}
+
+// GetPlaylistTracks lists up to MaxPlaylistTracks tracks of a public playlist
+// in order. Local files and unavailable entries are skipped.
+func (s *SpotifyService) GetPlaylistTracks(ctx context.Context, playlistID string) ([]SpotifyTrack, error) {
+	token, err := s.getAccessToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var tracks []SpotifyTrack
+	pageURL := fmt.Sprintf("https://api.spotify.com/v1/playlists/%s/tracks?limit=100", url.PathEscape(playlistID))
+	for pageURL != "" && len(tracks) < MaxPlaylistTracks {
+		req, err := http.NewRequestWithContext(ctx, "GET", pageURL, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create playlist request: %w", err)
+		}
+
+		req.Header.Set("Authorization", "Bearer "+token)
+
+		resp, err := s.httpClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("playlist request failed: %w", err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read playlist response: %w", err)
+		}
+
+		if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusBadRequest {
+			return nil, fmt.Errorf("spotify playlist %s: %w", playlistID, ErrTrackNotFound)
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("playlist request failed with status %d: %s", resp.StatusCode, string(body))
+		}
+
+		var page spotifyPlaylistTracksResponse
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, fmt.Errorf("failed to decode playlist response: %w", err)
+		}
+		for _, item := range page.Items {
+			if item.Track != nil && !item.IsLocal && item.Track.ID != "" {
+				tracks = append(tracks, *item.Track)
+			}
+		}
+		pageURL = page.Next
+	}
+
+	return tracks[:min(len(tracks), MaxPlaylistTracks)], nil
 }
 
 // GetTrack retrieves a single track by its Spotify ID.
@@ -174,7 +391,7 @@ func (s *SpotifyService) GetTrack(ctx context.Context, trackID string) (*Spotify
 		return nil, err
 	}
 
-	trackURL := fmt.Sprintf("https://api.spotify.com/v1/tracks/%s", trackID)
+	trackURL := fmt.Sprintf("https://api.spotify.com/v1/tracks/%s", url.PathEscape(trackID))
 
 	req, err := http.NewRequestWithContext(ctx, "GET", trackURL, nil)
 	if err != nil {
@@ -189,6 +406,9 @@ func (s *SpotifyService) GetTrack(ctx context.Context, trackID string) (*Spotify
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusBadRequest {
+		return nil, fmt.Errorf("spotify track %s: %w", trackID, ErrTrackNotFound)
+	}
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		return nil, fmt.Errorf("track request failed with status %d: %s", resp.StatusCode, string(body))
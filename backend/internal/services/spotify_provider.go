@@ -0,0 +1,206 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// spotifyIDRe matches a Spotify base-62 resource ID.
+var spotifyIDRe = regexp.MustCompile(`^[0-9A-Za-z]{22}$`)
+
+// SpotifyProvider adapts SpotifyService to the MusicProvider interface.
+// Approved songs are added to the admin's playlist by the frontend, so it has
+// no server-side playback target.
+type SpotifyProvider struct {
+	service *SpotifyService
+}
+
+// NewSpotifyProvider creates a SpotifyProvider backed by the given service.
+func NewSpotifyProvider(service *SpotifyService) *SpotifyProvider {
+	return &SpotifyProvider{service: service}
+}
+
+// Name implements MusicProvider.
+func (p *SpotifyProvider) Name() string { return "spotify" }
+
+// Search implements MusicProvider.
+func (p *SpotifyProvider) Search(ctx context.Context, opts SearchOptions) (*SearchPage, error) {
+	result, err := p.service.Search(ctx, SpotifySearchOptions{
+		Query:  opts.Query,
+		Artist: opts.Artist,
+		Album:  opts.Album,
+		Year:   opts.Year,
+		Limit:  opts.Limit,
+		Offset: opts.Offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	page := &SearchPage{
+		Tracks:     make([]Track, len(result.Tracks)),
+		NextOffset: result.NextOffset,
+	}
+	for i, t := range result.Tracks {
+		page.Tracks[i] = spotifyTrackToTrack(t)
+	}
+	return page, nil
+}
+
+// GetTrack implements MusicProvider.
+func (p *SpotifyProvider) GetTrack(ctx context.Context, id string) (*Track, error) {
+	t, err := p.service.GetTrack(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	track := spotifyTrackToTrack(*t)
+	return &track, nil
+}
+
+// TrackMetadata implements MetadataSource using the track's popularity, its
+// album's release date and its artists' genres. Results are cached.
+func (p *SpotifyProvider) TrackMetadata(ctx context.Context, trackID string) (*TrackMetadata, error) {
+	key := SearchKey{Params: trackID}
+	if cached, ok := p.service.metadata.Get("tracks", key); ok {
+		return cached.(*TrackMetadata), nil
+	}
+
+	t, err := p.service.GetTrack(ctx, trackID)
+	if err != nil {
+		return nil, err
+	}
+	artistIDs := make([]string, 0, len(t.Artists))
+	for _, a := range t.Artists {
+		if a.ID != "" {
+			artistIDs = append(artistIDs, a.ID)
+		}
+	}
+	artistGenres, err := p.service.GetArtistGenres(ctx, artistIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := &TrackMetadata{Popularity: t.Popularity}
+	if len(t.Album.ReleaseDate) >= 4 {
+		meta.ReleaseYear, _ = strconv.Atoi(t.Album.ReleaseDate[:4])
+	}
+	for _, id := range artistIDs {
+		for _, genre := range artistGenres[id] {
+			if !slices.Contains(meta.Genres, genre) {
+				meta.Genres = append(meta.Genres, genre)
+			}
+		}
+	}
+
+	p.service.metadata.Set("tracks", key, meta)
+	return meta, nil
+}
+
+// PlaylistTracks implements PlaylistSource.
+func (p *SpotifyProvider) PlaylistTracks(ctx context.Context, playlistID string) ([]Track, error) {
+	items, err := p.service.GetPlaylistTracks(ctx, playlistID)
+	if err != nil {
+		return nil, err
+	}
+	tracks := make([]Track, len(items))
+	for i, t := range items {
+		tracks[i] = spotifyTrackToTrack(t)
+	}
+	return tracks, nil
+}
+
+// ResolveURL implements MusicProvider. It accepts spotify:track: URIs and
+// open.spotify.com/track/ links, including localized /intl-xx/ paths.
+func (p *SpotifyProvider) ResolveURL(rawURL string) (string, error) {
+	rawURL = strings.TrimSpace(rawURL)
+
+	var kind, id string
+	if rest, ok := strings.CutPrefix(rawURL, "spotify:"); ok {
+		kind, id, _ = strings.Cut(rest, ":")
+	} else {
+		u, err := parseLink(rawURL)
+		if err != nil || (u.Host != "open.spotify.com" && u.Host != "play.spotify.com") {
+			return "", ErrUnrecognizedLink
+		}
+		segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+		if len(segments) > 0 && strings.HasPrefix(segments[0], "intl-") {
+			segments = segments[1:]
+		}
+		if len(segments) > 0 && segments[0] == "embed" {
+			segments = segments[1:]
+		}
+		if len(segments) < 2 {
+			return "", ErrUnrecognizedLink
+		}
+		kind, id = segments[0], segments[1]
+	}
+
+	if kind != "track" {
+		if kind == "" {
+			return "", ErrUnrecognizedLink
+		}
+		return "", ErrLinkNotTrack
+	}
+	if !spotifyIDRe.MatchString(id) {
+		return "", fmt.Errorf("invalid Spotify track ID: %w", ErrUnrecognizedLink)
+	}
+	return id, nil
+}
+
+// SearchISRC implements ISRCSearcher using Spotify's isrc: field filter.
+func (p *SpotifyProvider) SearchISRC(ctx context.Context, isrc string) ([]Track, error) {
+	result, err := p.service.Search(ctx, SpotifySearchOptions{Query: "isrc:" + spotifyFilterValue(isrc), Limit: 5})
+	if err != nil {
+		return nil, err
+	}
+	tracks := make([]Track, len(result.Tracks))
+	for i, t := range result.Tracks {
+		tracks[i] = spotifyTrackToTrack(t)
+	}
+	return tracks, nil
+}
+
+// PlaybackTarget implements MusicProvider.
+func (p *SpotifyProvider) PlaybackTarget() PlaybackTarget { return nil }
+
+// spotifyTrackToTrack converts a Spotify API track to a provider-neutral Track.
+func spotifyTrackToTrack(t SpotifyTrack) Track {
+	artists := make([]string, len(t.Artists))
+	for i, a := range t.Artists {
+		artists[i] = a.Name
+	}
+
+	var albumArt string
+	if len(t.Album.Images) > 0 {
+		albumArt = t.Album.Images[0].URL
+	}
+
+	return Track{
+		ID:          t.ID,
+		Name:        t.Name,
+		ArtistNames: joinArtists(artists),
+		AlbumName:   t.Album.Name,
+		AlbumArtURL: albumArt,
+		DurationMS:  int64(t.DurationMS),
+		URI:         t.URI,
+		ISRC:        t.ExternalIDs.ISRC,
+	}
+}
+
+// parseLink parses a pasted link, tolerating a missing scheme.
+func parseLink(rawURL string) (*url.URL, error) {
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "https://" + rawURL
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	u.Host = strings.ToLower(u.Hostname())
+	return u, nil
+}
@@ -0,0 +1,25 @@
+package services
+
+import "testing"
+
+func TestSpotifySearchOptionsQuery(t *testing.T) {
+	tests := []struct {
+		name string
+		opts SpotifySearchOptions
+		want string
+	}{
+		{"free text", SpotifySearchOptions{Query: " get lucky "}, "get lucky"},
+		{"all filters", SpotifySearchOptions{Query: "lucky", Artist: "Daft Punk", Album: "Random Access Memories", Year: "2013"},
+			`lucky artist:"Daft Punk" album:"Random Access Memories" year:2013`},
+		{"filter only", SpotifySearchOptions{Artist: "Daft Punk"}, `artist:"Daft Punk"`},
+		{"quotes stripped", SpotifySearchOptions{Artist: `AC"DC`}, `artist:"ACDC"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.opts.q(); got != tt.want {
+				t.Errorf("q() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,364 @@
+package services
+
+import (
+	"context"
+	"crypto/md5" // #nosec G501 -- Subsonic token auth is defined as md5(password + salt)
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// subsonicAPIVersion is the Subsonic REST API version requested; 1.16.1
+	// is what Navidrome and current Subsonic servers implement.
+	subsonicAPIVersion = "1.16.1"
+	// subsonicClientName identifies this app to the server.
+	subsonicClientName = "songify"
+	// subsonicErrNotFound is the Subsonic error code for a missing resource.
+	subsonicErrNotFound = 70
+)
+
+// SubsonicQueueMode selects where approved songs are sent on a Subsonic server.
+type SubsonicQueueMode string
+
+const (
+	// SubsonicQueueJukebox plays songs through the server's jukebox output.
+	SubsonicQueueJukebox SubsonicQueueMode = "jukebox"
+	// SubsonicQueuePlaylist appends songs to a server playlist per session,
+	// for playback in any Subsonic client.
+	SubsonicQueuePlaylist SubsonicQueueMode = "playlist"
+)
+
+// SubsonicService provides access to a self-hosted music server speaking the
+// Subsonic REST API (Navidrome, Airsonic, Gonic, etc.).
+type SubsonicService struct {
+	baseURL    string
+	username   string
+	password   string
+	httpClient *http.Client
+	cache      *SearchCache
+
+	mu        sync.Mutex
+	playlists map[string]string // session ID -> playlist ID
+}
+
+// SubsonicSong is a song from the Subsonic API. Duration is in seconds.
+type SubsonicSong struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Artist   string `json:"artist"`
+	Album    string `json:"album"`
+	CoverArt string `json:"coverArt"`
+	Duration int    `json:"duration"`
+	// ISRC is an OpenSubsonic extension; plain Subsonic servers omit it.
+	ISRC []string `json:"isrc"`
+}
+
+// subsonicResponse is the envelope of every Subsonic JSON response.
+type subsonicResponse struct {
+	Response struct {
+		Status string `json:"status"`
+		Error  *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+		SearchResult3 struct {
+			Song []SubsonicSong `json:"song"`
+		} `json:"searchResult3"`
+		Song      *SubsonicSong `json:"song"`
+		Playlists struct {
+			Playlist []subsonicPlaylist `json:"playlist"`
+		} `json:"playlists"`
+		Playlist *subsonicPlaylist `json:"playlist"`
+		Jukebox  struct {
+			Entry []SubsonicSong `json:"entry"`
+		} `json:"jukeboxPlaylist"`
+	} `json:"subsonic-response"`
+}
+
+type subsonicPlaylist struct {
+	ID    string         `json:"id"`
+	Name  string         `json:"name"`
+	Entry []SubsonicSong `json:"entry"` // Only returned by getPlaylist
+}
+
+// SubsonicError is a failure reported by the Subsonic server.
+type SubsonicError struct {
+	Code    int
+	Message string
+}
+
+func (e *SubsonicError) Error() string {
+	return fmt.Sprintf("subsonic error %d: %s", e.Code, e.Message)
+}
+
+// SubsonicSearchResult is one page of Subsonic search results.
+// NextOffset is nil when there are no further pages.
+type SubsonicSearchResult struct {
+	Songs      []SubsonicSong
+	NextOffset *int
+}
+
+// NewSubsonicService creates a SubsonicService for the server at baseURL,
+// authenticating with the given credentials.
+func NewSubsonicService(baseURL, username, password string, cache *SearchCache) *SubsonicService {
+	return &SubsonicService{
+		baseURL:  strings.TrimRight(baseURL, "/"),
+		username: username,
+		password: password,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		cache:     cache,
+		playlists: make(map[string]string),
+	}
+}
+
+// Configured reports whether a server URL has been set.
+func (s *SubsonicService) Configured() bool {
+	return s.baseURL != ""
+}
+
+// call invokes a Subsonic API method and decodes the response envelope.
+// A "failed" status is returned as a *SubsonicError.
+func (s *SubsonicService) call(ctx context.Context, method string, params url.Values) (*subsonicResponse, error) {
+	salt, err := subsonicSalt()
+	if err != nil {
+		return nil, err
+	}
+	token := md5.Sum([]byte(s.password + salt)) // #nosec G401 -- required by the Subsonic API
+
+	if params == nil {
+		params = url.Values{}
+	}
+	params.Set("u", s.username)
+	params.Set("t", hex.EncodeToString(token[:]))
+	params.Set("s", salt)
+	params.Set("v", subsonicAPIVersion)
+	params.Set("c", subsonicClientName)
+	params.Set("f", "json")
+
+	req, err := http.NewRequestWithContext(ctx, "GET", s.baseURL+"/rest/"+method+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s request: %w", method, err)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s request failed: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("%s request failed with status %d: %s", method, resp.StatusCode, string(body))
+	}
+
+	var result subsonicResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode %s response: %w", method, err)
+	}
+	if result.Response.Status != "ok" {
+		if e := result.Response.Error; e != nil {
+			return nil, fmt.Errorf("%s: %w", method, &SubsonicError{Code: e.Code, Message: e.Message})
+		}
+		return nil, fmt.Errorf("%s: unexpected status %q", method, result.Response.Status)
+	}
+	return &result, nil
+}
+
+// subsonicSalt returns a random salt for token authentication.
+func subsonicSalt() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate salt: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Search queries the server for songs matching query.
+// Limit defaults to 20 if not specified or out of range (1-50).
+// Identical searches are served from the shared search cache.
+func (s *SubsonicService) Search(ctx context.Context, query string, limit, offset int) (*SubsonicSearchResult, error) {
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+	key := SearchKey{Query: query, Limit: limit, Params: strconv.Itoa(offset)}
+
+	if cached, ok := s.cache.Get("subsonic", key); ok {
+		return cached.(*SubsonicSearchResult), nil
+	}
+
+	resp, err := s.call(ctx, "search3", url.Values{
+		"query":       {query},
+		"songCount":   {strconv.Itoa(limit)},
+		"songOffset":  {strconv.Itoa(offset)},
+		"artistCount": {"0"},
+		"albumCount":  {"0"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SubsonicSearchResult{Songs: resp.Response.SearchResult3.Song}
+	// search3 has no total; a full page means there may be more
+	if len(result.Songs) == limit {
+		next := offset + limit
+		result.NextOffset = &next
+	}
+
+	s.cache.Set("subsonic", key, result)
+	return result, nil
+}
+
+// GetSong retrieves a single song by its server ID.
+func (s *SubsonicService) GetSong(ctx context.Context, id string) (*SubsonicSong, error) {
+	resp, err := s.call(ctx, "getSong", url.Values{"id": {id}})
+	if err != nil {
+		var se *SubsonicError
+		if errors.As(err, &se) && se.Code == subsonicErrNotFound {
+			return nil, fmt.Errorf("subsonic song %s: %w", id, ErrTrackNotFound)
+		}
+		return nil, err
+	}
+	if resp.Response.Song == nil {
+		return nil, fmt.Errorf("subsonic song %s: %w", id, ErrTrackNotFound)
+	}
+	return resp.Response.Song, nil
+}
+
+// JukeboxAdd appends a song to the server's jukebox playlist and starts
+// playback if it was stopped.
+func (s *SubsonicService) JukeboxAdd(ctx context.Context, id string) error {
+	if _, err := s.call(ctx, "jukeboxControl", url.Values{"action": {"add"}, "id": {id}}); err != nil {
+		return err
+	}
+	_, err := s.call(ctx, "jukeboxControl", url.Values{"action": {"start"}})
+	return err
+}
+
+// JukeboxPlayNow appends a song to the jukebox playlist and skips to it.
+func (s *SubsonicService) JukeboxPlayNow(ctx context.Context, id string) error {
+	if _, err := s.call(ctx, "jukeboxControl", url.Values{"action": {"add"}, "id": {id}}); err != nil {
+		return err
+	}
+	resp, err := s.call(ctx, "jukeboxControl", url.Values{"action": {"get"}})
+	if err != nil {
+		return err
+	}
+	index := len(resp.Response.Jukebox.Entry) - 1
+	if index < 0 {
+		return fmt.Errorf("jukebox playlist is empty after adding song %s", id)
+	}
+	if _, err := s.call(ctx, "jukeboxControl", url.Values{"action": {"skip"}, "index": {strconv.Itoa(index)}}); err != nil {
+		return err
+	}
+	_, err = s.call(ctx, "jukeboxControl", url.Values{"action": {"start"}})
+	return err
+}
+
+// JukeboxRemove removes the most recently added copy of a song from the
+// jukebox playlist. It does nothing if the song is not in the playlist.
+func (s *SubsonicService) JukeboxRemove(ctx context.Context, id string) error {
+	resp, err := s.call(ctx, "jukeboxControl", url.Values{"action": {"get"}})
+	if err != nil {
+		return err
+	}
+	index := lastSongIndex(resp.Response.Jukebox.Entry, id)
+	if index < 0 {
+		return nil
+	}
+	_, err = s.call(ctx, "jukeboxControl", url.Values{"action": {"remove"}, "index": {strconv.Itoa(index)}})
+	return err
+}
+
+// PlaylistAdd appends a song to the session's server playlist, creating the
+// playlist on first use.
+func (s *SubsonicService) PlaylistAdd(ctx context.Context, sessionID, id string) error {
+	playlistID, err := s.sessionPlaylist(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	_, err = s.call(ctx, "updatePlaylist", url.Values{"playlistId": {playlistID}, "songIdToAdd": {id}})
+	return err
+}
+
+// PlaylistRemove removes the most recently added copy of a song from the
+// session's server playlist. It does nothing if the song is not in the playlist.
+func (s *SubsonicService) PlaylistRemove(ctx context.Context, sessionID, id string) error {
+	playlistID, err := s.sessionPlaylist(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	resp, err := s.call(ctx, "getPlaylist", url.Values{"id": {playlistID}})
+	if err != nil {
+		return err
+	}
+	if resp.Response.Playlist == nil {
+		return fmt.Errorf("getPlaylist returned no playlist")
+	}
+	index := lastSongIndex(resp.Response.Playlist.Entry, id)
+	if index < 0 {
+		return nil
+	}
+	_, err = s.call(ctx, "updatePlaylist", url.Values{"playlistId": {playlistID}, "songIndexToRemove": {strconv.Itoa(index)}})
+	return err
+}
+
+// lastSongIndex returns the index of the last entry with the given ID, or -1.
+func lastSongIndex(entries []SubsonicSong, id string) int {
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// sessionPlaylist returns the ID of the playlist songs for a session are
+// added to, finding it by name or creating it if needed.
+func (s *SubsonicService) sessionPlaylist(ctx context.Context, sessionID string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if id, ok := s.playlists[sessionID]; ok {
+		return id, nil
+	}
+
+	name := SubsonicPlaylistName(sessionID)
+	resp, err := s.call(ctx, "getPlaylists", nil)
+	if err != nil {
+		return "", err
+	}
+	for _, p := range resp.Response.Playlists.Playlist {
+		if p.Name == name {
+			s.playlists[sessionID] = p.ID
+			return p.ID, nil
+		}
+	}
+
+	resp, err = s.call(ctx, "createPlaylist", url.Values{"name": {name}})
+	if err != nil {
+		return "", err
+	}
+	if resp.Response.Playlist == nil {
+		return "", fmt.Errorf("createPlaylist returned no playlist")
+	}
+	s.playlists[sessionID] = resp.Response.Playlist.ID
+	return resp.Response.Playlist.ID, nil
+}
+
+// SubsonicPlaylistName is the name of the server playlist approved songs for
+// a session are added to in playlist mode.
+func SubsonicPlaylistName(sessionID string) string {
+	return "Songify " + sessionID
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// SubsonicProvider adapts SubsonicService to the MusicProvider interface.
+// It is its own PlaybackTarget: approved songs go to the server's jukebox or
+// to a per-session playlist, depending on the queue mode.
+type SubsonicProvider struct {
+	service *SubsonicService
+	mode    SubsonicQueueMode
+}
+
+// NewSubsonicProvider creates a SubsonicProvider backed by the given service.
+// An unrecognized mode falls back to the jukebox.
+func NewSubsonicProvider(service *SubsonicService, mode SubsonicQueueMode) *SubsonicProvider {
+	if mode != SubsonicQueuePlaylist {
+		mode = SubsonicQueueJukebox
+	}
+	return &SubsonicProvider{service: service, mode: mode}
+}
+
+// Name implements MusicProvider.
+func (p *SubsonicProvider) Name() string { return "subsonic" }
+
+// Search implements MusicProvider. Subsonic only supports free-text search,
+// so artist and album filters are folded into the query.
+func (p *SubsonicProvider) Search(ctx context.Context, opts SearchOptions) (*SearchPage, error) {
+	var terms []string
+	for _, t := range []string{opts.Query, opts.Artist, opts.Album} {
+		if t = strings.TrimSpace(t); t != "" {
+			terms = append(terms, t)
+		}
+	}
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("%w: query is required", ErrInvalidSearch)
+	}
+
+	result, err := p.service.Search(ctx, strings.Join(terms, " "), opts.Limit, opts.Offset)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &SearchPage{
+		Tracks:     make([]Track, len(result.Songs)),
+		NextOffset: result.NextOffset,
+	}
+	for i, s := range result.Songs {
+		page.Tracks[i] = subsonicSongToTrack(s)
+	}
+	return page, nil
+}
+
+// GetTrack implements MusicProvider.
+func (p *SubsonicProvider) GetTrack(ctx context.Context, id string) (*Track, error) {
+	s, err := p.service.GetSong(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	track := subsonicSongToTrack(*s)
+	return &track, nil
+}
+
+// ResolveURL implements MusicProvider. It accepts subsonic:song: URIs and
+// links to the configured server that carry a song id, such as stream or
+// download URLs. Album, artist, playlist and share pages are collections.
+func (p *SubsonicProvider) ResolveURL(rawURL string) (string, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	if id, ok := strings.CutPrefix(rawURL, "subsonic:song:"); ok && id != "" {
+		return id, nil
+	}
+
+	server, err := parseLink(p.service.baseURL)
+	if err != nil || !p.service.Configured() {
+		return "", ErrUnrecognizedLink
+	}
+	u, err := parseLink(rawURL)
+	if err != nil || u.Host != server.Host {
+		return "", ErrUnrecognizedLink
+	}
+
+	if id := u.Query().Get("id"); id != "" {
+		return id, nil
+	}
+	for _, collection := range []string{"/album/", "/artist/", "/playlist/", "/share/"} {
+		if strings.Contains(u.Path, collection) || strings.Contains(u.Fragment, collection) {
+			return "", ErrLinkNotTrack
+		}
+	}
+	return "", ErrUnrecognizedLink
+}
+
+// PlaybackTarget implements MusicProvider.
+func (p *SubsonicProvider) PlaybackTarget() PlaybackTarget { return p }
+
+// IsConnected implements PlaybackTarget. Songs can be delivered whenever a
+// server is configured; delivery failures surface from Enqueue and PlayNow.
+func (p *SubsonicProvider) IsConnected(string) bool {
+	return p.service.Configured()
+}
+
+// Enqueue implements PlaybackTarget.
+func (p *SubsonicProvider) Enqueue(ctx context.Context, sessionID, trackID string) error {
+	if p.mode == SubsonicQueuePlaylist {
+		return p.service.PlaylistAdd(ctx, sessionID, trackID)
+	}
+	return p.service.JukeboxAdd(ctx, trackID)
+}
+
+// PlayNow implements PlaybackTarget. In playlist mode playback happens in
+// another client, so the song is appended like any other.
+func (p *SubsonicProvider) PlayNow(ctx context.Context, sessionID, trackID string) error {
+	if p.mode == SubsonicQueuePlaylist {
+		return p.service.PlaylistAdd(ctx, sessionID, trackID)
+	}
+	return p.service.JukeboxPlayNow(ctx, trackID)
+}
+
+// Remove implements PlaybackTarget.
+func (p *SubsonicProvider) Remove(ctx context.Context, sessionID, trackID string) error {
+	if p.mode == SubsonicQueuePlaylist {
+		return p.service.PlaylistRemove(ctx, sessionID, trackID)
+	}
+	return p.service.JukeboxRemove(ctx, trackID)
+}
+
+// subsonicSongToTrack converts a Subsonic song to a provider-neutral Track.
+// Cover art needs authenticated requests, so no album art URL is exposed.
+func subsonicSongToTrack(s SubsonicSong) Track {
+	var isrc string
+	if len(s.ISRC) > 0 {
+		isrc = s.ISRC[0]
+	}
+	return Track{
+		ISRC:        isrc,
+		ID:          s.ID,
+		Name:        s.Title,
+		ArtistNames: s.Artist,
+		AlbumName:   s.Album,
+		DurationMS:  int64(s.Duration) * 1000,
+		URI:         "subsonic:song:" + s.ID,
+	}
+}
@@ -0,0 +1,221 @@
+package services
+
+import (
+	"context"
+	"crypto/md5" // #nosec G501 -- verifying Subsonic token auth
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeSubsonic is a minimal Subsonic server recording the calls it receives.
+type fakeSubsonic struct {
+	t        *testing.T
+	mu       sync.Mutex
+	calls    []string
+	jukebox  []string
+	playlist []string
+}
+
+func (f *fakeSubsonic) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	token := md5.Sum([]byte("secret" + q.Get("s"))) // #nosec G401
+	if q.Get("u") != "dj" || q.Get("t") != hex.EncodeToString(token[:]) || q.Get("f") != "json" {
+		fmt.Fprint(w, `{"subsonic-response":{"status":"failed","error":{"code":40,"message":"Wrong username or password"}}}`)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	method := strings.TrimPrefix(r.URL.Path, "/rest/")
+	f.calls = append(f.calls, strings.TrimSpace(method+" "+q.Get("action")))
+
+	switch method {
+	case "search3":
+		fmt.Fprintf(w, `{"subsonic-response":{"status":"ok","searchResult3":{"song":[
+			{"id":"s1","title":"%s","artist":"Artist","album":"Album","duration":215}]}}}`, q.Get("query"))
+	case "getSong":
+		if q.Get("id") != "s1" {
+			fmt.Fprint(w, `{"subsonic-response":{"status":"failed","error":{"code":70,"message":"Song not found"}}}`)
+			return
+		}
+		fmt.Fprint(w, `{"subsonic-response":{"status":"ok","song":{"id":"s1","title":"Song","artist":"Artist","duration":200}}}`)
+	case "jukeboxControl":
+		switch q.Get("action") {
+		case "add":
+			f.jukebox = append(f.jukebox, q.Get("id"))
+		case "remove":
+			f.jukebox = removeIndex(f.jukebox, q.Get("index"))
+		}
+		fmt.Fprintf(w, `{"subsonic-response":{"status":"ok","jukeboxPlaylist":{"entry":[%s]}}}`, songEntries(f.jukebox))
+	case "getPlaylists":
+		fmt.Fprint(w, `{"subsonic-response":{"status":"ok","playlists":{"playlist":[{"id":"p0","name":"Other"}]}}}`)
+	case "createPlaylist":
+		fmt.Fprint(w, `{"subsonic-response":{"status":"ok","playlist":{"id":"p1","name":"Songify sess"}}}`)
+	case "getPlaylist":
+		fmt.Fprintf(w, `{"subsonic-response":{"status":"ok","playlist":{"id":"p1","name":"Songify sess","entry":[%s]}}}`, songEntries(f.playlist))
+	case "updatePlaylist":
+		if q.Get("playlistId") != "p1" {
+			f.t.Errorf("updatePlaylist playlistId = %q, want p1", q.Get("playlistId"))
+		}
+		if q.Has("songIndexToRemove") {
+			f.playlist = removeIndex(f.playlist, q.Get("songIndexToRemove"))
+		} else {
+			f.playlist = append(f.playlist, q.Get("songIdToAdd"))
+		}
+		fmt.Fprint(w, `{"subsonic-response":{"status":"ok"}}`)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// songEntries renders song IDs as a Subsonic entry list.
+func songEntries(ids []string) string {
+	var entries []string
+	for _, id := range ids {
+		entries = append(entries, fmt.Sprintf(`{"id":%q}`, id))
+	}
+	return strings.Join(entries, ",")
+}
+
+// removeIndex drops the element at a string index.
+func removeIndex(ids []string, index string) []string {
+	i, err := strconv.Atoi(index)
+	if err != nil || i < 0 || i >= len(ids) {
+		return ids
+	}
+	return append(ids[:i:i], ids[i+1:]...)
+}
+
+func newTestSubsonic(t *testing.T, mode SubsonicQueueMode) (*SubsonicProvider, *fakeSubsonic) {
+	t.Helper()
+	fake := &fakeSubsonic{t: t}
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+
+	service := NewSubsonicService(srv.URL+"/", "dj", "secret", NewSearchCache(10, time.Minute))
+	return NewSubsonicProvider(service, mode), fake
+}
+
+func TestSubsonicProviderSearchAndLookup(t *testing.T) {
+	p, _ := newTestSubsonic(t, SubsonicQueueJukebox)
+	ctx := context.Background()
+
+	page, err := p.Search(ctx, SearchOptions{Query: "blue", Artist: "Miles", Limit: 1})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(page.Tracks) != 1 || page.Tracks[0].Name != "blue Miles" || page.Tracks[0].DurationMS != 215000 {
+		t.Errorf("Search tracks = %+v", page.Tracks)
+	}
+	if page.NextOffset == nil || *page.NextOffset != 1 {
+		t.Errorf("NextOffset = %v, want 1 for a full page", page.NextOffset)
+	}
+	if _, err := p.Search(ctx, SearchOptions{}); !errors.Is(err, ErrInvalidSearch) {
+		t.Errorf("empty Search error = %v, want ErrInvalidSearch", err)
+	}
+
+	track, err := p.GetTrack(ctx, "s1")
+	if err != nil || track.URI != "subsonic:song:s1" {
+		t.Errorf("GetTrack(s1) = %+v, %v", track, err)
+	}
+	if _, err := p.GetTrack(ctx, "missing"); !errors.Is(err, ErrTrackNotFound) {
+		t.Errorf("GetTrack(missing) error = %v, want ErrTrackNotFound", err)
+	}
+}
+
+func TestSubsonicProviderBadCredentials(t *testing.T) {
+	p, _ := newTestSubsonic(t, SubsonicQueueJukebox)
+	p.service.password = "wrong"
+
+	var se *SubsonicError
+	if _, err := p.Search(context.Background(), SearchOptions{Query: "x"}); !errors.As(err, &se) || se.Code != 40 {
+		t.Errorf("Search error = %v, want Subsonic error 40", err)
+	}
+}
+
+func TestSubsonicProviderJukebox(t *testing.T) {
+	p, fake := newTestSubsonic(t, SubsonicQueueJukebox)
+	ctx := context.Background()
+	target := p.PlaybackTarget()
+
+	if !target.IsConnected("sess") {
+		t.Fatal("IsConnected = false for a configured server")
+	}
+	if err := target.Enqueue(ctx, "sess", "a"); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	if err := target.PlayNow(ctx, "sess", "b"); err != nil {
+		t.Fatalf("PlayNow: %v", err)
+	}
+
+	want := []string{"jukeboxControl add", "jukeboxControl start", "jukeboxControl add", "jukeboxControl get", "jukeboxControl skip", "jukeboxControl start"}
+	if strings.Join(fake.calls, ",") != strings.Join(want, ",") {
+		t.Errorf("calls = %v, want %v", fake.calls, want)
+	}
+
+	if err := target.Remove(ctx, "sess", "a"); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if err := target.Remove(ctx, "sess", "missing"); err != nil {
+		t.Fatalf("Remove(missing): %v", err)
+	}
+	if strings.Join(fake.jukebox, ",") != "b" {
+		t.Errorf("jukebox = %v, want [b]", fake.jukebox)
+	}
+}
+
+func TestSubsonicProviderPlaylist(t *testing.T) {
+	p, fake := newTestSubsonic(t, SubsonicQueuePlaylist)
+	ctx := context.Background()
+
+	for _, id := range []string{"a", "b"} {
+		if err := p.Enqueue(ctx, "sess", id); err != nil {
+			t.Fatalf("Enqueue(%s): %v", id, err)
+		}
+	}
+
+	if strings.Join(fake.playlist, ",") != "a,b" {
+		t.Errorf("playlist = %v, want [a b]", fake.playlist)
+	}
+	want := []string{"getPlaylists", "createPlaylist", "updatePlaylist", "updatePlaylist"}
+	if strings.Join(fake.calls, ",") != strings.Join(want, ",") {
+		t.Errorf("calls = %v, want %v (playlist looked up once)", fake.calls, want)
+	}
+
+	if err := p.Remove(ctx, "sess", "a"); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if strings.Join(fake.playlist, ",") != "b" {
+		t.Errorf("playlist after Remove = %v, want [b]", fake.playlist)
+	}
+}
+
+func TestSubsonicProviderResolveURL(t *testing.T) {
+	p := NewSubsonicProvider(NewSubsonicService("https://music.example.com", "dj", "secret", nil), "")
+	tests := []struct {
+		url     string
+		want    string
+		wantErr error
+	}{
+		{"subsonic:song:abc123", "abc123", nil},
+		{"https://music.example.com/rest/stream?id=abc123&u=dj", "abc123", nil},
+		{"https://music.example.com/app/#/album/xyz/show", "", ErrLinkNotTrack},
+		{"https://music.example.com/share/xyz", "", ErrLinkNotTrack},
+		{"https://other.example.com/rest/stream?id=abc123", "", ErrUnrecognizedLink},
+	}
+
+	for _, tt := range tests {
+		got, err := p.ResolveURL(tt.url)
+		if !errors.Is(err, tt.wantErr) || got != tt.want {
+			t.Errorf("ResolveURL(%q) = %q, %v; want %q, %v", tt.url, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
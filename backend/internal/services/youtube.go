@@ -1,8 +1,10 @@
 package services
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"html"
 	"io"
@@ -14,10 +16,16 @@ import (
 	"time"
 )
 
+const youtubeAPIBaseURL = "https://www.googleapis.com/youtube/v3"
+
 // YouTubeService provides access to the YouTube Data API v3 for video searches.
+// Results are cached and every API call is metered against the daily quota.
 type YouTubeService struct {
 	apiKey     string
+	baseURL    string
 	httpClient *http.Client
+	cache      *SearchCache
+	quota      *QuotaMeter
 }
 
 // YouTubeVideo represents a video from YouTube search results.
@@ -30,7 +38,9 @@ type YouTubeVideo struct {
 }
 
 type youtubeSearchResponse struct {
-	Items []youtubeSearchItem `json:"items"`
+	Items         []youtubeSearchItem `json:"items"`
+	NextPageToken string              `json:"nextPageToken"`
+	PrevPageToken string              `json:"prevPageToken"`
 }
 
 type youtubeSearchItem struct {
@@ -57,24 +67,106 @@ type youtubeThumbnail struct {
 	URL string `json:"url"`
 }
 
-// NewYouTubeService creates a YouTubeService with the given API key.
-func NewYouTubeService(apiKey string) *YouTubeService {
+// NewYouTubeService creates a YouTubeService with the given API key, shared
+// search cache, and daily quota meter.
+func NewYouTubeService(apiKey string, cache *SearchCache, quota *QuotaMeter) *YouTubeService {
 	return &YouTubeService{
-		apiKey: apiKey,
+		apiKey:  apiKey,
+		baseURL: youtubeAPIBaseURL,
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second,
 		},
+		cache: cache,
+		quota: quota,
+	}
+}
+
+// YouTubeSearchOptions controls a YouTube search.
+type YouTubeSearchOptions struct {
+	Query      string
+	Limit      int    // 1-50, defaults to 20
+	PageToken  string // Token from a previous YouTubeSearchResult
+	CategoryID string // Video category, e.g. YouTubeCategoryMusic
+	Duration   string // "short" (<4m), "medium" (4-20m) or "long" (>20m)
+}
+
+// YouTubeCategoryMusic is the video category ID for Music.
+const YouTubeCategoryMusic = "10"
+
+// YouTubeSearchResult is one page of YouTube search results.
+type YouTubeSearchResult struct {
+	Videos        []YouTubeVideo
+	NextPageToken string
+	PrevPageToken string
+}
+
+// params returns the search.list parameters other than the query and limit.
+func (o YouTubeSearchOptions) params() url.Values {
+	params := url.Values{}
+	if o.PageToken != "" {
+		params.Set("pageToken", o.PageToken)
+	}
+	if o.CategoryID != "" {
+		params.Set("videoCategoryId", o.CategoryID)
+	}
+	if o.Duration != "" {
+		params.Set("videoDuration", o.Duration)
 	}
+	return params
 }
 
-// Search queries YouTube for videos matching the given search string.
-func (s *YouTubeService) Search(ctx context.Context, query string, limit int) ([]YouTubeVideo, error) {
-	if limit <= 0 || limit > 50 {
-		limit = 20
+// Search queries YouTube for videos matching the given options.
+// Identical searches are served from the cache. Once the daily quota passes
+// its soft limit, searches are served only from the cache, including expired
+// entries, so the remaining units go to video and playlist lookups. The same
+// applies when the quota cannot cover another search. Uncached searches then
+// fail with ErrQuotaExhausted.
+func (s *YouTubeService) Search(ctx context.Context, opts YouTubeSearchOptions) (*YouTubeSearchResult, error) {
+	if opts.Limit <= 0 || opts.Limit > 50 {
+		opts.Limit = 20
 	}
+	key := SearchKey{Query: opts.Query, Limit: opts.Limit, Params: opts.params().Encode()}
 
-	searchURL := fmt.Sprintf("https://www.googleapis.com/youtube/v3/search?part=snippet&type=video&q=%s&maxResults=%d&key=%s",
-		url.QueryEscape(query), limit, url.QueryEscape(s.apiKey))
+	if cached, ok := s.cache.Get("youtube", key); ok {
+		return cached.(*YouTubeSearchResult), nil
+	}
+
+	if s.quota.Low() {
+		return s.staleResults(key, fmt.Errorf("search quota kept for lookups: %w", ErrQuotaExhausted))
+	}
+	if err := s.quota.Reserve(youtubeSearchCost + youtubeVideosCost); err != nil {
+		return s.staleResults(key, err)
+	}
+
+	result, err := s.search(ctx, opts)
+	if err != nil {
+		if errors.Is(err, ErrQuotaExhausted) {
+			return s.staleResults(key, err)
+		}
+		return nil, err
+	}
+
+	s.cache.Set("youtube", key, result)
+	return result, nil
+}
+
+// staleResults falls back to an expired cache entry when the API cannot be called.
+func (s *YouTubeService) staleResults(key SearchKey, cause error) (*YouTubeSearchResult, error) {
+	if cached, ok := s.cache.GetStale("youtube", key); ok {
+		return cached.(*YouTubeSearchResult), nil
+	}
+	return nil, cause
+}
+
+// search calls search.list and videos.list without consulting the cache.
+func (s *YouTubeService) search(ctx context.Context, opts YouTubeSearchOptions) (*YouTubeSearchResult, error) {
+	params := opts.params()
+	params.Set("part", "snippet")
+	params.Set("type", "video")
+	params.Set("q", opts.Query)
+	params.Set("maxResults", strconv.Itoa(opts.Limit))
+	params.Set("key", s.apiKey)
+	searchURL := s.baseURL + "/search?" + params.Encode()
 
 	req, err := http.NewRequestWithContext(ctx, "GET", searchURL, nil)
 	if err != nil {
@@ -89,6 +181,10 @@ func (s *YouTubeService) Search(ctx context.Context, query string, limit int) ([
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
+		if isQuotaExceededResponse(resp.StatusCode, body) {
+			s.quota.Exhaust()
+			return nil, fmt.Errorf("search request rejected: %w", ErrQuotaExhausted)
+		}
 		return nil, fmt.Errorf("search request failed with status %d: %s", resp.StatusCode, string(body))
 	}
 
@@ -124,7 +220,148 @@ func (s *YouTubeService) Search(ctx context.Context, query string, limit int) ([
 		}
 	}
 
-	return videos, nil
+	return &YouTubeSearchResult{
+		Videos:        videos,
+		NextPageToken: searchResp.NextPageToken,
+		PrevPageToken: searchResp.PrevPageToken,
+	}, nil
+}
+
+// GetVideo looks up a single video by ID (1 quota unit).
+func (s *YouTubeService) GetVideo(ctx context.Context, videoID string) (*YouTubeVideo, error) {
+	if err := s.quota.Reserve(youtubeVideosCost); err != nil {
+		return nil, err
+	}
+
+	videosURL := fmt.Sprintf("%s/videos?part=snippet,contentDetails&id=%s&key=%s",
+		s.baseURL, url.QueryEscape(videoID), url.QueryEscape(s.apiKey))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", videosURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create videos request: %w", err)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("videos request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		if isQuotaExceededResponse(resp.StatusCode, body) {
+			s.quota.Exhaust()
+			return nil, fmt.Errorf("videos request rejected: %w", ErrQuotaExhausted)
+		}
+		return nil, fmt.Errorf("videos request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var videosResp youtubeVideosResponse
+	if err := json.NewDecoder(resp.Body).Decode(&videosResp); err != nil {
+		return nil, fmt.Errorf("failed to decode videos response: %w", err)
+	}
+	if len(videosResp.Items) == 0 {
+		return nil, fmt.Errorf("youtube video %s: %w", videoID, ErrTrackNotFound)
+	}
+
+	item := videosResp.Items[0]
+	thumbnailURL := item.Snippet.Thumbnails.Medium.URL
+	if thumbnailURL == "" {
+		thumbnailURL = item.Snippet.Thumbnails.Default.URL
+	}
+	return &YouTubeVideo{
+		ID:           item.ID,
+		Title:        html.UnescapeString(item.Snippet.Title),
+		ChannelTitle: html.UnescapeString(item.Snippet.ChannelTitle),
+		ThumbnailURL: thumbnailURL,
+		DurationMS:   parseISO8601Duration(item.ContentDetails.Duration),
+	}, nil
+}
+
+// GetPlaylistVideos lists up to MaxPlaylistTracks videos of a playlist in
+// order (2 quota units per page of 50). Deleted and private videos are skipped.
+func (s *YouTubeService) GetPlaylistVideos(ctx context.Context, playlistID string) ([]YouTubeVideo, error) {
+	var videos []YouTubeVideo
+	pageToken := ""
+	for len(videos) < MaxPlaylistTracks {
+		if err := s.quota.Reserve(youtubePlaylistItemsCost + youtubeVideosCost); err != nil {
+			return nil, err
+		}
+
+		params := url.Values{}
+		params.Set("part", "snippet")
+		params.Set("playlistId", playlistID)
+		params.Set("maxResults", "50")
+		params.Set("key", s.apiKey)
+		if pageToken != "" {
+			params.Set("pageToken", pageToken)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, "GET", s.baseURL+"/playlistItems?"+params.Encode(), nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create playlist request: %w", err)
+		}
+
+		resp, err := s.httpClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("playlist request failed: %w", err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read playlist response: %w", err)
+		}
+
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("youtube playlist %s: %w", playlistID, ErrTrackNotFound)
+		}
+		if resp.StatusCode != http.StatusOK {
+			if isQuotaExceededResponse(resp.StatusCode, body) {
+				s.quota.Exhaust()
+				return nil, fmt.Errorf("playlist request rejected: %w", ErrQuotaExhausted)
+			}
+			return nil, fmt.Errorf("playlist request failed with status %d: %s", resp.StatusCode, string(body))
+		}
+
+		var page youtubePlaylistItemsResponse
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, fmt.Errorf("failed to decode playlist response: %w", err)
+		}
+
+		start := len(videos)
+		var videoIDs []string
+		for _, item := range page.Items {
+			// Deleted and private videos have no owner channel
+			if item.Snippet.VideoOwnerChannelTitle == "" {
+				continue
+			}
+			thumbnailURL := item.Snippet.Thumbnails.Medium.URL
+			if thumbnailURL == "" {
+				thumbnailURL = item.Snippet.Thumbnails.Default.URL
+			}
+			videos = append(videos, YouTubeVideo{
+				ID:           item.Snippet.ResourceID.VideoID,
+				Title:        html.UnescapeString(item.Snippet.Title),
+				ChannelTitle: html.UnescapeString(item.Snippet.VideoOwnerChannelTitle),
+				ThumbnailURL: thumbnailURL,
+			})
+			videoIDs = append(videoIDs, item.Snippet.ResourceID.VideoID)
+		}
+
+		durations, err := s.getVideoDurations(ctx, videoIDs)
+		if err == nil {
+			for i := start; i < len(videos); i++ {
+				videos[i].DurationMS = durations[videos[i].ID]
+			}
+		}
+
+		pageToken = page.NextPageToken
+		if pageToken == "" {
+			break
+		}
+	}
+
+	return videos[:min(len(videos), MaxPlaylistTracks)], nil
 }
 
 // getVideoDurations fetches video durations from the YouTube Videos API.
@@ -134,8 +371,8 @@ func (s *YouTubeService) getVideoDurations(ctx context.Context, videoIDs []strin
 		return nil, nil
 	}
 
-	videosURL := fmt.Sprintf("https://www.googleapis.com/youtube/v3/videos?part=contentDetails&id=%s&key=%s",
-		url.QueryEscape(strings.Join(videoIDs, ",")), url.QueryEscape(s.apiKey))
+	videosURL := fmt.Sprintf("%s/videos?part=contentDetails&id=%s&key=%s",
+		s.baseURL, url.QueryEscape(strings.Join(videoIDs, ",")), url.QueryEscape(s.apiKey))
 
 	req, err := http.NewRequestWithContext(ctx, "GET", videosURL, nil)
 	if err != nil {
@@ -166,12 +403,31 @@ func (s *YouTubeService) getVideoDurations(ctx context.Context, videoIDs []strin
 	return durations, nil
 }
 
+// isQuotaExceededResponse reports whether the API rejected a call because the
+// project's daily quota is spent.
+func isQuotaExceededResponse(status int, body []byte) bool {
+	return status == http.StatusForbidden && bytes.Contains(body, []byte("quotaExceeded"))
+}
+
+type youtubePlaylistItemsResponse struct {
+	Items []struct {
+		Snippet struct {
+			Title                  string            `json:"title"`
+			VideoOwnerChannelTitle string            `json:"videoOwnerChannelTitle"`
+			Thumbnails             youtubeThumbnails `json:"thumbnails"`
+			ResourceID             youtubeVideoID    `json:"resourceId"`
+		} `json:"snippet"`
+	} `json:"items"`
+	NextPageToken string `json:"nextPageToken"`
+}
+
 type youtubeVideosResponse struct {
 	Items []youtubeVideoItem `json:"items"`
 }
 
 type youtubeVideoItem struct {
 	ID             string                `json:"id"`
+	Snippet        youtubeSnippet        `json:"snippet"`
 	ContentDetails youtubeContentDetails `json:"contentDetails"`
 }
 
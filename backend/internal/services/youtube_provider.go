@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// youtubeIDRe matches an 11-character YouTube video ID.
+var youtubeIDRe = regexp.MustCompile(`^[0-9A-Za-z_-]{11}$`)
+
+// YouTubeProvider adapts YouTubeService to the MusicProvider interface.
+// Approved songs are sent to TVs paired through the Lounge API.
+type YouTubeProvider struct {
+	service *YouTubeService
+	lounge  *LoungeManager
+}
+
+// NewYouTubeProvider creates a YouTubeProvider backed by the given service and lounge manager.
+func NewYouTubeProvider(service *YouTubeService, lounge *LoungeManager) *YouTubeProvider {
+	return &YouTubeProvider{service: service, lounge: lounge}
+}
+
+// Name implements MusicProvider.
+func (p *YouTubeProvider) Name() string { return "youtube" }
+
+// Search implements MusicProvider.
+func (p *YouTubeProvider) Search(ctx context.Context, opts SearchOptions) (*SearchPage, error) {
+	if strings.TrimSpace(opts.Query) == "" {
+		return nil, fmt.Errorf("%w: query is required", ErrInvalidSearch)
+	}
+
+	result, err := p.service.Search(ctx, YouTubeSearchOptions{
+		Query:      opts.Query,
+		Limit:      opts.Limit,
+		PageToken:  opts.PageToken,
+		CategoryID: opts.Category,
+		Duration:   opts.Duration,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	page := &SearchPage{
+		Tracks:        make([]Track, len(result.Videos)),
+		NextPageToken: result.NextPageToken,
+	}
+	for i, v := range result.Videos {
+		page.Tracks[i] = youtubeVideoToTrack(v)
+	}
+	return page, nil
+}
+
+// GetTrack implements MusicProvider.
+func (p *YouTubeProvider) GetTrack(ctx context.Context, id string) (*Track, error) {
+	v, err := p.service.GetVideo(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	track := youtubeVideoToTrack(*v)
+	return &track, nil
+}
+
+// PlaylistTracks implements PlaylistSource.
+func (p *YouTubeProvider) PlaylistTracks(ctx context.Context, playlistID string) ([]Track, error) {
+	videos, err := p.service.GetPlaylistVideos(ctx, playlistID)
+	if err != nil {
+		return nil, err
+	}
+	tracks := make([]Track, len(videos))
+	for i, v := range videos {
+		tracks[i] = youtubeVideoToTrack(v)
+	}
+	return tracks, nil
+}
+
+// ResolveURL implements MusicProvider. It accepts youtu.be links and
+// youtube.com / music.youtube.com watch, shorts, embed and live links.
+func (p *YouTubeProvider) ResolveURL(rawURL string) (string, error) {
+	u, err := parseLink(strings.TrimSpace(rawURL))
+	if err != nil {
+		return "", ErrUnrecognizedLink
+	}
+
+	var id string
+	switch strings.TrimPrefix(u.Host, "www.") {
+	case "youtu.be":
+		id = strings.Trim(u.Path, "/")
+	case "youtube.com", "m.youtube.com", "music.youtube.com":
+		segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+		switch segments[0] {
+		case "watch":
+			id = u.Query().Get("v")
+			if id == "" && u.Query().Get("list") != "" {
+				return "", ErrLinkNotTrack
+			}
+		case "shorts", "embed", "live":
+			if len(segments) > 1 {
+				id = segments[1]
+			}
+		case "playlist", "browse", "channel", "album":
+			return "", ErrLinkNotTrack
+		default:
+			if strings.HasPrefix(segments[0], "@") {
+				return "", ErrLinkNotTrack
+			}
+		}
+	default:
+		return "", ErrUnrecognizedLink
+	}
+
+	if !youtubeIDRe.MatchString(id) {
+		return "", errors.Join(ErrUnrecognizedLink, fmt.Errorf("invalid YouTube video ID %q", id))
+	}
+	return id, nil
+}
+
+// PlaybackTarget implements MusicProvider.
+func (p *YouTubeProvider) PlaybackTarget() PlaybackTarget { return p.lounge }
+
+// youtubeVideoToTrack converts a YouTube video to a provider-neutral Track.
+// The channel stands in for the artist; videos have no album.
+func youtubeVideoToTrack(v YouTubeVideo) Track {
+	return Track{
+		ID:          v.ID,
+		Name:        v.Title,
+		ArtistNames: v.ChannelTitle,
+		AlbumArtURL: v.ThumbnailURL,
+		DurationMS:  v.DurationMS,
+		URI:         "https://www.youtube.com/watch?v=" + v.ID,
+	}
+}
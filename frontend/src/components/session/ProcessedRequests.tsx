@@ -1,3 +1,5 @@
+import { Undo2 } from 'lucide-react'
+import { Button } from '@/components/ui/button'
 import { Card, CardContent, CardHeader, CardTitle } from '@/components/ui/card'
 import { StatusBadge } from './StatusBadge'
 import type { SongRequest } from '@/types'
@@ -6,9 +8,15 @@ import { formatDuration } from '@/lib/utils'
 export function ProcessedRequests({
   requests,
   getExternalLink,
+  isAdmin = false,
+  onUndo,
+  isUndoing,
 }: {
   requests: SongRequest[]
   getExternalLink: (request: SongRequest) => string
+  isAdmin?: boolean
+  onUndo?: (request: SongRequest) => void
+  isUndoing?: boolean
 }) {
   if (requests.length === 0) return null
 
@@ -62,6 +70,17 @@ export function ProcessedRequests({
                 {formatDuration(request.durationMs)}
               </span>
               <StatusBadge status={request.status} />
+              {isAdmin && onUndo && (
+                <Button
+                  size="icon"
+                  variant="ghost"
+                  onClick={() => onUndo(request)}
+                  disabled={isUndoing}
+                  title="Undo"
+                >
+                  <Undo2 className="h-4 w-4" />
+                </Button>
+              )}
             </div>
           ))}
         </div>
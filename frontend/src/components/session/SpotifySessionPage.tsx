@@ -13,6 +13,7 @@ import {
   authenticateSpotify,
   isSpotifyAuthenticated,
   addTrackToPlaylist,
+  removeTrackFromPlaylist,
   tryRestoreSpotifySession,
 } from '@/services/spotify'
 import { useSortedRequests } from '@/hooks/useSortedRequests'
@@ -43,7 +44,7 @@ export function SpotifySessionPage({
     }
   }, [isAdmin, session.spotifyPlaylistId, queryClient, session.id])
 
-  const { approveMutation, rejectMutation, approveError, setApproveError } = useRequestMutations({
+  const { approveMutation, rejectMutation, undoMutation, approveError, setApproveError } = useRequestMutations({
     sessionId: session.id,
     onBeforeApprove: async (requestId) => {
       if (session.spotifyPlaylistId && !isSpotifyAuthenticated()) {
@@ -62,6 +63,19 @@ export function SpotifySessionPage({
         toast.error(error.message || 'Failed to approve song')
       }
     },
+    onUndoSuccess: async (request) => {
+      // Approval added the song to the playlist, so take it back out
+      if (request.status !== 'approved' || !session.spotifyPlaylistId) return
+      if (!isSpotifyAuthenticated()) {
+        toast.error('Reconnect to Spotify to remove the song from the playlist')
+        return
+      }
+      try {
+        await removeTrackFromPlaylist(session.spotifyPlaylistId, request.externalUri)
+      } catch {
+        toast.error('Failed to remove the song from the Spotify playlist')
+      }
+    },
   })
 
   const handleSpotifyAuth = async () => {
@@ -116,6 +130,9 @@ export function SpotifySessionPage({
         <ProcessedRequests
           requests={processedRequests}
           getExternalLink={getExternalLink}
+          isAdmin={isAdmin}
+          onUndo={(request) => undoMutation.mutate(request)}
+          isUndoing={undoMutation.isPending}
         />
 
         {requests.length === 0 && !requestsLoading && <EmptyState />}
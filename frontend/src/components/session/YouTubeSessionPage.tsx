@@ -93,7 +93,7 @@ export function YouTubeSessionPage({
     },
   })
 
-  const { approveMutation, rejectMutation, approveError, setApproveError } = useRequestMutations({
+  const { approveMutation, rejectMutation, undoMutation, approveError, setApproveError } = useRequestMutations({
     sessionId: session.id,
   })
 
@@ -156,6 +156,9 @@ export function YouTubeSessionPage({
         <ProcessedRequests
           requests={processedRequests}
           getExternalLink={getExternalLink}
+          isAdmin={isAdmin}
+          onUndo={(request) => undoMutation.mutate(request)}
+          isUndoing={undoMutation.isPending}
         />
 
         {requests.length === 0 && !requestsLoading && <EmptyState />}
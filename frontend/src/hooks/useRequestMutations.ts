@@ -2,17 +2,21 @@ import { useState } from 'react'
 import { useMutation, useQueryClient } from '@tanstack/react-query'
 import { toast } from 'sonner'
 import { api } from '@/services/api'
+import type { SongRequest } from '@/types'
 
 export function useRequestMutations({
   sessionId,
   onBeforeApprove,
   onApproveSuccess,
   onApproveError,
+  onUndoSuccess,
 }: {
   sessionId: string
   onBeforeApprove?: (requestId: number) => Promise<void>
   onApproveSuccess?: () => void
   onApproveError?: (error: Error) => void
+  /** Called with the request as it was before the undo */
+  onUndoSuccess?: (request: SongRequest) => void
 }) {
   const queryClient = useQueryClient()
   const [approveError, setApproveError] = useState<string | null>(null)
@@ -50,9 +54,22 @@ export function useRequestMutations({
     },
   })
 
+  const undoMutation = useMutation({
+    mutationFn: (request: SongRequest) => api.undoSongRequest(sessionId, request.id),
+    onSuccess: (_, request) => {
+      toast.success('Request returned to pending')
+      queryClient.invalidateQueries({ queryKey: ['requests', sessionId] })
+      onUndoSuccess?.(request)
+    },
+    onError: (error: Error) => {
+      toast.error(error.message || 'Failed to undo')
+    },
+  })
+
   return {
     approveMutation,
     rejectMutation,
+    undoMutation,
     approveError,
     setApproveError,
   }
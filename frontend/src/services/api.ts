@@ -169,6 +169,13 @@ export const api = {
     })
   },
 
+  /** Return a recently approved or rejected request to pending (admin only) */
+  undoSongRequest: async (sessionId: string, requestId: number): Promise<SongRequest> => {
+    return request(`/sessions/${sessionId}/requests/${requestId}/undo`, {
+      method: 'PUT',
+    })
+  },
+
   /** Delete all song requests in a session (admin only) */
   archiveAllRequests: async (sessionId: string): Promise<void> => {
     return request(`/sessions/${sessionId}/requests`, {
@@ -158,6 +158,22 @@ export async function addTrackToPlaylist(playlistId: string, trackUri: string) {
   await spotifyApi.playlists.addItemsToPlaylist(playlistId, [trackUri])
 }
 
+/**
+ * Remove a track from the linked playlist, e.g. when its approval is undone.
+ * Spotify removes every occurrence of the track.
+ * @param playlistId - Spotify playlist ID
+ * @param trackUri - Spotify track URI (e.g., "spotify:track:...")
+ */
+export async function removeTrackFromPlaylist(playlistId: string, trackUri: string) {
+  if (!spotifyApi) {
+    throw new Error('Spotify not authenticated')
+  }
+
+  await spotifyApi.playlists.removeItemsFromPlaylist(playlistId, {
+    tracks: [{ uri: trackUri }],
+  })
+}
+
 /**
  * Get playlist details including name and cover image.
  * @param playlistId - Spotify playlist ID